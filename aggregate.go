@@ -0,0 +1,221 @@
+package simplejson
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// ErrNoValues is returned by Avg, Min and Max when no value is found
+var ErrNoValues = errors.New("simplejson: no values to aggregate")
+
+// MissingPolicy sets how aggregations treat missing and null values
+type MissingPolicy int
+
+const (
+	// SkipMissing ignores missing and null values
+	SkipMissing MissingPolicy = iota
+	// MissingAsZero counts missing and null values as 0
+	MissingAsZero
+	// FailOnMissing returns an error locating the first missing or null value
+	FailOnMissing
+)
+
+// Aggregator computes aggregations over the values of a document
+type Aggregator struct {
+	j      *JSON
+	policy MissingPolicy
+}
+
+// Aggregate returns an Aggregator over `j` treating missing values per `policy`
+func (j *JSON) Aggregate(policy MissingPolicy) *Aggregator {
+	return &Aggregator{j: j, policy: policy}
+}
+
+// Sum returns the sum of the numbers found at `branch`, where a "*" element
+// matches every element of an array or value of an object:
+//
+//	total, err := js.Sum("items", "*", "price")
+//
+// Missing and null values are skipped, see Aggregate for other policies
+func (j *JSON) Sum(branch ...interface{}) (float64, error) {
+	return j.Aggregate(SkipMissing).Sum(branch...)
+}
+
+// Avg is like Sum, returning the mean of the numbers found
+func (j *JSON) Avg(branch ...interface{}) (float64, error) {
+	return j.Aggregate(SkipMissing).Avg(branch...)
+}
+
+// Min is like Sum, returning the smallest number found
+func (j *JSON) Min(branch ...interface{}) (float64, error) {
+	return j.Aggregate(SkipMissing).Min(branch...)
+}
+
+// Max is like Sum, returning the largest number found
+func (j *JSON) Max(branch ...interface{}) (float64, error) {
+	return j.Aggregate(SkipMissing).Max(branch...)
+}
+
+// Count is like Sum, returning the number of values found, of any type
+func (j *JSON) Count(branch ...interface{}) (int, error) {
+	return j.Aggregate(SkipMissing).Count(branch...)
+}
+
+// Sum is like JSON.Sum, with the policy of the Aggregator
+func (a *Aggregator) Sum(branch ...interface{}) (float64, error) {
+	nums, err := a.numbers(branch)
+	if err != nil {
+		return 0, err
+	}
+	f, _ := ratSum(nums).Float64()
+	return f, nil
+}
+
+// Avg is like JSON.Avg, with the policy of the Aggregator
+func (a *Aggregator) Avg(branch ...interface{}) (float64, error) {
+	nums, err := a.numbers(branch)
+	if err != nil {
+		return 0, err
+	}
+	if len(nums) == 0 {
+		return 0, ErrNoValues
+	}
+	sum := ratSum(nums)
+	f, _ := sum.Quo(sum, new(big.Rat).SetInt64(int64(len(nums)))).Float64()
+	return f, nil
+}
+
+// Min is like JSON.Min, with the policy of the Aggregator
+func (a *Aggregator) Min(branch ...interface{}) (float64, error) {
+	return a.extreme(branch, -1)
+}
+
+// Max is like JSON.Max, with the policy of the Aggregator
+func (a *Aggregator) Max(branch ...interface{}) (float64, error) {
+	return a.extreme(branch, 1)
+}
+
+// Count is like JSON.Count, with the policy of the Aggregator
+func (a *Aggregator) Count(branch ...interface{}) (int, error) {
+	vals, err := a.values(branch)
+	return len(vals), err
+}
+
+func (a *Aggregator) extreme(branch []interface{}, sign int) (float64, error) {
+	nums, err := a.numbers(branch)
+	if err != nil {
+		return 0, err
+	}
+	if len(nums) == 0 {
+		return 0, ErrNoValues
+	}
+	best := nums[0]
+	for _, n := range nums[1:] {
+		if n.Cmp(best) == sign {
+			best = n
+		}
+	}
+	f, _ := best.Float64()
+	return f, nil
+}
+
+func ratSum(nums []*big.Rat) *big.Rat {
+	sum := new(big.Rat)
+	for _, n := range nums {
+		sum.Add(sum, n)
+	}
+	return sum
+}
+
+// values returns the values found at `branch`, applying the policy
+func (a *Aggregator) values(branch []interface{}) ([]interface{}, error) {
+	var vals []interface{}
+	var err error
+	globBranch(a.j.Interface(), branch, nil, func(path []interface{}, val interface{}, found bool) {
+		if err != nil {
+			return
+		}
+		if found && val != nil {
+			vals = append(vals, val)
+			return
+		}
+		switch a.policy {
+		case MissingAsZero:
+			vals = append(vals, 0)
+		case FailOnMissing:
+			err = fmt.Errorf("simplejson: no value at %s", formatPath(path))
+		}
+	})
+	return vals, err
+}
+
+// numbers returns the numbers found at `branch`, applying the policy
+func (a *Aggregator) numbers(branch []interface{}) ([]*big.Rat, error) {
+	var nums []*big.Rat
+	var err error
+	globBranch(a.j.Interface(), branch, nil, func(path []interface{}, val interface{}, found bool) {
+		if err != nil {
+			return
+		}
+		if !found || val == nil {
+			switch a.policy {
+			case MissingAsZero:
+				nums = append(nums, new(big.Rat))
+			case FailOnMissing:
+				err = fmt.Errorf("simplejson: no value at %s", formatPath(path))
+			}
+			return
+		}
+		n, ok := numberRat(val)
+		if !ok {
+			err = fmt.Errorf("simplejson: %s is %s, not a number", formatPath(path), jsonType(val))
+			return
+		}
+		nums = append(nums, n)
+	})
+	return nums, err
+}
+
+// globBranch calls `fn` with the values at `branch` within `data`, where a "*"
+// element matches every element of an array or value of an object, in sorted
+// key order. Missing values are reported as not found at the first missing step.
+func globBranch(data interface{}, branch, path []interface{}, fn func(path []interface{}, val interface{}, found bool)) {
+	if len(branch) == 0 {
+		fn(path, data, true)
+		return
+	}
+	p, rest := branch[0], branch[1:]
+	next := func(key, val interface{}) {
+		globBranch(val, rest, append(path[:len(path):len(path)], key), fn)
+	}
+	if p == "*" {
+		switch v := data.(type) {
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				next(key, v[key])
+			}
+			return
+		case []interface{}:
+			for i, val := range v {
+				next(i, val)
+			}
+			return
+		}
+	}
+	val, ok := lookup(data, []interface{}{p})
+	if !ok {
+		fn(append(path[:len(path):len(path)], p), nil, false)
+		return
+	}
+	if i, isIndex := p.(int); isIndex && i < 0 {
+		p = i + len(data.([]interface{}))
+	}
+	next(p, val)
+}
@@ -0,0 +1,47 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestAggregations(t *testing.T) {
+	js, err := NewJSON([]byte(`{"items":[{"price":10.5,"qty":2},{"price":4.5},{"qty":1},{"price":null}],"totals":{"a":1,"b":3}}`))
+	assert.Equal(t, nil, err)
+
+	sum, err := js.Sum("items", "*", "price")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 15.0, sum)
+	avg, _ := js.Avg("items", "*", "price")
+	assert.Equal(t, 7.5, avg)
+	min, _ := js.Min("items", "*", "price")
+	assert.Equal(t, 4.5, min)
+	max, _ := js.Max("totals", "*")
+	assert.Equal(t, 3.0, max)
+	n, _ := js.Count("items", "*", "qty")
+	assert.Equal(t, 2, n)
+	n, _ = js.Count("items", "*")
+	assert.Equal(t, 4, n)
+
+	zero := js.Aggregate(MissingAsZero)
+	avg, _ = zero.Avg("items", "*", "price")
+	assert.Equal(t, 3.75, avg)
+	min, _ = zero.Min("items", "*", "price")
+	assert.Equal(t, 0.0, min)
+	n, _ = zero.Count("items", "*", "price")
+	assert.Equal(t, 4, n)
+
+	_, err = js.Aggregate(FailOnMissing).Sum("items", "*", "price")
+	assert.Equal(t, "simplejson: no value at items[2].price", err.Error())
+	_, err = js.Aggregate(FailOnMissing).Count("missing", "*")
+	assert.Equal(t, "simplejson: no value at missing", err.Error())
+
+	_, err = js.Sum("items")
+	assert.Equal(t, "simplejson: items is array, not a number", err.Error())
+	_, err = js.Avg("missing", "*", "x")
+	assert.Equal(t, ErrNoValues, err)
+	sum, err = js.Sum("items", -4, "price")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 10.5, sum)
+}
@@ -0,0 +1,66 @@
+package simplejson
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ArgsPolicy is how accessors taking an optional default value, such as
+// String, Int and Map, handle being called with more than one
+type ArgsPolicy int32
+
+// Policies for extra default arguments
+const (
+	// PanicOnExtraArgs panics with an error wrapping ErrExtraArgs, the default
+	PanicOnExtraArgs ArgsPolicy = iota
+	// ErrorOnExtraArgs uses the first default and records an error wrapping
+	// ErrExtraArgs, returned by Err
+	ErrorOnExtraArgs
+	// IgnoreExtraArgs uses the first default
+	IgnoreExtraArgs
+)
+
+// ErrExtraArgs is wrapped by the errors reporting an accessor called with
+// more than one default value
+var ErrExtraArgs = errors.New("received too many arguments")
+
+var argsPolicy int32
+
+// SetArgsPolicy sets how every accessor handles being called with more than
+// one default value. It is meant to be set once by the application, when
+// code it does not control may misuse the accessors.
+func SetArgsPolicy(p ArgsPolicy) {
+	atomic.StoreInt32(&argsPolicy, int32(p))
+}
+
+// errBox holds errors in an atomic.Value, which requires a consistent type
+type errBox struct {
+	err error
+}
+
+// Err returns the last error recorded by the accessors of the document `j`
+// belongs to, see ErrorOnExtraArgs, or by its modifications, see SetQuota
+func (j *JSON) Err() error {
+	if j == nil || j.doc == nil {
+		return nil
+	}
+	if b, ok := j.doc.err.Load().(errBox); ok {
+		return b.err
+	}
+	return nil
+}
+
+// extraArgs handles the accessor `name` being called with `n` default values
+func (j *JSON) extraArgs(name string, n int) {
+	switch ArgsPolicy(atomic.LoadInt32(&argsPolicy)) {
+	case IgnoreExtraArgs:
+		return
+	case ErrorOnExtraArgs:
+		if j != nil && j.doc != nil {
+			j.doc.err.Store(errBox{fmt.Errorf("simplejson: %s() %w %d", name, ErrExtraArgs, n)})
+		}
+		return
+	}
+	panic(fmt.Errorf("simplejson: %s() %w %d", name, ErrExtraArgs, n))
+}
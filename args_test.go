@@ -0,0 +1,36 @@
+package simplejson
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestArgsPolicy(t *testing.T) {
+	defer SetArgsPolicy(PanicOnExtraArgs)
+	js := mustJSON(`{"a":"x"}`)
+
+	func() {
+		defer func() {
+			err, _ := recover().(error)
+			assert.Equal(t, true, errors.Is(err, ErrExtraArgs))
+			assert.Equal(t, "simplejson: String() received too many arguments 2", err.Error())
+		}()
+		js.Get("b").String("y", "z")
+	}()
+
+	SetArgsPolicy(ErrorOnExtraArgs)
+	assert.Equal(t, nil, js.Err())
+	assert.Equal(t, "y", js.Get("b").String("y", "z"))
+	assert.Equal(t, "x", js.Get("a").String("y", "z"))
+	assert.Equal(t, 1, js.Get("b").Int(1, 2, 3))
+	assert.Equal(t, true, errors.Is(js.Err(), ErrExtraArgs))
+	assert.Equal(t, "simplejson: Int() received too many arguments 3", js.Get("a").Err().Error())
+
+	SetArgsPolicy(IgnoreExtraArgs)
+	other := mustJSON(`{}`)
+	assert.Equal(t, map[string]interface{}{"k": 1}, other.Get("m").Map(map[string]interface{}{"k": 1}, nil))
+	assert.Equal(t, true, other.Get("b").Bool(true, false))
+	assert.Equal(t, nil, other.Err())
+}
@@ -0,0 +1,100 @@
+package simplejson
+
+// SetIndex modifies the `JSON` array by setting `val` at `index`,
+// negative indexes count back from the end of the array
+func (j *JSON) SetIndex(index int, val interface{}) {
+	j.checkWritable()
+	j.setChild(index, val)
+}
+
+// RemoveIndex modifies the `JSON` array by removing the element at `index`,
+// negative indexes count back from the end of the array
+func (j *JSON) RemoveIndex(index int) {
+	j.checkWritable()
+	j.delChild(index)
+}
+
+// normalizeIndex converts a negative index counting back from the end of an
+// array of `length` elements and reports whether it addresses an element
+func normalizeIndex(index *int, length int) bool {
+	if *index < 0 {
+		*index += length
+	}
+	return *index >= 0 && *index < length
+}
+
+// setChild sets the value at `key` of an object or index of an array
+func (j *JSON) setChild(key interface{}, val interface{}) error {
+	switch k := key.(type) {
+	case string:
+		return j.set(k, val)
+	case int:
+		j.beforeWrite()
+		if a, ok := j.CheckArray(); ok && normalizeIndex(&k, len(a)) {
+			val = j.storable(val, k)
+			old := a[k]
+			a[k] = val
+			return j.notify(ChangeReplace, []interface{}{k}, old, val)
+		}
+	}
+	return nil
+}
+
+// insertChild is like setChild, but inserts values into arrays
+func (j *JSON) insertChild(key interface{}, val interface{}) error {
+	k, ok := key.(int)
+	if !ok {
+		return j.setChild(key, val)
+	}
+	if a, ok := j.CheckArray(); ok && k >= 0 && k <= len(a) {
+		val = j.storable(val, k)
+		n := make([]interface{}, 0, len(a)+1)
+		n = append(append(append(n, a[:k]...), val), a[k:]...)
+		j.setData(n)
+		if err := j.notify(ChangeAdd, []interface{}{k}, nil, val); err != nil {
+			j.setData(a)
+			return err
+		}
+	}
+	return nil
+}
+
+// delChild removes the value at `key` of an object or index of an array
+func (j *JSON) delChild(key interface{}) {
+	switch k := key.(type) {
+	case string:
+		j.Del(k)
+	case int:
+		if a, ok := j.CheckArray(); ok && normalizeIndex(&k, len(a)) {
+			old := a[k]
+			j.setData(append(a[:k:k], a[k+1:]...))
+			j.notify(ChangeRemove, []interface{}{k}, old, nil)
+		}
+	}
+}
+
+// Append modifies the `JSON` array by appending `vals` to it
+func (j *JSON) Append(vals ...interface{}) {
+	j.beforeWrite()
+	a, ok := j.CheckArray()
+	if !ok || len(vals) == 0 {
+		return
+	}
+	n := len(a)
+	if j.doc != nil && j.doc.shared {
+		// the spare capacity of the array may be shared with snapshots
+		a = a[:n:n]
+	}
+	a = append(a, vals...)
+	for i := n; i < len(a); i++ {
+		a[i] = j.storable(a[i], i)
+	}
+	j.setData(a)
+	for i, val := range a[n:] {
+		if err := j.notify(ChangeAdd, []interface{}{n + i}, nil, val); err != nil {
+			// the values from the rejected one on are dropped
+			j.setData(a[: n+i : n+i])
+			return
+		}
+	}
+}
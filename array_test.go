@@ -0,0 +1,54 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestNegativeIndexes(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"items":[1,2,3,{"id":4}]}`))
+
+	assert.Equal(t, 4, js.Get("items", -1, "id").Int())
+	assert.Equal(t, 2, js.Get("items", -3).Int())
+	_, ok := js.CheckGet("items", -5)
+	assert.Equal(t, false, ok)
+
+	items := js.Get("items")
+	items.SetIndex(-1, "last")
+	items.SetIndex(0, "first")
+	items.SetIndex(10, "ignored")
+	assert.Equal(t, `{"items":["first",2,3,"last"]}`, string(mustEncode(t, js)))
+
+	items.RemoveIndex(-2)
+	assert.Equal(t, `{"items":["first",2,"last"]}`, string(mustEncode(t, js)))
+	js.Get("items").RemoveIndex(0)
+	assert.Equal(t, `{"items":[2,"last"]}`, string(mustEncode(t, js)))
+
+	var changes []Change
+	js.OnChange(nil, func(c Change) { changes = append(changes, c) })
+	js.Get("items").SetIndex(-1, 0)
+	assert.Equal(t, []interface{}{"items", 1}, changes[0].Path)
+
+	js.Freeze()
+	assertPanics(t, ErrFrozen, func() { js.Get("items").RemoveIndex(0) })
+}
+
+func TestAppend(t *testing.T) {
+	js := mustJSON(`{"list":[],"a":{"b":[[]]}}`)
+	js.Get("list").Append("x")
+	js.Get("a", "b", 0).Append(1, 2)
+	js.Get("a").Get("b").Append(true)
+	assert.Equal(t, `{"a":{"b":[[1,2],true]},"list":["x"]}`, string(mustEncode(t, js)))
+
+	// unshared arrays grow in place
+	list := js.ArrayAt("big")
+	for i := 0; i < 100; i++ {
+		list.Append(i)
+	}
+	a := list.Array()
+	list.Append(100)
+	assert.T(t, cap(a) > len(a), cap(a))
+	assert.Equal(t, identity(a), identity(list.Array()))
+	assert.Equal(t, 101, len(js.Get("big").Array()))
+}
@@ -0,0 +1,254 @@
+package simplejson
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// binary format version, written as the first byte
+const binaryVersion = 1
+
+// value tags of the binary format
+const (
+	tagNull byte = iota
+	tagFalse
+	tagTrue
+	tagString
+	tagNumber
+	tagInt
+	tagUint
+	tagFloat
+	tagObject
+	tagArray
+	// tagRaw holds values of other types, encoded as JSON by the codec
+	tagRaw
+)
+
+var (
+	errBinaryTruncated    = errors.New("simplejson: truncated binary document")
+	errNilUnmarshalBinary = errors.New("simplejson: UnmarshalBinary on nil pointer")
+)
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+//
+// The document is written in a compact format made of type tagged values and
+// varint lengths, which loads several times faster than JSON text. It is meant
+// for caches and is only guaranteed to be read by UnmarshalBinary of the same
+// major version of this package.
+func (j *JSON) MarshalBinary() ([]byte, error) {
+	buf := bytes.NewBuffer([]byte{binaryVersion})
+	if err := writeBinary(buf, j.Interface()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// decoding documents written by MarshalBinary.
+// Numbers are decoded as json.Number, int64, uint64 or float64 values,
+// depending on how they were held in the encoded document.
+func (j *JSON) UnmarshalBinary(data []byte) error {
+	if j == nil {
+		return errNilUnmarshalBinary
+	}
+	if j.IsFrozen() {
+		return ErrFrozen
+	}
+	if len(data) == 0 {
+		return errBinaryTruncated
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("simplejson: unsupported binary format version %d", data[0])
+	}
+	r := &binaryReader{data: data[1:]}
+	v, err := r.value()
+	if err != nil {
+		return err
+	}
+	if len(r.data) > 0 {
+		return fmt.Errorf("simplejson: %d bytes of trailing data in binary document", len(r.data))
+	}
+	j.document()
+	j.data = v
+	return nil
+}
+
+func writeBinary(buf *bytes.Buffer, data interface{}) error {
+	var scratch [binary.MaxVarintLen64]byte
+	uvarint := func(n uint64) {
+		buf.Write(scratch[:binary.PutUvarint(scratch[:], n)])
+	}
+	str := func(tag byte, s string) {
+		buf.WriteByte(tag)
+		uvarint(uint64(len(s)))
+		buf.WriteString(s)
+	}
+
+	switch v := data.(type) {
+	case nil:
+		buf.WriteByte(tagNull)
+	case bool:
+		if v {
+			buf.WriteByte(tagTrue)
+		} else {
+			buf.WriteByte(tagFalse)
+		}
+	case string:
+		str(tagString, v)
+	case json.Number:
+		str(tagNumber, string(v))
+	case int, int8, int16, int32, int64:
+		buf.WriteByte(tagInt)
+		buf.Write(scratch[:binary.PutVarint(scratch[:], reflect.ValueOf(v).Int())])
+	case uint, uint8, uint16, uint32, uint64:
+		buf.WriteByte(tagUint)
+		uvarint(reflect.ValueOf(v).Uint())
+	case float32, float64:
+		buf.WriteByte(tagFloat)
+		binary.LittleEndian.PutUint64(scratch[:8], math.Float64bits(reflect.ValueOf(v).Float()))
+		buf.Write(scratch[:8])
+	case map[string]interface{}:
+		buf.WriteByte(tagObject)
+		uvarint(uint64(len(v)))
+		for key, val := range v {
+			uvarint(uint64(len(key)))
+			buf.WriteString(key)
+			if err := writeBinary(buf, val); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		buf.WriteByte(tagArray)
+		uvarint(uint64(len(v)))
+		for _, val := range v {
+			if err := writeBinary(buf, val); err != nil {
+				return err
+			}
+		}
+	default:
+		b, err := codec.Marshal(v)
+		if err != nil {
+			return err
+		}
+		str(tagRaw, string(b))
+	}
+	return nil
+}
+
+type binaryReader struct {
+	data []byte
+}
+
+func (r *binaryReader) uvarint() (uint64, error) {
+	n, size := binary.Uvarint(r.data)
+	if size <= 0 {
+		return 0, errBinaryTruncated
+	}
+	r.data = r.data[size:]
+	return n, nil
+}
+
+// length reads a length, which can't exceed the remaining bytes
+// as every element takes at least one
+func (r *binaryReader) length() (int, error) {
+	n, err := r.uvarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(r.data)) {
+		return 0, errBinaryTruncated
+	}
+	return int(n), nil
+}
+
+func (r *binaryReader) string() (string, error) {
+	n, err := r.length()
+	if err != nil {
+		return "", err
+	}
+	s := string(r.data[:n])
+	r.data = r.data[n:]
+	return s, nil
+}
+
+func (r *binaryReader) value() (interface{}, error) {
+	if len(r.data) == 0 {
+		return nil, errBinaryTruncated
+	}
+	tag := r.data[0]
+	r.data = r.data[1:]
+
+	switch tag {
+	case tagNull:
+		return nil, nil
+	case tagFalse:
+		return false, nil
+	case tagTrue:
+		return true, nil
+	case tagString:
+		return r.string()
+	case tagNumber:
+		s, err := r.string()
+		return json.Number(s), err
+	case tagInt:
+		n, size := binary.Varint(r.data)
+		if size <= 0 {
+			return nil, errBinaryTruncated
+		}
+		r.data = r.data[size:]
+		return n, nil
+	case tagUint:
+		return r.uvarint()
+	case tagFloat:
+		if len(r.data) < 8 {
+			return nil, errBinaryTruncated
+		}
+		f := math.Float64frombits(binary.LittleEndian.Uint64(r.data))
+		r.data = r.data[8:]
+		return f, nil
+	case tagObject:
+		n, err := r.length()
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]interface{}, n)
+		for i := 0; i < n; i++ {
+			key, err := r.string()
+			if err != nil {
+				return nil, err
+			}
+			if m[key], err = r.value(); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case tagArray:
+		n, err := r.length()
+		if err != nil {
+			return nil, err
+		}
+		a := make([]interface{}, n)
+		for i := range a {
+			if a[i], err = r.value(); err != nil {
+				return nil, err
+			}
+		}
+		return a, nil
+	case tagRaw:
+		s, err := r.string()
+		if err != nil {
+			return nil, err
+		}
+		var v interface{}
+		dec := codec.NewDecoder(bytes.NewBufferString(s))
+		dec.UseNumber()
+		err = dec.Decode(&v)
+		return v, err
+	}
+	return nil, fmt.Errorf("simplejson: invalid binary value tag %d", tag)
+}
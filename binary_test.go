@@ -0,0 +1,58 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	js, err := NewJSON([]byte(`{"id":12345678901234567890,"name":"x","ok":true,"no":false,"nil":null,
+		"items":[1.5,"a",{"b":[]}],"empty":{}}`))
+	assert.Equal(t, nil, err)
+	js.Set("int", -42)
+	js.Set("uint", uint64(18446744073709551615))
+	js.Set("float", 0.25)
+	js.Set("struct", struct {
+		A int `json:"a"`
+	}{1})
+
+	b, err := js.MarshalBinary()
+	assert.Equal(t, nil, err)
+
+	got := &JSON{}
+	assert.Equal(t, nil, got.UnmarshalBinary(b))
+	assert.Equal(t, json.Number("12345678901234567890"), got.Get("id").Interface())
+	assert.Equal(t, int64(-42), got.Get("int").Interface())
+	assert.Equal(t, uint64(18446744073709551615), got.Get("uint").Interface())
+	assert.Equal(t, 0.25, got.Get("float").Interface())
+	assert.Equal(t, map[string]interface{}{"a": json.Number("1")}, got.Get("struct").Interface())
+	assert.Equal(t, nil, got.Get("nil").Interface())
+	assert.Equal(t, true, deepEqual(js.Get("items").Interface(), got.Get("items").Interface()))
+
+	want, _ := js.Encode()
+	enc, _ := got.Encode()
+	assert.Equal(t, string(want), string(enc))
+
+	got.Set("new", 1)
+	assert.Equal(t, 1, got.Get("new").Int())
+}
+
+func TestBinaryErrors(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"a":["long string value"]}`))
+	b, _ := js.MarshalBinary()
+
+	for i := 0; i < len(b); i++ {
+		assert.NotEqual(t, nil, (&JSON{}).UnmarshalBinary(b[:i]))
+	}
+	assert.Equal(t, "simplejson: unsupported binary format version 9", (&JSON{}).UnmarshalBinary([]byte{9}).Error())
+	assert.Equal(t, "simplejson: invalid binary value tag 99", (&JSON{}).UnmarshalBinary([]byte{binaryVersion, 99}).Error())
+	assert.Equal(t, "simplejson: 1 bytes of trailing data in binary document", (&JSON{}).UnmarshalBinary(append(b, 0)).Error())
+
+	// lengths larger than the input are rejected before allocating
+	assert.Equal(t, errBinaryTruncated, (&JSON{}).UnmarshalBinary([]byte{binaryVersion, tagArray, 0xff, 0xff, 0xff, 0xff, 0x0f}))
+
+	js.Freeze()
+	assert.Equal(t, ErrFrozen, js.UnmarshalBinary(b))
+}
@@ -0,0 +1,259 @@
+package simplejson
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// BindError reports a field that could not be bound
+type BindError struct {
+	Path []interface{}
+	Err  error
+}
+
+func (e *BindError) Error() string {
+	return fmt.Sprintf("%s: %v", formatPath(e.Path), e.Err)
+}
+
+// BindErrors holds every field that could not be bound
+type BindErrors []*BindError
+
+func (e BindErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "simplejson: cannot bind " + strings.Join(msgs, "; ")
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Bind populates the struct pointed to by `v` from the document.
+//
+// Fields are matched to keys as by encoding/json, honoring `json` tags and
+// nested structs. Two more tags are supported:
+//
+//	Port    int           `json:"port" default:"8080"`
+//	Timeout time.Duration `json:"timeout" default:"5s"`
+//	Host    string        `json:"host" required:"true"`
+//
+// `default` is used when the document has no value, or a null value, for
+// the field. It is decoded as JSON, except for strings, durations and
+// types implementing encoding.TextUnmarshaler which are parsed from the
+// text. `required:"true"` fields must be present in the document.
+//
+// Binding carries on past invalid fields, the returned BindErrors report all
+// of them with their path. Durations may be given as strings such as "1m30s".
+func (j *JSON) Bind(v interface{}) error {
+	j = j.orMissing()
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("simplejson: Bind requires a non-nil pointer to a struct, got %T", v)
+	}
+	b := binder{fold: j.doc != nil && j.doc.foldKeys}
+	b.bindStruct(rv.Elem(), j.data, nil)
+	if len(b.errs) > 0 {
+		return b.errs
+	}
+	return nil
+}
+
+type binder struct {
+	fold bool
+	errs BindErrors
+}
+
+func (b *binder) fail(path []interface{}, format string, args ...interface{}) {
+	b.errs = append(b.errs, &BindError{Path: path, Err: fmt.Errorf(format, args...)})
+}
+
+func (b *binder) bindStruct(rv reflect.Value, data interface{}, path []interface{}) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		fv := rv.Field(i)
+
+		// embedded structs are flattened into their parent
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if f.Type.Kind() == reflect.Ptr {
+					if !fv.CanSet() {
+						continue
+					}
+					if fv.IsNil() {
+						fv.Set(reflect.New(ft))
+					}
+					fv = fv.Elem()
+				}
+				b.bindStruct(fv, data, path)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fpath := append(path[:len(path):len(path)], name)
+
+		val, ok := b.field(data, name)
+		if ok && val != nil {
+			b.bindValue(fv, val, fpath)
+			continue
+		}
+		if def, ok := f.Tag.Lookup("default"); ok {
+			if err := parseDefault(fv, def); err != nil {
+				b.fail(fpath, "invalid default %q: %v", def, err)
+			}
+			continue
+		}
+		if f.Tag.Get("required") == "true" {
+			b.fail(fpath, "missing required value")
+			continue
+		}
+		// nested structs may hold defaults and required fields of their own
+		if isBindStruct(fv.Type()) {
+			b.bindStruct(fv, nil, fpath)
+		}
+	}
+}
+
+// field returns the value of `name` in `data`, matching the key
+// case insensitively as encoding/json does if there is no exact match
+func (b *binder) field(data interface{}, name string) (interface{}, bool) {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	if val, ok := m[name]; ok {
+		return val, true
+	}
+	if b.fold {
+		_, val, ok := getKeyFold(m, name)
+		return val, ok
+	}
+	for k, val := range m {
+		if strings.EqualFold(k, name) {
+			return val, true
+		}
+	}
+	return nil, false
+}
+
+func (b *binder) bindValue(fv reflect.Value, val interface{}, path []interface{}) {
+	t := fv.Type()
+	switch {
+	case isBindStruct(t):
+		if _, ok := val.(map[string]interface{}); ok {
+			b.bindStruct(fv, val, path)
+			return
+		}
+	case t.Kind() == reflect.Ptr && isBindStruct(t.Elem()):
+		if _, ok := val.(map[string]interface{}); ok {
+			if fv.IsNil() {
+				fv.Set(reflect.New(t.Elem()))
+			}
+			b.bindStruct(fv.Elem(), val, path)
+			return
+		}
+	case t.Kind() == reflect.Slice:
+		if a, ok := val.([]interface{}); ok {
+			s := reflect.MakeSlice(t, len(a), len(a))
+			for i, elem := range a {
+				b.bindValue(s.Index(i), elem, append(path[:len(path):len(path)], i))
+			}
+			fv.Set(s)
+			return
+		}
+	case t == durationType:
+		if s, ok := val.(string); ok {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				b.fail(path, "invalid duration %q", s)
+				return
+			}
+			fv.SetInt(int64(d))
+			return
+		}
+	}
+
+	raw, err := codec.Marshal(val)
+	if err == nil {
+		err = codec.Unmarshal(raw, fv.Addr().Interface())
+	}
+	if err != nil {
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			b.fail(path, "cannot use %s as %s", jsonType(val), t)
+			return
+		}
+		b.fail(path, "%v", err)
+	}
+}
+
+// parseDefault sets `fv` to the value described by the `default` tag
+func parseDefault(fv reflect.Value, def string) error {
+	if u, ok := fv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(def))
+	}
+	switch {
+	case fv.Kind() == reflect.String:
+		fv.SetString(def)
+		return nil
+	case fv.Type() == durationType:
+		d, err := time.ParseDuration(def)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+	return codec.Unmarshal([]byte(def), fv.Addr().Interface())
+}
+
+// isBindStruct reports whether `t` is a struct whose fields are bound one by
+// one rather than decoded as a whole
+func isBindStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	p := reflect.PtrTo(t)
+	return !p.Implements(jsonUnmarshalerType) && !p.Implements(textUnmarshalerType)
+}
+
+// jsonType returns the name of the JSON type of `val`
+func jsonType(val interface{}) string {
+	switch val.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	}
+	if isNumber(val) {
+		return "number"
+	}
+	return fmt.Sprintf("%T", val)
+}
@@ -0,0 +1,84 @@
+package simplejson
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+type bindTLS struct {
+	Enabled bool   `json:"enabled" default:"true"`
+	Cert    string `json:"cert" required:"true"`
+}
+
+type bindCommon struct {
+	Name string `json:"name" default:"svc"`
+}
+
+type bindConfig struct {
+	bindCommon
+	Host    string        `json:"host" required:"true"`
+	Port    int           `json:"port" default:"8080"`
+	Timeout time.Duration `json:"timeout" default:"5s"`
+	Tags    []string      `json:"tags" default:"[\"a\"]"`
+	TLS     bindTLS       `json:"tls"`
+	Peers   []struct {
+		Addr string `json:"addr" required:"true"`
+		Up   bool   `json:"up"`
+	} `json:"peers"`
+	Ignored string `json:"-"`
+	Started time.Time
+}
+
+func TestBind(t *testing.T) {
+	js, err := NewJSON([]byte(`{
+		"host": "localhost",
+		"timeout": "1m",
+		"tls": {"cert": "c.pem"},
+		"peers": [{"addr": "a"}, {"addr": "b", "up": true}],
+		"Ignored": "x",
+		"started": "2020-01-02T03:04:05Z"
+	}`))
+	assert.Equal(t, nil, err)
+
+	var c bindConfig
+	assert.Equal(t, nil, js.Bind(&c))
+	assert.Equal(t, "svc", c.Name)
+	assert.Equal(t, "localhost", c.Host)
+	assert.Equal(t, 8080, c.Port)
+	assert.Equal(t, time.Minute, c.Timeout)
+	assert.Equal(t, []string{"a"}, c.Tags)
+	assert.Equal(t, bindTLS{Enabled: true, Cert: "c.pem"}, c.TLS)
+	assert.Equal(t, 2, len(c.Peers))
+	assert.Equal(t, true, c.Peers[1].Up)
+	assert.Equal(t, "", c.Ignored)
+	assert.Equal(t, 2020, c.Started.Year())
+}
+
+func TestBindErrors(t *testing.T) {
+	js, err := NewJSON([]byte(`{"port": "x", "timeout": "soon", "peers": [{"up": true}]}`))
+	assert.Equal(t, nil, err)
+
+	var c bindConfig
+	err = js.Bind(&c)
+	errs, ok := err.(BindErrors)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 5, len(errs))
+	assert.Equal(t, "simplejson: cannot bind host: missing required value; "+
+		"port: cannot use string as int; "+
+		"timeout: invalid duration \"soon\"; "+
+		"tls.cert: missing required value; "+
+		"peers[0].addr: missing required value", err.Error())
+	assert.Equal(t, []interface{}{"peers", 0, "addr"}, errs[4].Path)
+
+	assert.NotEqual(t, nil, js.Bind(c))
+}
+
+func TestBindInvalidDefault(t *testing.T) {
+	var c struct {
+		N int `default:"many"`
+	}
+	err := New().Bind(&c)
+	assert.Equal(t, `simplejson: cannot bind N: invalid default "many": invalid character 'm' looking for beginning of value`, err.Error())
+}
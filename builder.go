@@ -0,0 +1,181 @@
+package simplejson
+
+import (
+	"fmt"
+	"math"
+)
+
+// Builder is implemented by ObjectBuilder and ArrayBuilder,
+// it allows builders to be nested into each other
+type Builder interface {
+	build() (interface{}, error)
+}
+
+// ObjectBuilder constructs a JSON object with chained calls:
+//
+//	js := NewObjectBuilder().
+//		Set("id", 1).
+//		Nested("tags", NewArrayBuilder().Add("a", "b")).
+//		Build()
+//
+// Values are validated as they are set, the first error is kept and
+// reported by Err and Build.
+type ObjectBuilder struct {
+	m   map[string]interface{}
+	err error
+}
+
+// NewObjectBuilder returns a builder for an empty object
+func NewObjectBuilder() *ObjectBuilder {
+	return &ObjectBuilder{m: map[string]interface{}{}}
+}
+
+// Set sets `key` to `val`, which must be encodable as JSON.
+// Setting a key twice is an error.
+func (b *ObjectBuilder) Set(key string, val interface{}) *ObjectBuilder {
+	if b.err != nil {
+		return b
+	}
+	if _, ok := b.m[key]; ok {
+		b.err = &BuildError{Path: []interface{}{key}, Err: fmt.Errorf("duplicate key")}
+		return b
+	}
+	v, err := buildValue(val)
+	if err != nil {
+		b.err = wrapBuildError(key, err)
+		return b
+	}
+	b.m[key] = v
+	return b
+}
+
+// Nested sets `key` to the value constructed by `child`
+func (b *ObjectBuilder) Nested(key string, child Builder) *ObjectBuilder {
+	return b.Set(key, child)
+}
+
+// Err returns the first error found while building
+func (b *ObjectBuilder) Err() error {
+	return b.err
+}
+
+// Build returns the constructed document,
+// it panics if an error was found while building
+func (b *ObjectBuilder) Build() *JSON {
+	return mustBuild(b)
+}
+
+func (b *ObjectBuilder) build() (interface{}, error) {
+	return b.m, b.err
+}
+
+// ArrayBuilder constructs a JSON array with chained calls,
+// see ObjectBuilder
+type ArrayBuilder struct {
+	a   []interface{}
+	err error
+}
+
+// NewArrayBuilder returns a builder for an empty array
+func NewArrayBuilder() *ArrayBuilder {
+	return &ArrayBuilder{a: []interface{}{}}
+}
+
+// Add appends `vals`, which must be encodable as JSON
+func (b *ArrayBuilder) Add(vals ...interface{}) *ArrayBuilder {
+	for _, val := range vals {
+		if b.err != nil {
+			return b
+		}
+		v, err := buildValue(val)
+		if err != nil {
+			b.err = wrapBuildError(len(b.a), err)
+			return b
+		}
+		b.a = append(b.a, v)
+	}
+	return b
+}
+
+// Nested appends the value constructed by `child`
+func (b *ArrayBuilder) Nested(child Builder) *ArrayBuilder {
+	return b.Add(child)
+}
+
+// Err returns the first error found while building
+func (b *ArrayBuilder) Err() error {
+	return b.err
+}
+
+// Build returns the constructed document,
+// it panics if an error was found while building
+func (b *ArrayBuilder) Build() *JSON {
+	return mustBuild(b)
+}
+
+func (b *ArrayBuilder) build() (interface{}, error) {
+	return b.a, b.err
+}
+
+// BuildError reports an invalid value given to a builder
+type BuildError struct {
+	Path []interface{}
+	Err  error
+}
+
+func (e *BuildError) Error() string {
+	return fmt.Sprintf("simplejson: cannot build %s: %v", formatPath(e.Path), e.Err)
+}
+
+// Unwrap returns the error of the invalid value
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+func wrapBuildError(key interface{}, err error) error {
+	if be, ok := err.(*BuildError); ok {
+		return &BuildError{Path: append([]interface{}{key}, be.Path...), Err: be.Err}
+	}
+	return &BuildError{Path: []interface{}{key}, Err: err}
+}
+
+func mustBuild(b Builder) *JSON {
+	data, err := b.build()
+	if err != nil {
+		panic(err)
+	}
+	js := &JSON{data: data}
+	js.document()
+	return js
+}
+
+// buildValue validates `val` and returns the value to store in the document
+func buildValue(val interface{}) (interface{}, error) {
+	val, err := convertValue(val)
+	if err != nil {
+		ce := err.(*ConvertError)
+		return nil, &BuildError{Path: ce.Path, Err: fmt.Errorf("cannot convert %s: %w", ce.Type, ce.Err)}
+	}
+	switch v := val.(type) {
+	case Builder:
+		return v.build()
+	case *JSON:
+		return v.data, nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("unsupported number %v", v)
+		}
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return nil, fmt.Errorf("unsupported number %v", v)
+		}
+	case nil, bool, string:
+	default:
+		if !isNumber(v) {
+			if _, err := codec.Marshal(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return val, nil
+}
@@ -0,0 +1,46 @@
+package simplejson
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	js := NewObjectBuilder().
+		Set("id", 1).
+		Set("name", "x").
+		Nested("tags", NewArrayBuilder().Add("a", "b")).
+		Nested("owner", NewObjectBuilder().
+			Set("id", 2).
+			Nested("roles", NewArrayBuilder().Nested(NewObjectBuilder().Set("admin", true)))).
+		Build()
+	assert.Equal(t, `{"id":1,"name":"x","owner":{"id":2,"roles":[{"admin":true}]},"tags":["a","b"]}`,
+		string(mustEncode(t, js)))
+
+	js.Set("added", true)
+	assert.Equal(t, true, js.Get("added").Bool())
+
+	arr := NewArrayBuilder().Add(1, js.Get("tags")).Build()
+	assert.Equal(t, `[1,["a","b"]]`, string(mustEncode(t, arr)))
+}
+
+func TestBuilderErrors(t *testing.T) {
+	b := NewObjectBuilder().Set("a", 1).Set("a", 2)
+	assert.Equal(t, "simplejson: cannot build a: duplicate key", b.Err().Error())
+
+	b = NewObjectBuilder().
+		Nested("items", NewArrayBuilder().Add(1, math.NaN())).
+		Set("later", 1)
+	assert.Equal(t, "simplejson: cannot build items[1]: unsupported number NaN", b.Err().Error())
+
+	a := NewArrayBuilder().Add(make(chan int))
+	assert.NotEqual(t, nil, a.Err())
+	assert.Equal(t, []interface{}{0}, a.Err().(*BuildError).Path)
+
+	defer func() {
+		assert.Equal(t, b.Err(), recover())
+	}()
+	b.Build()
+}
@@ -0,0 +1,29 @@
+package simplejson
+
+// CompareAndSet sets `newVal` at `branch` only if the current value there is
+// equal to `expected`, as the JSON Patch "test" operation does, and reports
+// whether it did. Absent values never match. `expected` may be a *JSON.
+func (j *JSON) CompareAndSet(branch []interface{}, expected, newVal interface{}) (bool, error) {
+	j = j.orMissing()
+	if j.IsFrozen() {
+		return false, ErrFrozen
+	}
+	if e, ok := expected.(*JSON); ok {
+		expected = e.data
+	}
+	current, ok := lookup(j.data, branch)
+	if !ok || !deepEqual(current, expected) {
+		return false, nil
+	}
+	if err := j.setAt(branch, newVal); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CompareAndSet is like JSON.CompareAndSet, performed atomically
+func (s *SyncJSON) CompareAndSet(branch []interface{}, expected, newVal interface{}) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.j.CompareAndSet(branch, expected, newVal)
+}
@@ -0,0 +1,51 @@
+package simplejson
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestCompareAndSet(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"doc":{"version":1,"tags":["a"]}}`))
+
+	ok, err := js.CompareAndSet([]interface{}{"doc", "version"}, 2, 3)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, ok)
+
+	ok, err = js.CompareAndSet([]interface{}{"doc", "version"}, 1.0, 2)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 2, js.Get("doc", "version").Int())
+
+	ok, _ = js.CompareAndSet([]interface{}{"doc", "tags"}, js.Get("doc", "tags"), []interface{}{"b"})
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "b", js.Get("doc", "tags", 0).String())
+
+	ok, _ = js.CompareAndSet([]interface{}{"doc", "missing"}, nil, 1)
+	assert.Equal(t, false, ok)
+}
+
+func TestSyncCompareAndSet(t *testing.T) {
+	s := NewSync(nil)
+	s.Set("n", 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 100; n++ {
+				for {
+					cur := s.Get("n").Int()
+					if ok, _ := s.CompareAndSet([]interface{}{"n"}, cur, cur+1); ok {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 400, s.Get("n").Int())
+}
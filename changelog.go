@@ -0,0 +1,63 @@
+package simplejson
+
+// StartRecording makes the document record every subsequent modification
+// into its change log, see ChangeLog
+func (j *JSON) StartRecording() {
+	if j == nil {
+		return
+	}
+	d := j.document()
+	d.track = true
+	d.recording = true
+}
+
+// StopRecording stops recording modifications, the change log is kept
+func (j *JSON) StopRecording() {
+	if j != nil && j.doc != nil {
+		j.doc.recording = false
+	}
+}
+
+// ResetChangeLog discards the recorded modifications
+func (j *JSON) ResetChangeLog() {
+	if j != nil && j.doc != nil {
+		j.doc.changelog = nil
+		j.doc.undone = nil
+	}
+}
+
+// Changes returns the recorded modifications, oldest first
+func (j *JSON) Changes() []Change {
+	if j == nil || j.doc == nil {
+		return nil
+	}
+	return append([]Change(nil), j.doc.changelog...)
+}
+
+// ChangeLog returns the recorded modifications as a RFC 6902 JSON Patch
+// document, an array of operations such as:
+//
+//	{"op": "replace", "path": "/server/port", "value": 8080}
+func (j *JSON) ChangeLog() *JSON {
+	ops := make([]interface{}, 0, len(j.Changes()))
+	for _, c := range j.Changes() {
+		op := map[string]interface{}{
+			"op":   c.Op.String(),
+			"path": formatPointer(c.Path),
+		}
+		if c.Op != ChangeRemove {
+			op["value"] = c.New
+		}
+		ops = append(ops, op)
+	}
+	return &JSON{data: ops}
+}
+
+// record appends a modification to the change log.
+// Values are copied, so later modifications don't alter the log.
+func (d *document) record(c Change) {
+	c.Old = deepCopy(c.Old)
+	c.New = deepCopy(c.New)
+	d.changelog = append(d.changelog, c)
+	d.undone = nil
+}
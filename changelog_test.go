@@ -0,0 +1,36 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestChangeLog(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"b":1},"c/d":[]}`))
+	assert.Equal(t, nil, err)
+
+	js.Set("before", true)
+	js.StartRecording()
+	js.Get("a").Set("b", 2)
+	js.SetPath([]string{"a", "e~f"}, map[string]interface{}{"g": 1})
+	js.Get("a", "e~f").Set("g", 2)
+	js.Del("c/d")
+	js.StopRecording()
+	js.Set("after", true)
+
+	assert.Equal(t, 4, len(js.Changes()))
+	// recorded values are copies
+	assert.Equal(t, map[string]interface{}{"g": 1}, js.Changes()[1].New)
+
+	b, err := js.ChangeLog().Encode()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `[{"op":"replace","path":"/a/b","value":2},`+
+		`{"op":"add","path":"/a/e~0f","value":{"g":1}},`+
+		`{"op":"replace","path":"/a/e~0f/g","value":2},`+
+		`{"op":"remove","path":"/c~1d"}]`, string(b))
+
+	js.ResetChangeLog()
+	assert.Equal(t, 0, len(js.Changes()))
+	assert.Equal(t, []interface{}{}, js.ChangeLog().Interface())
+}
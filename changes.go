@@ -0,0 +1,161 @@
+package simplejson
+
+// ChangeOp is the kind of modification described by a Change
+type ChangeOp int
+
+// Kinds of modifications
+const (
+	ChangeAdd ChangeOp = iota
+	ChangeReplace
+	ChangeRemove
+)
+
+func (op ChangeOp) String() string {
+	switch op {
+	case ChangeAdd:
+		return "add"
+	case ChangeReplace:
+		return "replace"
+	case ChangeRemove:
+		return "remove"
+	}
+	return "unknown"
+}
+
+// Change describes a modification made to a document
+type Change struct {
+	Op ChangeOp
+	// Path locates the modified value from the root of the document
+	Path []interface{}
+	// Old is the previous value, nil when added
+	Old interface{}
+	// New is the new value, nil when removed
+	New interface{}
+}
+
+type watcher struct {
+	prefix []interface{}
+	fn     func(Change)
+}
+
+// OnChange registers `fn` to be called after every modification made through
+// Set, SetPath, Del and the other mutators that touches the subtree at `prefix`,
+// relative to `j`. Modifications replacing an ancestor of `prefix` are reported too.
+// The returned function unregisters `fn`.
+//
+// Registering a watcher makes the document track the location of the views
+// obtained from it, so Get chains allocate a view per step.
+func (j *JSON) OnChange(prefix []interface{}, fn func(Change)) (remove func()) {
+	if j == nil {
+		return func() {}
+	}
+	d := j.document()
+	d.track = true
+	base, _ := j.location()
+	w := &watcher{prefix: append(append([]interface{}{}, base...), prefix...), fn: fn}
+	d.watchers = append(d.watchers, w)
+
+	return func() {
+		for i, v := range d.watchers {
+			if v == w {
+				d.watchers = append(d.watchers[:i:i], d.watchers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// observed reports whether modifications of the document must be reported
+func (d *document) observed() bool {
+	return d != nil && (len(d.watchers) > 0 || d.recording || d.tracer != nil || d.quota != nil)
+}
+
+// location returns the branch leading from the root of the document to `j`
+func (j *JSON) location() ([]interface{}, bool) {
+	d := j.doc
+	if d == nil || j == d.root {
+		return []interface{}{}, true
+	}
+	if j.parent == nil {
+		if d.frozen {
+			// views of frozen documents may be shared by goroutines,
+			// locate a copy instead
+			c := *j
+			j = &c
+		}
+		if !d.locate(j) {
+			return nil, false
+		}
+	}
+	path, ok := j.parent.location()
+	if !ok {
+		return nil, false
+	}
+	return append(path, j.key), true
+}
+
+// notify reports a modification at `branch`, relative to `j`. A modification
+// exceeding the quota of the document is reverted and not reported, its error
+// is recorded, see Err, and returned
+func (j *JSON) notify(op ChangeOp, branch []interface{}, old, val interface{}) error {
+	if !j.doc.observed() {
+		return nil
+	}
+	base, ok := j.location()
+	if !ok {
+		// detached from the document
+		return nil
+	}
+	c := Change{Op: op, Path: append(base, branch...), Old: old, New: val}
+	if q := j.doc.quota; q != nil {
+		if q.reverting {
+			return nil
+		}
+		if err := j.doc.charge(c); err != nil {
+			j.doc.err.Store(errBox{err})
+			return err
+		}
+	}
+	if j.doc.recording {
+		j.doc.record(c)
+	}
+	if j.doc.tracer != nil {
+		j.doc.tracer.Set(c)
+	}
+
+	for _, w := range j.doc.watchers {
+		if hasPrefix(c.Path, w.prefix) || hasPrefix(w.prefix, c.Path) {
+			w.fn(c)
+		}
+	}
+	return nil
+}
+
+// setOp returns the kind of a modification assigning a value
+func setOp(existed bool) ChangeOp {
+	if existed {
+		return ChangeReplace
+	}
+	return ChangeAdd
+}
+
+func hasPrefix(path, prefix []interface{}) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// stringsBranch converts a SetPath branch
+func stringsBranch(branch []string) []interface{} {
+	b := make([]interface{}, len(branch))
+	for i, key := range branch {
+		b[i] = key
+	}
+	return b
+}
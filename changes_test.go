@@ -0,0 +1,53 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestOnChange(t *testing.T) {
+	js, err := NewJSON([]byte(`{"server":{"port":80,"host":"a"},"log":{"level":"info"}}`))
+	assert.Equal(t, nil, err)
+	early := js.Get("server")
+
+	var server, all []Change
+	remove := js.OnChange([]interface{}{"server"}, func(c Change) { server = append(server, c) })
+	js.OnChange(nil, func(c Change) { all = append(all, c) })
+
+	js.Get("server").Set("port", 8080)
+	js.Get("log").Set("level", "debug")
+	early.Del("host")
+	js.SetPath([]string{"server", "tls", "enabled"}, true)
+	js.Del("missing")
+	js.Set("server", map[string]interface{}{})
+
+	assert.Equal(t, []Change{
+		{Op: ChangeReplace, Path: []interface{}{"server", "port"}, Old: json.Number("80"), New: 8080},
+		{Op: ChangeRemove, Path: []interface{}{"server", "host"}, Old: "a"},
+		{Op: ChangeAdd, Path: []interface{}{"server", "tls"}, New: map[string]interface{}{"enabled": true}},
+		{Op: ChangeReplace, Path: []interface{}{"server"}, Old: map[string]interface{}{
+			"port": 8080, "tls": map[string]interface{}{"enabled": true}}, New: map[string]interface{}{}},
+	}, server)
+	assert.Equal(t, 5, len(all))
+	assert.Equal(t, "replace", all[1].Op.String())
+
+	remove()
+	js.Get("server").Set("port", 1)
+	assert.Equal(t, 4, len(server))
+	assert.Equal(t, 6, len(all))
+}
+
+func TestOnChangeView(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"b":{"c":1}}}`))
+	assert.Equal(t, nil, err)
+
+	var changes []Change
+	js.Get("a").OnChange([]interface{}{"b"}, func(c Change) { changes = append(changes, c) })
+	js.Get("a", "b").Set("c", 2)
+	js.Get("a").Set("d", 1)
+
+	assert.Equal(t, 1, len(changes))
+	assert.Equal(t, []interface{}{"a", "b", "c"}, changes[0].Path)
+}
@@ -0,0 +1,28 @@
+package simplejson
+
+// Clone returns a deep copy of the document, sharing no maps or arrays with it.
+// The copy is always mutable, even when cloning a frozen document.
+func (j *JSON) Clone() *JSON {
+	c := &JSON{data: deepCopy(j.Interface())}
+	c.document()
+	return c
+}
+
+// deepCopy recursively copies maps and arrays, scalars are shared
+func deepCopy(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[key] = deepCopy(val)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, val := range v {
+			a[i] = deepCopy(val)
+		}
+		return a
+	}
+	return data
+}
@@ -0,0 +1,21 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestClone(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"b":[1,{"c":"d"}]}}`))
+	assert.Equal(t, nil, err)
+
+	c := js.Clone()
+	assert.Equal(t, js.Interface(), c.Interface())
+
+	c.Get("a", "b", 1).Set("c", "changed")
+	c.Get("a").Set("e", true)
+	assert.Equal(t, "d", js.Get("a", "b", 1, "c").String())
+	_, ok := js.CheckGet("a", "e")
+	assert.Equal(t, false, ok)
+}
@@ -0,0 +1,66 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Codec is the JSON engine used to encode and decode documents.
+// The standard library is used by default, faster engines exposing the same
+// API (go-json, jsoniter, sonic...) can be plugged in with SetCodec.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	NewDecoder(r io.Reader) StreamDecoder
+}
+
+// StreamDecoder reads successive values from an input stream,
+// it is satisfied by *json.Decoder
+type StreamDecoder interface {
+	UseNumber()
+	Decode(v interface{}) error
+}
+
+// StdCodec is the Codec backed by encoding/json
+type StdCodec struct{}
+
+// Marshal implements Codec using json.Marshal
+func (StdCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements Codec using json.Unmarshal
+func (StdCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// NewDecoder implements Codec using json.NewDecoder
+func (StdCodec) NewDecoder(r io.Reader) StreamDecoder {
+	return json.NewDecoder(r)
+}
+
+// PreservesNumbers implements NumberPreserver,
+// encoding/json writes json.Number values verbatim
+func (StdCodec) PreservesNumbers() bool {
+	return true
+}
+
+// NumberPreserver is implemented by codecs that write json.Number values
+// verbatim and integers without converting them to floats. Documents are
+// encoded by codecs that don't by walking the tree, numbers being written
+// by this package and only values of other types marshaled by the codec.
+type NumberPreserver interface {
+	PreservesNumbers() bool
+}
+
+var codec Codec = StdCodec{}
+
+// SetCodec replaces the package level JSON engine, a nil codec restores the default.
+// It is meant to be called once during program initialization,
+// before any document is decoded or encoded.
+func SetCodec(c Codec) {
+	if c == nil {
+		c = StdCodec{}
+	}
+	codec = c
+}
@@ -0,0 +1,46 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+type countingCodec struct {
+	StdCodec
+	marshals, decoders int
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals++
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) NewDecoder(r io.Reader) StreamDecoder {
+	c.decoders++
+	return json.NewDecoder(r)
+}
+
+func TestSetCodec(t *testing.T) {
+	c := &countingCodec{}
+	SetCodec(c)
+	defer SetCodec(nil)
+
+	js, err := NewJSON([]byte(`{"a":[1,2]}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, c.decoders)
+
+	b, err := js.Encode()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"a":[1,2]}`, string(b))
+
+	b, err = js.EncodePretty()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "{\n  \"a\": [\n    1,\n    2\n  ]\n}", string(b))
+	assert.Equal(t, 2, c.marshals)
+
+	SetCodec(nil)
+	assert.Equal(t, StdCodec{}, codec)
+}
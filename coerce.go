@@ -0,0 +1,206 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Coercion is the type a value is converted to by CoerceTypes
+type Coercion int
+
+// Coercions applied by CoerceTypes
+const (
+	// CoerceNumber converts numeric strings, and booleans to 1 or 0
+	CoerceNumber Coercion = iota + 1
+	// CoerceBool converts the numbers 1 and 0 and strings such as "true",
+	// "false", "1" or "0"
+	CoerceBool
+	// CoerceString converts numbers and booleans to their JSON text
+	CoerceString
+	// CoerceTime converts Unix epoch seconds, numbers or numeric strings,
+	// to RFC 3339 strings in UTC, RFC 3339 strings are kept as is
+	CoerceTime
+)
+
+func (c Coercion) String() string {
+	switch c {
+	case CoerceNumber:
+		return "number"
+	case CoerceBool:
+		return "bool"
+	case CoerceString:
+		return "string"
+	case CoerceTime:
+		return "time"
+	}
+	return "unknown"
+}
+
+// CoerceTypes converts the values found at the dotted paths of `types` once
+// decoded, normalizing documents from producers that are loose about types.
+// A `*` path segment matches every key of an object or element of an array,
+// missing paths and null values are ignored and decoding fails when a value
+// cannot be converted:
+//
+//	js, err := NewJSONWith(body, CoerceTypes(map[string]Coercion{
+//		"items.*.price":  CoerceNumber,
+//		"items.*.active": CoerceBool,
+//		"created":        CoerceTime,
+//	}))
+func CoerceTypes(types map[string]Coercion) DecodeOption {
+	return func(c *decodeConfig) {
+		c.coerce = types
+	}
+}
+
+// CoerceLocale makes CoerceNumber accept numeric strings written with the
+// `decimal` separator and grouped into thousands by `group`, such as
+// "1.234,56" with CoerceLocale(',', '.'), instead of JSON numbers.
+// Groups must be of three digits.
+func CoerceLocale(decimal, group rune) DecodeOption {
+	return func(c *decodeConfig) {
+		c.locale = &numberLocale{decimal: decimal, group: group}
+	}
+}
+
+// numberLocale holds the separators of numbers set by CoerceLocale
+type numberLocale struct {
+	decimal, group rune
+}
+
+// parse returns the JSON number written as `s` in the locale
+func (l *numberLocale) parse(s string) (string, bool) {
+	var sign string
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac := s, ""
+	i := strings.IndexRune(s, l.decimal)
+	if i >= 0 {
+		whole, frac = s[:i], s[i+utf8.RuneLen(l.decimal):]
+	}
+	if groups := strings.Split(whole, string(l.group)); len(groups) > 1 {
+		for i, g := range groups {
+			if len(g) != 3 && (i > 0 || len(g) == 0 || len(g) > 3) {
+				return "", false
+			}
+		}
+		whole = strings.Join(groups, "")
+	}
+	n := sign + whole
+	if i >= 0 {
+		n += "." + frac
+	}
+	return n, isValidNumber(n)
+}
+
+// coerceTypes applies the coercions of `types` to `data`, returning the result,
+// numeric strings are read in `locale` if not nil
+func coerceTypes(data interface{}, types map[string]Coercion, locale *numberLocale) (interface{}, error) {
+	paths := make([]string, 0, len(types))
+	for path := range types {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, branch := range globBranches(data, path) {
+			val, _ := lookup(data, branch)
+			if val == nil {
+				continue
+			}
+			to, ok := coerce(val, types[path], locale)
+			if !ok {
+				return nil, fmt.Errorf("simplejson: cannot coerce %s at %s to %s",
+					jsonType(val), formatPath(branch), types[path])
+			}
+			if len(branch) == 0 {
+				data = to
+				continue
+			}
+			switch parent, _ := lookup(data, branch[:len(branch)-1]); p := parent.(type) {
+			case map[string]interface{}:
+				p[branch[len(branch)-1].(string)] = to
+			case []interface{}:
+				p[branch[len(branch)-1].(int)] = to
+			}
+		}
+	}
+	return data, nil
+}
+
+// coerce converts the scalar `val` according to `c`
+func coerce(val interface{}, c Coercion, locale *numberLocale) (interface{}, bool) {
+	switch c {
+	case CoerceNumber:
+		switch v := val.(type) {
+		case json.Number:
+			return v, true
+		case string:
+			s, ok := strings.TrimSpace(v), false
+			if locale != nil {
+				s, ok = locale.parse(s)
+			} else {
+				ok = isValidNumber(s)
+			}
+			if ok {
+				return json.Number(s), true
+			}
+		case bool:
+			if v {
+				return json.Number("1"), true
+			}
+			return json.Number("0"), true
+		}
+
+	case CoerceBool:
+		switch v := val.(type) {
+		case bool:
+			return v, true
+		case json.Number:
+			switch f, err := v.Float64(); {
+			case err == nil && f == 1:
+				return true, true
+			case err == nil && f == 0:
+				return false, true
+			}
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				return b, true
+			}
+		}
+
+	case CoerceString:
+		switch v := val.(type) {
+		case string:
+			return v, true
+		case json.Number:
+			return v.String(), true
+		case bool:
+			return strconv.FormatBool(v), true
+		}
+
+	case CoerceTime:
+		var epoch string
+		switch v := val.(type) {
+		case json.Number:
+			epoch = v.String()
+		case string:
+			if _, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return v, true
+			}
+			epoch = strings.TrimSpace(v)
+		}
+		if f, err := strconv.ParseFloat(epoch, 64); err == nil && isValidNumber(epoch) {
+			sec := math.Floor(f)
+			nsec := math.Round((f - sec) * 1e9)
+			return time.Unix(int64(sec), int64(nsec)).UTC().Format(time.RFC3339Nano), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,76 @@
+package simplejson
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestCoerceTypes(t *testing.T) {
+	types := map[string]Coercion{
+		"items.*.price":  CoerceNumber,
+		"items.*.active": CoerceBool,
+		"items.*.sku":    CoerceString,
+		"created":        CoerceTime,
+		"updated":        CoerceTime,
+		"missing.path":   CoerceNumber,
+	}
+	body := `{"created":1700000000.5,"updated":"2023-11-14T22:13:20Z","items":[
+		{"price":" 12.50","active":1,"sku":1234},
+		{"price":3,"active":"false","sku":null},
+		{"price":true,"active":true}]}`
+
+	js, err := NewJSONWith([]byte(body), CoerceTypes(types))
+	assert.Equal(t, nil, err)
+	b, _ := js.Get("items").Encode()
+	assert.Equal(t, `[{"active":true,"price":12.50,"sku":"1234"},{"active":false,"price":3,"sku":null},{"active":true,"price":1}]`, string(b))
+	assert.Equal(t, "2023-11-14T22:13:20.5Z", js.Get("created").String())
+	assert.Equal(t, "2023-11-14T22:13:20Z", js.Get("updated").String())
+
+	js, err = NewFromReaderWith(strings.NewReader(`"1700000000"`), CoerceTypes(map[string]Coercion{"": CoerceTime}))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "2023-11-14T22:13:20Z", js.String())
+
+	dec := NewDecoder(bytes.NewBufferString(`{"n":"1"} {"n":"2"}`), CoerceTypes(map[string]Coercion{"n": CoerceNumber}))
+	for i := 1; dec.More(); i++ {
+		js, err := dec.Decode()
+		assert.Equal(t, nil, err)
+		assert.Equal(t, i, js.Get("n").Int())
+	}
+
+	for _, tc := range []struct {
+		body string
+		c    Coercion
+	}{
+		{`{"v":"abc"}`, CoerceNumber},
+		{`{"v":2}`, CoerceBool},
+		{`{"v":{}}`, CoerceString},
+		{`{"v":"yesterday"}`, CoerceTime},
+		{`{"v":"NaN"}`, CoerceTime},
+	} {
+		_, err := NewJSONWith([]byte(tc.body), CoerceTypes(map[string]Coercion{"v": tc.c}))
+		assert.NotEqual(t, nil, err, tc.body)
+	}
+	_, err = NewJSONWith([]byte(`{"a":[1,"x"]}`), CoerceTypes(map[string]Coercion{"a.*": CoerceBool}))
+	assert.Equal(t, `simplejson: cannot coerce string at a[1] to bool`, err.Error())
+}
+
+func TestCoerceLocale(t *testing.T) {
+	types := CoerceTypes(map[string]Coercion{"*": CoerceNumber})
+	js, err := NewJSONWith([]byte(`{"a":"1.234,56","b":"-12,5","c":"1.234.567","d":"0,5","e":"7"}`), types, CoerceLocale(',', '.'))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"a":1234.56,"b":-12.5,"c":1234567,"d":0.5,"e":7}`, string(mustEncode(t, js)))
+
+	js, err = NewJSONWith([]byte(`{"a":"1 234.5"}`), types, CoerceLocale('.', ' '))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"a":1234.5}`, string(mustEncode(t, js)))
+
+	for _, s := range []string{"1.23,4", "12.34", "1234.5", ".123", "1.234,5,6", "1,2.3", "1.234,"} {
+		_, err := NewJSONWith([]byte(`{"a":"`+s+`"}`), types, CoerceLocale(',', '.'))
+		assert.NotEqual(t, nil, err, s)
+	}
+	_, err = NewJSONWith([]byte(`{"a":"1.234,56"}`), types)
+	assert.NotEqual(t, nil, err)
+}
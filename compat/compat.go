@@ -0,0 +1,374 @@
+// Package compat exposes the API of github.com/bitly/go-simplejson on top of
+// this package, so code written against it can be migrated by changing the
+// import path only:
+//
+//	import simplejson "github.com/brunotm/go-simplejson/compat"
+//
+//	js, err := simplejson.NewJson(body)
+//	name := js.GetPath("user", "name").MustString()
+//
+// The underlying document is available through JSON, which allows call
+// sites to move to the native API one at a time.
+package compat
+
+import (
+	"errors"
+	"io"
+	"log"
+
+	simplejson "github.com/brunotm/go-simplejson"
+)
+
+// Json wraps a simplejson document with the bitly/go-simplejson methods
+type Json struct {
+	js *simplejson.JSON
+}
+
+// Version returns the version of the underlying implementation
+func Version() string {
+	return simplejson.Version()
+}
+
+// NewJson returns a pointer to a new `Json` object after unmarshaling `body` bytes
+func NewJson(body []byte) (*Json, error) {
+	js, err := simplejson.NewJSON(body)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(js), nil
+}
+
+// NewFromReader returns a *Json by decoding from an io.Reader
+func NewFromReader(r io.Reader) (*Json, error) {
+	js, err := simplejson.NewFromReader(r)
+	return Wrap(js), err
+}
+
+// New returns a pointer to a new, empty `Json` object
+func New() *Json {
+	return Wrap(simplejson.New())
+}
+
+// Wrap returns a `Json` for a native document
+func Wrap(js *simplejson.JSON) *Json {
+	return &Json{js: js}
+}
+
+// JSON returns the native document wrapped by `j`
+func (j *Json) JSON() *simplejson.JSON {
+	return j.js
+}
+
+// Interface returns the underlying data
+func (j *Json) Interface() interface{} {
+	return j.js.Interface()
+}
+
+// Encode returns its marshaled data as `[]byte`
+func (j *Json) Encode() ([]byte, error) {
+	return j.js.Encode()
+}
+
+// EncodePretty returns its marshaled data as `[]byte` with indentation
+func (j *Json) EncodePretty() ([]byte, error) {
+	return j.js.EncodePretty()
+}
+
+// Implements the json.Marshaler interface.
+func (j *Json) MarshalJSON() ([]byte, error) {
+	return j.js.MarshalJSON()
+}
+
+// Implements the json.Unmarshaler interface.
+func (j *Json) UnmarshalJSON(p []byte) error {
+	if j.js == nil {
+		j.js = &simplejson.JSON{}
+	}
+	return j.js.UnmarshalJSON(p)
+}
+
+// Set modifies `Json` map by `key` and `value`
+func (j *Json) Set(key string, val interface{}) {
+	j.js.Set(key, val)
+}
+
+// SetPath modifies `Json`, recursively checking/creating map keys for the supplied path,
+// and then finally writing in the value
+func (j *Json) SetPath(branch []string, val interface{}) {
+	j.js.SetPath(branch, val)
+}
+
+// Del modifies `Json` map by deleting `key` if it is present.
+func (j *Json) Del(key string) {
+	j.js.Del(key)
+}
+
+// Get returns a pointer to a new `Json` object for `key` in its `map`
+// representation
+func (j *Json) Get(key string) *Json {
+	return Wrap(j.js.Get(key))
+}
+
+// GetPath searches for the item as specified by the branch
+// without the need to deep dive using Get()'s.
+func (j *Json) GetPath(branch ...string) *Json {
+	path := make([]interface{}, len(branch))
+	for i, key := range branch {
+		path[i] = key
+	}
+	return Wrap(j.js.Get(path...))
+}
+
+// GetIndex returns a pointer to a new `Json` object for `index` in its `array`
+// representation, negative indexes count back from the end of the array
+func (j *Json) GetIndex(index int) *Json {
+	return Wrap(j.js.Get(index))
+}
+
+// CheckGet returns a pointer to a new `Json` object and
+// a `bool` identifying success or failure
+func (j *Json) CheckGet(key string) (*Json, bool) {
+	js, ok := j.js.CheckGet(key)
+	if !ok {
+		return nil, false
+	}
+	return Wrap(js), true
+}
+
+// Map type asserts to `map`
+func (j *Json) Map() (map[string]interface{}, error) {
+	if m, ok := j.js.CheckMap(); ok {
+		return m, nil
+	}
+	return nil, errors.New("type assertion to map[string]interface{} failed")
+}
+
+// Array type asserts to an `array`
+func (j *Json) Array() ([]interface{}, error) {
+	if a, ok := j.js.CheckArray(); ok {
+		return a, nil
+	}
+	return nil, errors.New("type assertion to []interface{} failed")
+}
+
+// Bool type asserts to `bool`
+func (j *Json) Bool() (bool, error) {
+	if b, ok := j.js.CheckBool(); ok {
+		return b, nil
+	}
+	return false, errors.New("type assertion to bool failed")
+}
+
+// String type asserts to `string`
+func (j *Json) String() (string, error) {
+	if s, ok := j.js.CheckString(); ok {
+		return s, nil
+	}
+	return "", errors.New("type assertion to string failed")
+}
+
+// Bytes type asserts to `[]byte`
+func (j *Json) Bytes() ([]byte, error) {
+	if s, ok := j.js.CheckString(); ok {
+		return []byte(s), nil
+	}
+	return nil, errors.New("type assertion to []byte failed")
+}
+
+// StringArray type asserts to an `array` of `string`,
+// null elements become empty strings
+func (j *Json) StringArray() ([]string, error) {
+	arr, err := j.Array()
+	if err != nil {
+		return nil, err
+	}
+	retArr := make([]string, 0, len(arr))
+	for _, a := range arr {
+		if a == nil {
+			retArr = append(retArr, "")
+			continue
+		}
+		s, ok := a.(string)
+		if !ok {
+			return nil, errors.New("type assertion to []string failed")
+		}
+		retArr = append(retArr, s)
+	}
+	return retArr, nil
+}
+
+// Float64 coerces into a float64
+func (j *Json) Float64() (float64, error) {
+	if f, ok := j.js.CheckFloat64(); ok {
+		return f, nil
+	}
+	return 0, errors.New("invalid value type")
+}
+
+// Int coerces into an int
+func (j *Json) Int() (int, error) {
+	if i, ok := j.js.CheckInt(); ok {
+		return i, nil
+	}
+	return 0, errors.New("invalid value type")
+}
+
+// Int64 coerces into an int64
+func (j *Json) Int64() (int64, error) {
+	if i, ok := j.js.CheckInt64(); ok {
+		return i, nil
+	}
+	return 0, errors.New("invalid value type")
+}
+
+// Uint64 coerces into an uint64
+func (j *Json) Uint64() (uint64, error) {
+	if i, ok := j.js.CheckUint64(); ok {
+		return i, nil
+	}
+	return 0, errors.New("invalid value type")
+}
+
+// MustArray guarantees the return of a `[]interface{}` (with optional default)
+func (j *Json) MustArray(args ...[]interface{}) []interface{} {
+	var def []interface{}
+	switch len(args) {
+	case 0:
+	case 1:
+		def = args[0]
+	default:
+		log.Panicf("MustArray() received too many arguments %d", len(args))
+	}
+	if a, err := j.Array(); err == nil {
+		return a
+	}
+	return def
+}
+
+// MustMap guarantees the return of a `map[string]interface{}` (with optional default)
+func (j *Json) MustMap(args ...map[string]interface{}) map[string]interface{} {
+	var def map[string]interface{}
+	switch len(args) {
+	case 0:
+	case 1:
+		def = args[0]
+	default:
+		log.Panicf("MustMap() received too many arguments %d", len(args))
+	}
+	if m, err := j.Map(); err == nil {
+		return m
+	}
+	return def
+}
+
+// MustString guarantees the return of a `string` (with optional default)
+func (j *Json) MustString(args ...string) string {
+	var def string
+	switch len(args) {
+	case 0:
+	case 1:
+		def = args[0]
+	default:
+		log.Panicf("MustString() received too many arguments %d", len(args))
+	}
+	if s, err := j.String(); err == nil {
+		return s
+	}
+	return def
+}
+
+// MustStringArray guarantees the return of a `[]string` (with optional default)
+func (j *Json) MustStringArray(args ...[]string) []string {
+	var def []string
+	switch len(args) {
+	case 0:
+	case 1:
+		def = args[0]
+	default:
+		log.Panicf("MustStringArray() received too many arguments %d", len(args))
+	}
+	if a, err := j.StringArray(); err == nil {
+		return a
+	}
+	return def
+}
+
+// MustInt guarantees the return of an `int` (with optional default)
+func (j *Json) MustInt(args ...int) int {
+	var def int
+	switch len(args) {
+	case 0:
+	case 1:
+		def = args[0]
+	default:
+		log.Panicf("MustInt() received too many arguments %d", len(args))
+	}
+	if i, err := j.Int(); err == nil {
+		return i
+	}
+	return def
+}
+
+// MustFloat64 guarantees the return of a `float64` (with optional default)
+func (j *Json) MustFloat64(args ...float64) float64 {
+	var def float64
+	switch len(args) {
+	case 0:
+	case 1:
+		def = args[0]
+	default:
+		log.Panicf("MustFloat64() received too many arguments %d", len(args))
+	}
+	if f, err := j.Float64(); err == nil {
+		return f
+	}
+	return def
+}
+
+// MustBool guarantees the return of a `bool` (with optional default)
+func (j *Json) MustBool(args ...bool) bool {
+	var def bool
+	switch len(args) {
+	case 0:
+	case 1:
+		def = args[0]
+	default:
+		log.Panicf("MustBool() received too many arguments %d", len(args))
+	}
+	if b, err := j.Bool(); err == nil {
+		return b
+	}
+	return def
+}
+
+// MustInt64 guarantees the return of an `int64` (with optional default)
+func (j *Json) MustInt64(args ...int64) int64 {
+	var def int64
+	switch len(args) {
+	case 0:
+	case 1:
+		def = args[0]
+	default:
+		log.Panicf("MustInt64() received too many arguments %d", len(args))
+	}
+	if i, err := j.Int64(); err == nil {
+		return i
+	}
+	return def
+}
+
+// MustUint64 guarantees the return of an `uint64` (with optional default)
+func (j *Json) MustUint64(args ...uint64) uint64 {
+	var def uint64
+	switch len(args) {
+	case 0:
+	case 1:
+		def = args[0]
+	default:
+		log.Panicf("MustUint64() received too many arguments %d", len(args))
+	}
+	if i, err := j.Uint64(); err == nil {
+		return i
+	}
+	return def
+}
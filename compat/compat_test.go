@@ -0,0 +1,101 @@
+package compat
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestCompat(t *testing.T) {
+	js, err := NewJson([]byte(`{
+		"test": {
+			"string_array": ["asdf", "ghjk", "zxcv"],
+			"string_array_null": ["abc", null, "efg"],
+			"array": [1, "2", 3],
+			"arraywithsubs": [{"subkeyone": 1}, {"subkeytwo": 2, "subkeythree": 3}],
+			"int": 10,
+			"float": 5.150,
+			"string": "simplejson",
+			"bool": true,
+			"big": 9223372036854775807
+		}
+	}`))
+	assert.Equal(t, nil, err)
+
+	_, ok := js.CheckGet("test")
+	assert.Equal(t, true, ok)
+	_, ok = js.CheckGet("missing_key")
+	assert.Equal(t, false, ok)
+
+	aws := js.Get("test").Get("arraywithsubs")
+	assert.Equal(t, 1, aws.GetIndex(0).Get("subkeyone").MustInt())
+	assert.Equal(t, 3, aws.GetIndex(1).Get("subkeythree").MustInt())
+	assert.Equal(t, 0, aws.GetIndex(5).MustInt())
+
+	i, err := js.GetPath("test", "int").Int()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 10, i)
+	_, err = js.GetPath("test", "string").Int()
+	assert.Equal(t, "invalid value type", err.Error())
+
+	f, _ := js.GetPath("test", "float").Float64()
+	assert.Equal(t, 5.150, f)
+	assert.Equal(t, int64(9223372036854775807), js.GetPath("test", "big").MustInt64())
+	assert.Equal(t, uint64(10), js.GetPath("test", "int").MustUint64())
+
+	s, _ := js.GetPath("test", "string").String()
+	assert.Equal(t, "simplejson", s)
+	_, err = js.GetPath("test", "int").String()
+	assert.Equal(t, "type assertion to string failed", err.Error())
+	b, _ := js.GetPath("test", "string").Bytes()
+	assert.Equal(t, []byte("simplejson"), b)
+
+	assert.Equal(t, true, js.GetPath("test", "bool").MustBool())
+	assert.Equal(t, "fyea", js.GetPath("test", "missing").MustString("fyea"))
+	assert.Equal(t, 5150, js.GetPath("test", "missing").MustInt(5150))
+	assert.Equal(t, 1.5, js.GetPath("test", "missing").MustFloat64(1.5))
+	assert.Equal(t, []interface{}{"1"}, js.Get("missing").MustArray([]interface{}{"1"}))
+	assert.Equal(t, map[string]interface{}{"a": 1}, js.Get("missing").MustMap(map[string]interface{}{"a": 1}))
+	assert.Equal(t, 9, len(js.Get("test").MustMap()))
+
+	assert.Equal(t, []string{"asdf", "ghjk", "zxcv"}, js.GetPath("test", "string_array").MustStringArray())
+	assert.Equal(t, []string{"abc", "", "efg"}, js.GetPath("test", "string_array_null").MustStringArray())
+	_, err = js.GetPath("test", "array").StringArray()
+	assert.Equal(t, "type assertion to []string failed", err.Error())
+	assert.Equal(t, []string{"x"}, js.GetPath("test", "array").MustStringArray([]string{"x"}))
+
+	js.Set("float2", 300.0)
+	assert.Equal(t, 300.0, js.Get("float2").MustFloat64())
+	js.Del("float2")
+	_, ok = js.CheckGet("float2")
+	assert.Equal(t, false, ok)
+	js.SetPath([]string{"foo", "bar"}, "baz")
+	assert.Equal(t, "baz", js.GetPath("foo", "bar").MustString())
+	assert.Equal(t, "baz", js.JSON().Get("foo", "bar").String())
+
+	defer func() {
+		assert.NotEqual(t, nil, recover())
+	}()
+	js.Get("test").MustInt(1, 2)
+}
+
+func TestCompatStdlibInterfaces(t *testing.T) {
+	val := new(struct {
+		Name   string `json:"name"`
+		Params *Json  `json:"params"`
+	})
+	assert.Equal(t, nil, json.Unmarshal([]byte(`{"name":"x","params":{"a":1}}`), val))
+	assert.Equal(t, 1, val.Params.Get("a").MustInt())
+
+	p, err := json.Marshal(val)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"name":"x","params":{"a":1}}`, string(p))
+
+	js, err := NewFromReader(bytes.NewBufferString(`{"a":[1]}`))
+	assert.Equal(t, nil, err)
+	enc, _ := js.Encode()
+	assert.Equal(t, `{"a":[1]}`, string(enc))
+	assert.Equal(t, map[string]interface{}{}, New().Interface())
+}
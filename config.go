@@ -0,0 +1,376 @@
+package simplejson
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/brunotm/go-simplejson/scanner"
+)
+
+// Source is a layer of configuration applied by Load
+type Source interface {
+	// Apply overlays the layer onto `j`
+	Apply(j *JSON) error
+	// String describes the layer, it is reported by Origin
+	String() string
+}
+
+// Provenance tells where a value of a document built by Load was set
+type Provenance struct {
+	// Source is the layer that set the value
+	Source Source
+	// Line is the line of the value within the document of file and
+	// reader layers, 0 if unknown
+	Line int
+	// Name is the environment variable or the key of the override
+	// that set the value, empty if unknown
+	Name string
+}
+
+// String describes the provenance, such as `file /etc/app.json:12`
+// or `env APP_* (APP_SERVER__PORT)`
+func (p Provenance) String() string {
+	if p.Source == nil {
+		return "unknown"
+	}
+	s := p.Source.String()
+	if p.Line > 0 {
+		s += ":" + strconv.Itoa(p.Line)
+	}
+	if p.Name != "" {
+		s += " (" + p.Name + ")"
+	}
+	return s
+}
+
+// locatingSource is implemented by layers able to tell where, within
+// themselves, they set the value at `path` during their last Apply
+type locatingSource interface {
+	locate(path string) (line int, name string)
+}
+
+// Load builds a document by applying `sources` in order, later layers
+// overriding earlier ones, and records which layer set every value,
+// see Origin and Provenance:
+//
+//	js, err := Load(
+//		FileSource("/etc/app/defaults.json"),
+//		FileSource("/etc/app/config.json"),
+//		EnvSource("APP_", "__"),
+//		OverrideSource("flags", map[string]interface{}{"log.level": "debug"}),
+//	)
+func Load(sources ...Source) (*JSON, error) {
+	j := New()
+	origins := make(map[string]Provenance)
+	before := leaves(j.data)
+
+	for _, src := range sources {
+		if err := src.Apply(j); err != nil {
+			return nil, fmt.Errorf("simplejson: loading %s: %w", src, err)
+		}
+		after := leaves(j.data)
+		for path, val := range after {
+			if old, ok := before[path]; !ok || !deepEqual(old, val) {
+				p := Provenance{Source: src}
+				if ls, ok := src.(locatingSource); ok {
+					p.Line, p.Name = ls.locate(path)
+				}
+				origins[path] = p
+			}
+		}
+		for path := range origins {
+			if _, ok := after[path]; !ok {
+				delete(origins, path)
+			}
+		}
+		before = after
+	}
+
+	j.document().origins = origins
+	j.doc.layers = sources
+	return j, nil
+}
+
+// Origin returns the layer that set the value at the dotted `path`
+// of a document built by Load, or nil if unknown. Objects and arrays
+// report the last layer that set any of their values.
+func (j *JSON) Origin(path string) Source {
+	if j == nil || j.doc == nil || j.doc.origins == nil {
+		return nil
+	}
+	p, _ := j.Provenance(path)
+	return p.Source
+}
+
+// Provenance is like Origin, also reporting where the layer set the value
+// at the dotted `path`. Objects and arrays report the layer only.
+func (j *JSON) Provenance(path string) (Provenance, bool) {
+	if j == nil || j.doc == nil || j.doc.origins == nil {
+		return Provenance{}, false
+	}
+	if p, ok := j.doc.origins[path]; ok {
+		return p, true
+	}
+	var (
+		found Source
+		rank  = -1
+	)
+	for p, origin := range j.doc.origins {
+		if path == "" || strings.HasPrefix(p, path+".") {
+			if r := j.doc.sourceRank(origin.Source); r > rank {
+				found, rank = origin.Source, r
+			}
+		}
+	}
+	return Provenance{Source: found}, found != nil
+}
+
+// sourceRank orders sources by their position in the layers
+func (d *document) sourceRank(src Source) int {
+	rank := 0
+	for _, s := range d.layers {
+		if s == src {
+			return rank
+		}
+		rank++
+	}
+	return rank
+}
+
+// leaves returns the scalar values and empty containers of `data` by dotted path
+func leaves(data interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	var walk func(prefix string, v interface{})
+	walk = func(prefix string, v interface{}) {
+		join := func(key string) string {
+			if prefix == "" {
+				return key
+			}
+			return prefix + "." + key
+		}
+		switch c := v.(type) {
+		case map[string]interface{}:
+			if len(c) > 0 {
+				for key, val := range c {
+					walk(join(key), val)
+				}
+				return
+			}
+		case []interface{}:
+			if len(c) > 0 {
+				for i, val := range c {
+					walk(join(strconv.Itoa(i)), val)
+				}
+				return
+			}
+		}
+		if prefix != "" {
+			out[prefix] = v
+		}
+	}
+	walk("", data)
+	return out
+}
+
+type fileSource struct {
+	path  string
+	lines map[string]int
+}
+
+// FileSource is a layer merged from the JSON file at `path`
+func FileSource(path string) Source {
+	return &fileSource{path: path}
+}
+
+func (f *fileSource) String() string {
+	return "file " + f.path
+}
+
+func (f *fileSource) Apply(j *JSON) error {
+	raw, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+	f.lines, err = mergeLayer(j, raw)
+	return err
+}
+
+func (f *fileSource) locate(path string) (int, string) {
+	return f.lines[path], ""
+}
+
+type readerSource struct {
+	name  string
+	r     io.Reader
+	lines map[string]int
+}
+
+// ReaderSource is a layer merged from the JSON document read from `r`
+func ReaderSource(name string, r io.Reader) Source {
+	return &readerSource{name: name, r: r}
+}
+
+func (s *readerSource) String() string {
+	return s.name
+}
+
+func (s *readerSource) Apply(j *JSON) error {
+	raw, err := ioutil.ReadAll(s.r)
+	if err != nil {
+		return err
+	}
+	s.lines, err = mergeLayer(j, raw)
+	return err
+}
+
+func (s *readerSource) locate(path string) (int, string) {
+	return s.lines[path], ""
+}
+
+// mergeLayer merges the JSON document `raw` into `j`,
+// returning the line of each of its values by dotted path
+func mergeLayer(j *JSON, raw []byte) (map[string]int, error) {
+	layer, err := NewJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := j.Merge(layer); err != nil {
+		return nil, err
+	}
+	return leafLines(raw), nil
+}
+
+// leafLines returns the line of the values reported by leaves within
+// the valid JSON document `raw`
+func leafLines(raw []byte) map[string]int {
+	lines := make(map[string]int)
+	line, last := 1, 0
+	lineAt := func(pos int) int {
+		line += bytes.Count(raw[last:pos], []byte{'\n'})
+		last = pos
+		return line
+	}
+
+	s := scanner.New(raw)
+	var walk func(prefix string) error
+	walk = func(prefix string) error {
+		b, err := s.Peek()
+		if err != nil {
+			return err
+		}
+		l := lineAt(s.Pos())
+		if b != '{' && b != '[' {
+			if prefix != "" {
+				lines[prefix] = l
+			}
+			_, err = s.SkipValue()
+			return err
+		}
+		empty := true
+		err = s.Members(func(key string, index int) error {
+			empty = false
+			if b == '[' {
+				key = strconv.Itoa(index)
+			}
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			return walk(key)
+		})
+		if empty && prefix != "" {
+			lines[prefix] = l
+		}
+		return err
+	}
+	walk("")
+	return lines
+}
+
+type envSource struct {
+	prefix, sep string
+	names       map[string]string
+}
+
+// EnvSource is a layer of environment variables, applied by ApplyEnv
+func EnvSource(prefix, sep string) Source {
+	return &envSource{prefix: prefix, sep: sep}
+}
+
+func (s *envSource) String() string {
+	return "env " + s.prefix + "*"
+}
+
+func (s *envSource) Apply(j *JSON) error {
+	environ := os.Environ()
+	if err := j.applyEnv(environ, s.prefix, s.sep); err != nil {
+		return err
+	}
+	s.names = make(map[string]string)
+	for _, kv := range environ {
+		i := strings.IndexByte(kv, '=')
+		if !strings.HasPrefix(kv, s.prefix) || i <= len(s.prefix) {
+			continue
+		}
+		branch := j.envBranch(strings.Split(kv[len(s.prefix):i], s.sep))
+		s.names[dottedPath(branch)] = kv[:i]
+	}
+	return nil
+}
+
+func (s *envSource) locate(path string) (int, string) {
+	return 0, s.names[path]
+}
+
+type overrideSource struct {
+	name   string
+	values map[string]interface{}
+	keys   map[string]string
+}
+
+// OverrideSource is a layer of explicit values keyed by dotted paths
+func OverrideSource(name string, values map[string]interface{}) Source {
+	return &overrideSource{name: name, values: values}
+}
+
+func (s *overrideSource) String() string {
+	return s.name
+}
+
+func (s *overrideSource) Apply(j *JSON) error {
+	paths := make([]string, 0, len(s.values))
+	for path := range s.values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	s.keys = make(map[string]string)
+	for _, path := range paths {
+		branch := dottedBranch(j.data, path)
+		if err := j.setAt(branch, s.values[path]); err != nil {
+			return err
+		}
+		for leaf := range leaves(s.values[path]) {
+			s.keys[dottedPath(branch)+"."+leaf] = path
+		}
+		s.keys[dottedPath(branch)] = path
+	}
+	return nil
+}
+
+func (s *overrideSource) locate(path string) (int, string) {
+	return 0, s.keys[path]
+}
+
+// dottedPath joins the keys and indexes of `branch` with dots
+func dottedPath(branch []interface{}) string {
+	segments := make([]string, len(branch))
+	for i, key := range branch {
+		segments[i] = fmt.Sprint(key)
+	}
+	return strings.Join(segments, ".")
+}
@@ -0,0 +1,83 @@
+package simplejson
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "simplejson")
+	assert.Equal(t, nil, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config.json")
+	assert.Equal(t, nil, ioutil.WriteFile(file, []byte(`{"server":{"port":8080},"hosts":["a","b"]}`), 0600))
+
+	os.Setenv("SJTEST_SERVER__TLS", "true")
+	defer os.Unsetenv("SJTEST_SERVER__TLS")
+
+	defaults := ReaderSource("defaults", strings.NewReader(`{"server":{"port":80,"host":"localhost"},"log":"info"}`))
+	flags := OverrideSource("flags", map[string]interface{}{"log": "debug", "hosts.1": "c"})
+	js, err := Load(defaults, FileSource(file), EnvSource("SJTEST_", "__"), flags)
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, `{"hosts":["a","c"],"log":"debug","server":{"host":"localhost","port":8080,"tls":true}}`,
+		string(mustEncode(t, js)))
+	assert.Equal(t, defaults, js.Origin("server.host"))
+	assert.Equal(t, "file "+file, js.Origin("server.port").String())
+	assert.Equal(t, "env SJTEST_*", js.Origin("server.tls").String())
+	assert.Equal(t, flags, js.Origin("log"))
+	assert.Equal(t, flags, js.Origin("hosts.1"))
+	assert.Equal(t, "file "+file, js.Origin("hosts.0").String())
+	// containers report the last layer that set any of their values
+	assert.Equal(t, "env SJTEST_*", js.Origin("server").String())
+	assert.Equal(t, nil, js.Origin("missing"))
+
+	_, err = Load(FileSource(filepath.Join(dir, "missing.json")))
+	assert.Equal(t, true, errors.Is(err, os.ErrNotExist))
+}
+
+func TestProvenance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "simplejson")
+	assert.Equal(t, nil, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config.json")
+	assert.Equal(t, nil, ioutil.WriteFile(file, []byte("{\n  \"server\": {\n    \"port\": 8080,\n    \"tags\": []\n  },\n  \"hosts\": [\"a\",\n    \"b\"]\n}\n"), 0600))
+
+	os.Setenv("SJTEST_SERVER__TLS", "true")
+	defer os.Unsetenv("SJTEST_SERVER__TLS")
+
+	js, err := Load(
+		ReaderSource("defaults", strings.NewReader(`{"server":{"port":80},`+"\n"+`"log":"info"}`)),
+		FileSource(file),
+		EnvSource("SJTEST_", "__"),
+		OverrideSource("flags", map[string]interface{}{"log": "debug", "limits": map[string]interface{}{"cpu": 2}}),
+	)
+	assert.Equal(t, nil, err)
+
+	for path, want := range map[string]string{
+		"server.port": "file " + file + ":3",
+		"server.tags": "file " + file + ":4",
+		"hosts.1":     "file " + file + ":7",
+		"server.tls":  "env SJTEST_* (SJTEST_SERVER__TLS)",
+		"log":         "flags (log)",
+		"limits.cpu":  "flags (limits)",
+		"server":      "env SJTEST_*",
+	} {
+		p, ok := js.Provenance(path)
+		assert.Equal(t, true, ok, path)
+		assert.Equal(t, want, p.String(), path)
+	}
+
+	js, _ = Load(ReaderSource("defaults", strings.NewReader(`{"server":{"port":80},`+"\n"+`"log":"info"}`)))
+	p, _ := js.Provenance("log")
+	assert.Equal(t, Provenance{Source: js.Origin("log"), Line: 2}, p)
+	_, ok := js.Provenance("missing")
+	assert.Equal(t, false, ok)
+	assert.Equal(t, "unknown", Provenance{}.String())
+}
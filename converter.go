@@ -0,0 +1,440 @@
+package simplejson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ConverterFunc converts a Go value into a value to store in documents
+type ConverterFunc func(v interface{}) (interface{}, error)
+
+var (
+	// converters holds a map[reflect.Type]ConverterFunc, replaced on registration
+	converters   atomic.Value
+	convertersMu sync.Mutex
+)
+
+// RegisterConverter registers `fn` to convert the values of type `t`, such as
+// decimals or UUIDs, into values native to documents, typically strings or
+// numbers. Converters are applied to the values stored by Set, SetPath,
+// SetIndex, Append and the builders, including within objects, arrays,
+// structs, maps and slices, and by NewFromValue:
+//
+//	simplejson.RegisterConverter(reflect.TypeOf(uuid.UUID{}), func(v interface{}) (interface{}, error) {
+//		return v.(uuid.UUID).String(), nil
+//	})
+//
+// A nil `fn` removes the converter of `t`. Like SetCodec, it is meant to be
+// called during program initialization.
+func RegisterConverter(t reflect.Type, fn func(v interface{}) (interface{}, error)) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	old, _ := converters.Load().(map[reflect.Type]ConverterFunc)
+	m := make(map[reflect.Type]ConverterFunc, len(old)+1)
+	for k, v := range old {
+		m[k] = v
+	}
+	if fn == nil {
+		delete(m, t)
+	} else {
+		m[t] = fn
+	}
+	converters.Store(m)
+}
+
+// ConvertError reports a value that could not be converted
+type ConvertError struct {
+	// Path locates the value within the value being stored
+	Path []interface{}
+	Type reflect.Type
+	Err  error
+}
+
+func (e *ConvertError) Error() string {
+	return fmt.Sprintf("simplejson: cannot convert %s at %s: %v", e.Type, formatPath(e.Path), e.Err)
+}
+
+// Unwrap returns the error of the converter
+func (e *ConvertError) Unwrap() error {
+	return e.Err
+}
+
+// NewFromValue returns a document holding `v` converted into values native
+// to documents: structs and maps become objects, slices and arrays become
+// arrays, following the rules and tags of encoding/json, and values of types
+// with a registered converter are converted by it
+func NewFromValue(v interface{}) (*JSON, error) {
+	convs, _ := converters.Load().(map[reflect.Type]ConverterFunc)
+	c := &converter{convs: convs, full: true}
+	data, _, err := c.convert(v, nil)
+	if err != nil {
+		return nil, err
+	}
+	j := &JSON{data: data}
+	j.document()
+	return j, nil
+}
+
+// convertValue returns `val` with the registered converters applied,
+// or `val` itself if none applies
+func convertValue(val interface{}) (interface{}, error) {
+	convs, _ := converters.Load().(map[reflect.Type]ConverterFunc)
+	if len(convs) == 0 {
+		return val, nil
+	}
+	c := &converter{convs: convs}
+	val, _, err := c.convert(val, nil)
+	return val, err
+}
+
+// storable returns `val` converted as set by RegisterConverter, panicking
+// if it cannot be stored at `branch` within `j`, see StrictSet
+func (j *JSON) storable(val interface{}, branch ...interface{}) interface{} {
+	val, err := convertValue(val)
+	if err != nil {
+		ce := err.(*ConvertError)
+		base, _ := j.location()
+		ce.Path = append(append(base, branch...), ce.Path...)
+		panic(ce)
+	}
+	j.checkValue(val, branch...)
+	return val
+}
+
+// converter applies converters to Go values
+type converter struct {
+	convs map[reflect.Type]ConverterFunc
+	// full converts every Go value into the values of decoded documents,
+	// otherwise values are only rebuilt when a converter applies within them
+	full bool
+}
+
+// convert returns `val` converted and reports whether it was changed,
+// `path` locating it for errors
+func (c *converter) convert(val interface{}, path []interface{}) (interface{}, bool, error) {
+	if val == nil {
+		return nil, false, nil
+	}
+	t := reflect.TypeOf(val)
+	if fn, ok := c.convs[t]; ok {
+		v, err := fn(val)
+		if err != nil {
+			return nil, false, &ConvertError{Path: append([]interface{}{}, path...), Type: t, Err: err}
+		}
+		if v != nil && reflect.TypeOf(v) == t {
+			return v, true, nil
+		}
+		v, _, err = c.convert(v, path)
+		return v, true, err
+	}
+
+	switch v := val.(type) {
+	case map[string]interface{}:
+		var m map[string]interface{}
+		for key, elem := range v {
+			conv, changed, err := c.convert(elem, append(path, key))
+			if err != nil {
+				return nil, false, err
+			}
+			if changed {
+				if m == nil {
+					m = make(map[string]interface{}, len(v))
+					for k, e := range v {
+						m[k] = e
+					}
+				}
+				m[key] = conv
+			}
+		}
+		if m == nil {
+			return val, false, nil
+		}
+		return m, true, nil
+	case []interface{}:
+		var a []interface{}
+		for i, elem := range v {
+			conv, changed, err := c.convert(elem, append(path, i))
+			if err != nil {
+				return nil, false, err
+			}
+			if changed {
+				if a == nil {
+					a = append([]interface{}{}, v...)
+				}
+				a[i] = conv
+			}
+		}
+		if a == nil {
+			return val, false, nil
+		}
+		return a, true, nil
+	case bool, string, json.Number:
+		return val, false, nil
+	}
+	return c.convertReflect(reflect.ValueOf(val), path)
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// convertReflect is convert for Go values other than those of documents
+func (c *converter) convertReflect(rv reflect.Value, path []interface{}) (interface{}, bool, error) {
+	t := rv.Type()
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		if !c.full {
+			return rv.Interface(), false, nil
+		}
+		v, err := marshalNative(rv.Interface())
+		if err != nil {
+			return nil, false, &ConvertError{Path: append([]interface{}{}, path...), Type: t, Err: err}
+		}
+		return v, true, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			break
+		}
+		v, changed, err := c.convert(rv.Elem().Interface(), path)
+		if err != nil || changed || c.full {
+			return v, true, err
+		}
+	case reflect.Struct:
+		m := make(map[string]interface{})
+		var changed bool
+		for _, f := range structFields(t) {
+			fv, ok := fieldByIndex(rv, f.index)
+			if !ok || !fv.CanInterface() || f.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			v, ch, err := c.convert(fv.Interface(), append(path, f.name))
+			if err != nil {
+				return nil, false, err
+			}
+			m[f.name] = v
+			changed = changed || ch
+		}
+		if changed || c.full {
+			return m, true, nil
+		}
+	case reflect.Map:
+		if rv.IsNil() {
+			break
+		}
+		m := make(map[string]interface{}, rv.Len())
+		var changed bool
+		iter := rv.MapRange()
+		for iter.Next() {
+			key, err := mapKey(iter.Key())
+			if err != nil {
+				return nil, false, &ConvertError{Path: append([]interface{}{}, path...), Type: t, Err: err}
+			}
+			v, ch, err := c.convert(iter.Value().Interface(), append(path, key))
+			if err != nil {
+				return nil, false, err
+			}
+			m[key] = v
+			changed = changed || ch
+		}
+		if changed || c.full {
+			return m, true, nil
+		}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && rv.IsNil() {
+			break
+		}
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 && c.convs[t.Elem()] == nil {
+			if c.full {
+				return base64.StdEncoding.EncodeToString(rv.Bytes()), true, nil
+			}
+			break
+		}
+		a := make([]interface{}, rv.Len())
+		var changed bool
+		for i := range a {
+			v, ch, err := c.convert(rv.Index(i).Interface(), append(path, i))
+			if err != nil {
+				return nil, false, err
+			}
+			a[i] = v
+			changed = changed || ch
+		}
+		if changed || c.full {
+			return a, true, nil
+		}
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if c.full && t.PkgPath() != "" {
+			// named types, such as enums, are stored as their underlying type
+			return rv.Convert(basicTypes[t.Kind()]).Interface(), true, nil
+		}
+	default:
+		if c.full {
+			return nil, false, &ConvertError{Path: append([]interface{}{}, path...), Type: t, Err: ErrUnsupportedValue}
+		}
+	}
+	if c.full && isNil(rv) {
+		return nil, true, nil
+	}
+	return rv.Interface(), false, nil
+}
+
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:    reflect.TypeOf(false),
+	reflect.String:  reflect.TypeOf(""),
+	reflect.Int:     reflect.TypeOf(int(0)),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+}
+
+// marshalNative returns the document values `v` is encoded to by the codec
+func marshalNative(v interface{}) (interface{}, error) {
+	b, err := codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := codec.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var data interface{}
+	err = dec.Decode(&data)
+	return data, err
+}
+
+// mapKey returns the object key of the map key `k`, as encoding/json does
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if m, ok := k.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type %s", k.Type())
+}
+
+// structField is an encoded field of a struct
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// structFields returns the fields of `t` encoded by encoding/json, fields of
+// embedded structs are promoted unless shadowed by a field of the same name
+func structFields(t reflect.Type) []structField {
+	var fields, promoted []structField
+	seen := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		name := opts[0]
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, ef := range structFields(ft) {
+					ef.index = append([]int{i}, ef.index...)
+					promoted = append(promoted, ef)
+				}
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		sf := structField{name: name, index: []int{i}}
+		for _, opt := range opts[1:] {
+			sf.omitEmpty = sf.omitEmpty || opt == "omitempty"
+		}
+		seen[name] = true
+		fields = append(fields, sf)
+	}
+	for _, f := range promoted {
+		if !seen[f.name] {
+			seen[f.name] = true
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex,
+// reporting false when going through a nil embedded pointer
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+// isEmptyValue reports whether `v` is omitted by the omitempty option
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// isNil reports whether `v` is a nil pointer, interface, map or slice
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package simplejson
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+type money struct {
+	cents int64
+}
+
+type level int
+
+func TestRegisterConverter(t *testing.T) {
+	moneyType := reflect.TypeOf(money{})
+	RegisterConverter(moneyType, func(v interface{}) (interface{}, error) {
+		m := v.(money)
+		if m.cents < 0 {
+			return nil, errors.New("negative amount")
+		}
+		return fmt.Sprintf("%d.%02d", m.cents/100, m.cents%100), nil
+	})
+	defer RegisterConverter(moneyType, nil)
+
+	js := New()
+	js.Set("price", money{1250})
+	js.SetPath([]string{"a", "b"}, []interface{}{money{1}, 2})
+	js.Set("items", []interface{}{0})
+	js.Get("items").Append(map[string]money{"x": {300}})
+	type line struct {
+		Price money `json:"price"`
+		Qty   int   `json:"qty"`
+	}
+	js.Set("line", &line{Price: money{99}, Qty: 2})
+	js.Set("plain", []int{1, 2})
+
+	b, err := js.EncodeWith(SortedKeys())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"a":{"b":["0.01",2]},"items":[0,{"x":"3.00"}],"line":{"price":"0.99","qty":2},"plain":[1,2],"price":"12.50"}`, string(b))
+	// values without converted types are stored as given
+	assert.Equal(t, []int{1, 2}, js.Get("plain").Interface())
+
+	err = setPanic(func() { js.Get("items").Append(1, []money{{-1}}) })
+	assert.Equal(t, "simplejson: cannot convert simplejson.money at items[3][0]: negative amount", err.Error())
+	assert.Equal(t, 2, len(js.Get("items").Array()))
+
+	_, err = NewObjectBuilder().Set("a", []interface{}{money{-1}}).build()
+	assert.Equal(t, "simplejson: cannot build a[0]: cannot convert simplejson.money: negative amount", err.Error())
+	assert.Equal(t, "negative amount", errors.Unwrap(errors.Unwrap(err)).Error())
+	assert.Equal(t, `{"m":"1.00"}`, string(mustEncode(t, O("m", money{100}))))
+}
+
+func TestNewFromValue(t *testing.T) {
+	type base struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	type item struct {
+		base
+		Name    string            `json:"name"`
+		Level   level             `json:"level"`
+		Tags    []string          `json:"tags,omitempty"`
+		Attrs   map[int]bool      `json:"attrs"`
+		Created time.Time         `json:"created"`
+		Data    []byte            `json:"data"`
+		Skip    string            `json:"-"`
+		Next    *item             `json:"next"`
+		Extra   map[string]string `json:"extra,omitempty"`
+		hidden  int
+	}
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	js, err := NewFromValue(item{
+		base:    base{ID: 1, Name: "shadowed"},
+		Name:    "x",
+		Level:   3,
+		Attrs:   map[int]bool{7: true},
+		Created: created,
+		Data:    []byte("hi"),
+		Skip:    "skip",
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[string]interface{}{
+		"id":      1,
+		"name":    "x",
+		"level":   3,
+		"attrs":   map[string]interface{}{"7": true},
+		"created": "2020-01-02T03:04:05Z",
+		"data":    "aGk=",
+		"next":    nil,
+	}, js.Interface())
+
+	_, err = NewFromValue(map[string]interface{}{"c": make(chan int)})
+	assert.Equal(t, "simplejson: cannot convert chan int at c: unsupported value", err.Error())
+}
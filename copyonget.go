@@ -0,0 +1,21 @@
+package simplejson
+
+// CopyOnGet sets whether Map and Array return deep copies of the containers
+// of the document `j` belongs to, rather than the live containers, so callers
+// cannot modify the document through them. Interface and the Check methods
+// still return the live containers. Like SortKeys, it is a setting of the
+// document.
+func (j *JSON) CopyOnGet(on bool) {
+	if j == nil {
+		return
+	}
+	j.document().copyOnGet = on
+}
+
+// copied returns `data`, or a deep copy of it if the document copies on Get
+func (j *JSON) copied(data interface{}) interface{} {
+	if j.doc == nil || !j.doc.copyOnGet {
+		return data
+	}
+	return deepCopy(data)
+}
@@ -0,0 +1,26 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestCopyOnGet(t *testing.T) {
+	js := mustJSON(`{"a":{"b":[1,{"c":2}]}}`)
+
+	// live containers by default
+	js.Get("a").Map()["x"] = 1
+	assert.Equal(t, 1, js.Get("a", "x").Int())
+
+	js.CopyOnGet(true)
+	m := js.Get("a").Map()
+	m["y"] = 1
+	m["b"].([]interface{})[1].(map[string]interface{})["c"] = 3
+	js.Get("a", "b").Array()[0] = 5
+	assert.Equal(t, `{"a":{"b":[1,{"c":2}],"x":1}}`, string(mustEncode(t, js)))
+
+	js.CopyOnGet(false)
+	js.Get("a", "b").Array()[0] = 5
+	assert.Equal(t, 5, js.Get("a", "b", 0).Int())
+}
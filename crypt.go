@@ -0,0 +1,133 @@
+package simplejson
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// encField holds the algorithm of encrypted values, named as in JWE
+const encField = "$enc"
+
+// EncryptPaths replaces the values at the dotted `paths` by envelopes holding
+// them encrypted with AES-GCM under `key`, which must be 16, 24 or 32 bytes:
+//
+//	{"$enc": "A256GCM", "kid": "1f2e...", "nonce": "...", "data": "..."}
+//
+// The key id `kid` identifies the key without revealing it, and the path of
+// the value is authenticated so envelopes can't be moved within a document.
+// A `*` segment matches every key or element, as in `users.*.email`.
+// Missing values and values already encrypted are skipped.
+func (j *JSON) EncryptPaths(key []byte, paths ...string) error {
+	j = j.orMissing()
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	kid := keyID(key)
+	for _, path := range paths {
+		for _, branch := range globBranches(j.data, path) {
+			val, _ := lookup(j.data, branch)
+			if isEnvelope(val) {
+				continue
+			}
+			plain, err := codec.Marshal(val)
+			if err != nil {
+				return err
+			}
+			nonce := make([]byte, aead.NonceSize())
+			if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+				return err
+			}
+			sealed := aead.Seal(nil, nonce, plain, []byte(formatPointer(branch)))
+			env := map[string]interface{}{
+				encField: algorithm(key),
+				"kid":    kid,
+				"nonce":  base64.StdEncoding.EncodeToString(nonce),
+				"data":   base64.StdEncoding.EncodeToString(sealed),
+			}
+			if err = j.setAt(branch, env); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DecryptPaths restores the values at `paths` encrypted by EncryptPaths,
+// values that are not encrypted are left as is. It fails if a value was
+// encrypted with another key or was tampered with.
+func (j *JSON) DecryptPaths(key []byte, paths ...string) error {
+	j = j.orMissing()
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	kid := keyID(key)
+	for _, path := range paths {
+		for _, branch := range globBranches(j.data, path) {
+			val, _ := lookup(j.data, branch)
+			if !isEnvelope(val) {
+				continue
+			}
+			env := val.(map[string]interface{})
+			where := formatPath(branch)
+			if env["kid"] != kid {
+				return fmt.Errorf("simplejson: %s is encrypted with key %v", where, env["kid"])
+			}
+			nonce, err1 := base64.StdEncoding.DecodeString(fmt.Sprint(env["nonce"]))
+			sealed, err2 := base64.StdEncoding.DecodeString(fmt.Sprint(env["data"]))
+			if err1 != nil || err2 != nil || len(nonce) != aead.NonceSize() {
+				return fmt.Errorf("simplejson: %s holds an invalid envelope", where)
+			}
+			plain, err := aead.Open(nil, nonce, sealed, []byte(formatPointer(branch)))
+			if err != nil {
+				return fmt.Errorf("simplejson: cannot decrypt %s: %w", where, err)
+			}
+			restored, err := NewJSON(plain)
+			if err != nil {
+				return err
+			}
+			if err = j.setAt(branch, restored.data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// keyID returns a short identifier of `key`
+func keyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// algorithm returns the name of AES-GCM for the size of `key`
+func algorithm(key []byte) string {
+	return fmt.Sprintf("A%dGCM", len(key)*8)
+}
+
+func isEnvelope(val interface{}) bool {
+	m, ok := val.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	switch m[encField] {
+	case "A128GCM", "A192GCM", "A256GCM":
+		return true
+	}
+	return false
+}
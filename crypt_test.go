@@ -0,0 +1,58 @@
+package simplejson
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestEncryptPaths(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	js, err := NewJSON([]byte(`{"id":1,"ssn":"123-45","users":[{"email":"a@x","age":30},{"email":"b@x","tags":["t"]}]}`))
+	assert.Equal(t, nil, err)
+	want, _ := js.Encode()
+
+	assert.Equal(t, nil, js.EncryptPaths(key, "ssn", "users.*.email", "users.1.tags", "missing.path"))
+	assert.Equal(t, 1, js.Get("id").Int())
+	assert.Equal(t, "A256GCM", js.Get("ssn", "$enc").String())
+	assert.Equal(t, keyID(key), js.Get("users", 0, "email", "kid").String())
+	enc, _ := js.Encode()
+	assert.Equal(t, false, bytes.Contains(enc, []byte("123-45")))
+	assert.Equal(t, false, bytes.Contains(enc, []byte("a@x")))
+
+	// encrypting twice is a no-op
+	before, _ := js.Encode()
+	assert.Equal(t, nil, js.EncryptPaths(key, "ssn"))
+	after, _ := js.Encode()
+	assert.Equal(t, string(before), string(after))
+
+	other := bytes.Repeat([]byte{2}, 32)
+	err = js.Clone().DecryptPaths(other, "ssn")
+	assert.Equal(t, "simplejson: ssn is encrypted with key "+keyID(key), err.Error())
+
+	// envelopes are bound to their path
+	moved := js.Clone()
+	moved.Set("ssn", js.Get("users", 0, "email").Interface())
+	err = moved.DecryptPaths(key, "ssn")
+	assert.Equal(t, "simplejson: cannot decrypt ssn: cipher: message authentication failed", err.Error())
+	assert.Equal(t, "cipher: message authentication failed", errors.Unwrap(err).Error())
+
+	assert.Equal(t, nil, js.DecryptPaths(key, "ssn", "users.*.email", "users.*.tags", "id"))
+	got, _ := js.Encode()
+	assert.Equal(t, string(want), string(got))
+
+	assert.NotEqual(t, nil, js.EncryptPaths([]byte("short"), "id"))
+	js.Freeze()
+	assert.Equal(t, ErrFrozen, js.EncryptPaths(key, "id"))
+}
+
+func TestEncryptPathsKeySizes(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"a":"secret"}`))
+	key := bytes.Repeat([]byte{1}, 16)
+	assert.Equal(t, nil, js.EncryptPaths(key, "a"))
+	assert.Equal(t, "A128GCM", js.Get("a", "$enc").String())
+	assert.Equal(t, nil, js.DecryptPaths(key, "a"))
+	assert.Equal(t, "secret", js.Get("a").String())
+}
@@ -0,0 +1,254 @@
+package simplejson
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CSVOption configures ToCSV and FromCSV
+type CSVOption func(*csvConfig)
+
+type csvConfig struct {
+	columns []string
+	sep     string
+	nested  bool
+	comma   rune
+	strings bool
+}
+
+// CSVColumns sets the columns written by ToCSV and their order,
+// by default every field found is written, in sorted order
+func CSVColumns(columns ...string) CSVOption {
+	return func(c *csvConfig) {
+		c.columns = columns
+	}
+}
+
+// CSVSeparator sets the separator joining the keys and indexes of nested
+// values flattened into columns, "." by default
+func CSVSeparator(sep string) CSVOption {
+	return func(c *csvConfig) {
+		c.sep = sep
+	}
+}
+
+// CSVNestedJSON writes nested objects and arrays as JSON encoded cells
+// instead of flattening them, and decodes such cells back
+func CSVNestedJSON() CSVOption {
+	return func(c *csvConfig) {
+		c.nested = true
+	}
+}
+
+// CSVComma sets the field delimiter, ',' by default
+func CSVComma(r rune) CSVOption {
+	return func(c *csvConfig) {
+		c.comma = r
+	}
+}
+
+// CSVStrings makes FromCSV keep every cell as a string,
+// instead of converting numbers and booleans
+func CSVStrings() CSVOption {
+	return func(c *csvConfig) {
+		c.strings = true
+	}
+}
+
+func newCSVConfig(opts []CSVOption) *csvConfig {
+	c := &csvConfig{sep: ".", comma: ','}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ToCSV writes the array of objects `j` to `w` as CSV, a header row followed by
+// a row per object. Nested objects and arrays are flattened into one column per
+// leaf, named by joining keys and indexes with the separator, and missing fields
+// and nulls are written as empty cells.
+func (j *JSON) ToCSV(w io.Writer, opts ...CSVOption) error {
+	j = j.orMissing()
+	c := newCSVConfig(opts)
+	a, ok := j.CheckArray()
+	if !ok {
+		return fmt.Errorf("simplejson: ToCSV requires an array of objects, got %s", jsonType(j.data))
+	}
+
+	rows := make([]map[string]string, len(a))
+	seen := make(map[string]bool)
+	for i, v := range a {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("simplejson: ToCSV element %d is %s, not an object", i, jsonType(v))
+		}
+		row := make(map[string]string)
+		for key, val := range m {
+			if err := c.flatten(row, key, val); err != nil {
+				return err
+			}
+		}
+		for key := range row {
+			seen[key] = true
+		}
+		rows[i] = row
+	}
+
+	columns := c.columns
+	if columns == nil {
+		for key := range seen {
+			columns = append(columns, key)
+		}
+		sort.Strings(columns)
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = c.comma
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, col := range columns {
+			record[i] = row[col]
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// flatten adds the cells of `val`, found at the column `name`, to `row`
+func (c *csvConfig) flatten(row map[string]string, name string, val interface{}) error {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		if !c.nested && len(v) > 0 {
+			for key, val := range v {
+				if err := c.flatten(row, name+c.sep+key, val); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case []interface{}:
+		if !c.nested && len(v) > 0 {
+			for i, val := range v {
+				if err := c.flatten(row, name+c.sep+strconv.Itoa(i), val); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case nil:
+		return nil
+	case string:
+		row[name] = v
+		return nil
+	}
+	b, err := newEncodeConfig(nil).encode(val)
+	if err != nil {
+		return err
+	}
+	row[name] = string(b)
+	return nil
+}
+
+// FromCSV reads CSV from `r` into an array of objects, the first row naming
+// the fields of the objects. Column names holding the separator are expanded
+// into nested objects, or arrays when their keys are consecutive indexes,
+// the reverse of ToCSV. Empty cells are omitted, numbers and booleans
+// are converted unless CSVStrings is given.
+func FromCSV(r io.Reader, opts ...CSVOption) (*JSON, error) {
+	c := newCSVConfig(opts)
+	cr := csv.NewReader(r)
+	cr.Comma = c.comma
+	rows := []interface{}{}
+	header, err := cr.Read()
+	for err == nil {
+		var record []string
+		if record, err = cr.Read(); err != nil {
+			break
+		}
+		obj := make(map[string]interface{})
+		for i, cell := range record {
+			if cell == "" {
+				continue
+			}
+			val, err := c.cell(cell)
+			if err != nil {
+				line, _ := cr.FieldPos(i)
+				return nil, fmt.Errorf("simplejson: FromCSV line %d, column %q: %w", line, header[i], err)
+			}
+			if c.nested || c.sep == "" {
+				obj[header[i]] = val
+			} else {
+				setFlattened(obj, strings.Split(header[i], c.sep), val)
+			}
+		}
+		for key, val := range obj {
+			obj[key] = toArrays(val)
+		}
+		rows = append(rows, obj)
+	}
+	if err != io.EOF {
+		return nil, err
+	}
+	j := &JSON{data: rows}
+	j.document()
+	return j, nil
+}
+
+// cell returns the value of a CSV cell
+func (c *csvConfig) cell(s string) (interface{}, error) {
+	if c.nested && (s[0] == '{' || s[0] == '[') {
+		js, err := NewJSON([]byte(s))
+		if err != nil {
+			return nil, err
+		}
+		return js.data, nil
+	}
+	if c.strings {
+		return s, nil
+	}
+	return inferType(s), nil
+}
+
+// setFlattened sets `val` at the keys of `segments` within `obj`
+func setFlattened(obj map[string]interface{}, segments []string, val interface{}) {
+	for _, key := range segments[:len(segments)-1] {
+		next, ok := obj[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			obj[key] = next
+		}
+		obj = next
+	}
+	obj[segments[len(segments)-1]] = val
+}
+
+// toArrays converts the objects of `data` whose keys are the indexes 0..n-1
+// into arrays
+func toArrays(data interface{}) interface{} {
+	m, ok := data.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return data
+	}
+	for key, val := range m {
+		m[key] = toArrays(val)
+	}
+	a := make([]interface{}, len(m))
+	for key, val := range m {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(a) || strconv.Itoa(i) != key {
+			return m
+		}
+		a[i] = val
+	}
+	return a
+}
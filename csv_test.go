@@ -0,0 +1,62 @@
+package simplejson
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/brunotm/go-simplejson/scanner"
+)
+
+func TestToCSV(t *testing.T) {
+	js, err := NewJSON([]byte(`[
+		{"id":1,"name":"a, b","owner":{"id":7},"tags":["x","y"],"ok":true},
+		{"id":2,"name":"c","note":null,"extra":"e"}
+	]`))
+	assert.Equal(t, nil, err)
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, js.ToCSV(&buf))
+	assert.Equal(t, "extra,id,name,ok,owner.id,tags.0,tags.1\n,1,\"a, b\",true,7,x,y\ne,2,c,,,,\n", buf.String())
+
+	buf.Reset()
+	assert.Equal(t, nil, js.ToCSV(&buf, CSVColumns("name", "tags", "id"), CSVNestedJSON(), CSVComma(';')))
+	assert.Equal(t, "name;tags;id\na, b;\"[\"\"x\"\",\"\"y\"\"]\";1\nc;;2\n", buf.String())
+
+	assert.NotEqual(t, nil, js.Get(0).ToCSV(&buf))
+	bad, _ := NewJSON([]byte(`[{"a":1},2]`))
+	assert.Equal(t, "simplejson: ToCSV element 1 is number, not an object", bad.ToCSV(&buf).Error())
+}
+
+func TestFromCSV(t *testing.T) {
+	js, err := FromCSV(strings.NewReader("id,name,owner.id,tags.0,tags.1,ok\n1,\"a, b\",7,x,y,true\n2,c,,,,\n"))
+	assert.Equal(t, nil, err)
+	b, _ := js.Encode()
+	assert.Equal(t, `[{"id":1,"name":"a, b","ok":true,"owner":{"id":7},"tags":["x","y"]},{"id":2,"name":"c"}]`, string(b))
+
+	js, err = FromCSV(strings.NewReader("id;tags\n01;[1,2]\n"), CSVComma(';'), CSVNestedJSON(), CSVStrings())
+	assert.Equal(t, nil, err)
+	b, _ = js.Encode()
+	assert.Equal(t, `[{"id":"01","tags":[1,2]}]`, string(b))
+
+	_, err = FromCSV(strings.NewReader("id,tags\n1,[1,\n"), CSVNestedJSON())
+	assert.NotEqual(t, nil, err)
+	_, err = FromCSV(strings.NewReader("id,tags\n1,\"[1,\"\n"), CSVNestedJSON())
+	assert.Equal(t, `simplejson: FromCSV line 2, column "tags": scanner: unexpected end of JSON input`, err.Error())
+	assert.Equal(t, true, errors.Is(err, scanner.ErrUnexpectedEnd))
+
+	js, err = FromCSV(strings.NewReader(""))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(js.Array()))
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	js, _ := NewJSON([]byte(`[{"a":{"b":[1,{"c":"d"}]},"e":1.5}]`))
+	var buf bytes.Buffer
+	assert.Equal(t, nil, js.ToCSV(&buf))
+	back, err := FromCSV(&buf)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, deepEqual(js.data, back.data))
+}
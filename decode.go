@@ -0,0 +1,202 @@
+package simplejson
+
+import (
+	"errors"
+	"io"
+)
+
+// DecodeOption configures how documents are decoded by NewJSONWith, NewFromReaderWith
+// and Decoder
+type DecodeOption func(*decodeConfig)
+
+type decodeConfig struct {
+	internKeys    bool
+	internStrings bool
+	maxBytes      int64
+	limits        *Limits
+	utf8          UTF8Mode
+	sanitizeUTF8  bool
+	coerce        map[string]Coercion
+	locale        *numberLocale
+	progress      func(int64)
+}
+
+func newDecodeConfig(opts []DecodeOption) *decodeConfig {
+	c := &decodeConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// InternKeys makes every occurrence of an object key within the decoded
+// document share a single string, so arrays of homogeneous objects don't hold
+// a copy of each key per element
+func InternKeys() DecodeOption {
+	return func(c *decodeConfig) {
+		c.internKeys = true
+	}
+}
+
+// InternStrings is like InternKeys, but also applies to string values
+func InternStrings() DecodeOption {
+	return func(c *decodeConfig) {
+		c.internKeys = true
+		c.internStrings = true
+	}
+}
+
+// MaxBytes fails decoding with ErrTooLarge when the input is larger than `n`
+// bytes. A Decoder applies the limit to the whole stream.
+func MaxBytes(n int64) DecodeOption {
+	return func(c *decodeConfig) {
+		c.maxBytes = n
+	}
+}
+
+// ErrTooLarge is returned when decoding an input larger than allowed by MaxBytes
+var ErrTooLarge = errors.New("simplejson: document too large")
+
+// limit returns `r` limited to the configured size
+func (c *decodeConfig) limit(r io.Reader) io.Reader {
+	if c.maxBytes <= 0 {
+		return r
+	}
+	return &limitedReader{r: r, n: c.maxBytes}
+}
+
+// limitedReader is like io.LimitedReader, returning ErrTooLarge
+// instead of io.EOF when more than `n` bytes are available
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, ErrTooLarge
+	}
+	// read one byte past the limit to detect larger inputs
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n + int(l.n), ErrTooLarge
+	}
+	return n, err
+}
+
+// apply post processes a freshly decoded document according to the options
+func (c *decodeConfig) apply(j *JSON) error {
+	if c.sanitizeUTF8 && c.utf8 == UTF8Replace {
+		j.data = sanitizeUTF8(j.data)
+	}
+	if c.internKeys {
+		in := &interner{table: make(map[string]string), values: c.internStrings}
+		j.data = in.walk(j.data)
+	}
+	if c.coerce != nil {
+		data, err := coerceTypes(j.data, c.coerce, c.locale)
+		if err != nil {
+			return err
+		}
+		j.data = data
+	}
+	return nil
+}
+
+type interner struct {
+	table  map[string]string
+	values bool
+}
+
+func (in *interner) intern(s string) string {
+	if v, ok := in.table[s]; ok {
+		return v
+	}
+	in.table[s] = s
+	return s
+}
+
+func (in *interner) walk(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[in.intern(key)] = in.walk(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range v {
+			v[i] = in.walk(val)
+		}
+	case string:
+		if in.values {
+			return in.intern(v)
+		}
+	}
+	return data
+}
+
+// Decoder reads successive documents from a stream, such as concatenated
+// or newline delimited JSON values, applying the same options to each
+//
+//	dec := simplejson.NewDecoder(r, simplejson.InternKeys())
+//	for dec.More() {
+//		js, err := dec.Decode()
+//		...
+//	}
+type Decoder struct {
+	dec     StreamDecoder
+	config  *decodeConfig
+	pending interface{}
+	err     error
+	peeked  bool
+	// failed is set once an error was returned by Decode
+	failed bool
+}
+
+// NewDecoder returns a Decoder reading from `r` with the current codec
+func NewDecoder(r io.Reader, opts ...DecodeOption) *Decoder {
+	c := newDecodeConfig(opts)
+	dec := codec.NewDecoder(c.input(r))
+	dec.UseNumber()
+	return &Decoder{dec: dec, config: c}
+}
+
+// More reports whether there is another document to decode,
+// a malformed document is reported by the following call to Decode
+// after which More returns false
+func (d *Decoder) More() bool {
+	d.peek()
+	return d.err != io.EOF && !d.failed
+}
+
+// Decode returns the next document of the stream,
+// or io.EOF when the stream is exhausted
+func (d *Decoder) Decode() (*JSON, error) {
+	d.peek()
+	data, err := d.pending, d.err
+	d.peeked, d.pending = false, nil
+	if err != nil {
+		d.failed = true
+		return nil, err
+	}
+	j := &JSON{data: data}
+	j.document()
+	if err = d.config.apply(j); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// peek decodes the next value ahead of Decode, once
+func (d *Decoder) peek() {
+	if d.peeked || d.err != nil {
+		return
+	}
+	d.err = d.config.decode(d.dec, &d.pending)
+	d.peeked = true
+}
@@ -0,0 +1,72 @@
+package simplejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestInternKeys(t *testing.T) {
+	raw := []byte(`[{"id":1,"name":"a"},{"id":2,"name":"a"},{"id":3,"name":"b"}]`)
+
+	js, err := NewJSONWith(raw, InternKeys())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(js.Array()))
+	assert.Equal(t, "a", js.Get(1, "name").String())
+	assert.Equal(t, int64(3), js.Get(2, "id").Int64())
+
+	js, err = NewFromReaderWith(bytes.NewReader(raw), InternStrings())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "b", js.Get(2, "name").String())
+
+	in := &interner{table: make(map[string]string)}
+	a := in.intern(string([]byte("key")))
+	b := in.intern(string([]byte("key")))
+	assert.Equal(t, a, b)
+	assert.Equal(t, 1, len(in.table))
+}
+
+func TestDecoder(t *testing.T) {
+	dec := NewDecoder(bytes.NewBufferString("{\"id\":1}\n{\"id\":2,\"name\":\"b\"}\n[3]"), InternKeys())
+
+	var docs []*JSON
+	for dec.More() {
+		js, err := dec.Decode()
+		assert.Equal(t, nil, err)
+		docs = append(docs, js)
+	}
+	assert.Equal(t, 3, len(docs))
+	assert.Equal(t, json.Number("2"), docs[1].Get("id").Interface())
+	assert.Equal(t, 3, docs[2].Get(0).Int())
+
+	docs[0].Set("id", 10)
+	assert.Equal(t, 2, docs[1].Get("id").Int())
+
+	_, err := dec.Decode()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, false, dec.More())
+
+	dec = NewDecoder(bytes.NewBufferString(`{"a":1} {"a":`))
+	_, err = dec.Decode()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, dec.More())
+	_, err = dec.Decode()
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, false, dec.More())
+}
+
+func TestNewJSONSignature(t *testing.T) {
+	// the constructors can be stored as plain decoding functions
+	var decode func([]byte) (*JSON, error) = NewJSON
+	var read func(io.Reader) (*JSON, error) = NewFromReader
+	js, err := decode([]byte(`{"a":1}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, js.Get("a").Int())
+	js, err = read(strings.NewReader(`[1]`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(js.Array()))
+}
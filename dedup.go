@@ -0,0 +1,103 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrNotFrozen is returned by operations requiring a frozen document
+var ErrNotFrozen = errors.New("simplejson: document is not frozen")
+
+// DedupSubtrees makes identical objects and arrays within the frozen `j` share
+// a single instance, reducing the memory held by documents repeating the same
+// blocks, and returns the number of containers that were replaced.
+//
+// Sharing is only safe for immutable data, ErrNotFrozen is returned unless
+// the document was frozen. It must not be called concurrently with reads
+// of the document.
+func (j *JSON) DedupSubtrees() (int, error) {
+	if !j.IsFrozen() {
+		return 0, ErrNotFrozen
+	}
+	d := &deduper{table: make(map[string]dedupEntry)}
+	j.data, _ = d.walk(j.data)
+	return d.replaced, nil
+}
+
+// deduper hash conses subtrees, each distinct subtree gets an id and
+// containers are identified by their type and the ids of their members
+type deduper struct {
+	table    map[string]dedupEntry
+	next     int
+	replaced int
+	sig      strings.Builder
+}
+
+type dedupEntry struct {
+	id  int
+	val interface{}
+}
+
+// walk returns the canonical instance of `data` and its id
+func (d *deduper) walk(data interface{}) (interface{}, int) {
+	var sig string
+	switch v := data.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		ids := make([]int, len(keys))
+		for i, key := range keys {
+			v[key], ids[i] = d.walk(v[key])
+		}
+		d.sig.Reset()
+		d.sig.WriteByte('{')
+		for i, key := range keys {
+			d.sig.WriteString(strconv.Quote(key))
+			d.sig.WriteString(strconv.Itoa(ids[i]))
+			d.sig.WriteByte(',')
+		}
+		sig = d.sig.String()
+	case []interface{}:
+		ids := make([]int, len(v))
+		for i := range v {
+			v[i], ids[i] = d.walk(v[i])
+		}
+		d.sig.Reset()
+		d.sig.WriteByte('[')
+		for _, id := range ids {
+			d.sig.WriteString(strconv.Itoa(id))
+			d.sig.WriteByte(',')
+		}
+		sig = d.sig.String()
+	case string:
+		sig = "s" + v
+	case json.Number:
+		sig = "n" + string(v)
+	case bool:
+		sig = "b" + strconv.FormatBool(v)
+	case nil:
+		sig = "z"
+	default:
+		// values of other types are never shared, nor their parents
+		d.next++
+		return data, -d.next
+	}
+
+	if e, ok := d.table[sig]; ok {
+		switch data.(type) {
+		case map[string]interface{}, []interface{}:
+			d.replaced++
+			return e.val, e.id
+		}
+		return data, e.id
+	}
+	d.next++
+	d.table[sig] = dedupEntry{id: d.next, val: data}
+	return data, d.next
+}
@@ -0,0 +1,46 @@
+package simplejson
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestDedupSubtrees(t *testing.T) {
+	body := `{"events":[
+		{"meta":{"host":"a","tags":["x","y"]},"v":1},
+		{"meta":{"host":"a","tags":["x","y"]},"v":2},
+		{"meta":{"host":"b","tags":["x","y"]},"v":3},
+		{"meta":{"host":"a","tags":["x","y"]},"v":1}]}`
+	js, _ := NewJSON([]byte(body))
+
+	_, err := js.DedupSubtrees()
+	assert.Equal(t, ErrNotFrozen, err)
+
+	before, _ := js.Encode()
+	n, err := js.Freeze().DedupSubtrees()
+	assert.Equal(t, nil, err)
+	// three tags arrays, two meta objects and the last event
+	assert.Equal(t, 6, n)
+	after, _ := js.Encode()
+	assert.Equal(t, string(before), string(after))
+
+	same := func(a, b *JSON) bool {
+		return reflect.ValueOf(a.data).Pointer() == reflect.ValueOf(b.data).Pointer()
+	}
+	assert.Equal(t, true, same(js.Get("events", 0, "meta"), js.Get("events", 1, "meta")))
+	assert.Equal(t, true, same(js.Get("events", 0), js.Get("events", 3)))
+	assert.Equal(t, true, same(js.Get("events", 0, "meta", "tags"), js.Get("events", 2, "meta", "tags")))
+	assert.Equal(t, false, same(js.Get("events", 0, "meta"), js.Get("events", 2, "meta")))
+
+	// copies stay independent of the shared subtrees
+	c := js.Clone()
+	c.Get("events", 0, "meta").Set("host", "c")
+	assert.Equal(t, "a", c.Get("events", 1, "meta", "host").String())
+
+	s := js.Snapshot()
+	s.Get("events", 1, "meta").Set("host", "d")
+	assert.Equal(t, "a", s.Get("events", 0, "meta", "host").String())
+	assert.Equal(t, "a", js.Get("events", 1, "meta", "host").String())
+}
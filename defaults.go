@@ -0,0 +1,71 @@
+package simplejson
+
+import (
+	"sort"
+	"strings"
+)
+
+// SetDefault registers `val` as the value Get returns for the dotted `path`,
+// such as "server.port" or "hosts.0", when it is missing from the document
+// `j` belongs to, so defaults are declared once rather than at every call:
+//
+//	js.SetDefault("server.port", 8080)
+//	port := js.Get("server", "port").Int()
+//
+// Get on a missing parent of registered paths returns an object holding their
+// defaults. Defaults are not added to the document, Get returns copies of them.
+// Like SortKeys, it is a setting of the document.
+func (j *JSON) SetDefault(path string, val interface{}) {
+	if j == nil {
+		return
+	}
+	d := j.document()
+	if d.defaults == nil {
+		d.defaults = make(map[string]interface{})
+	}
+	d.defaults[path] = val
+}
+
+// defaultAt returns the default of the missing value found at `branch`
+// within `j`, see SetDefault
+func (j *JSON) defaultAt(branch []interface{}) (interface{}, bool) {
+	if j == nil || j.doc == nil || len(j.doc.defaults) == 0 {
+		return nil, false
+	}
+	base, ok := j.location()
+	if !ok {
+		return nil, false
+	}
+	path := dottedPath(append(base, branch...))
+	if val, ok := j.doc.defaults[path]; ok {
+		return deepCopy(val), true
+	}
+
+	// build the object holding the defaults of the paths below
+	prefix := path + "."
+	var paths []string
+	for p := range j.doc.defaults {
+		if strings.HasPrefix(p, prefix) {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, false
+	}
+	sort.Strings(paths)
+	obj := make(map[string]interface{})
+	for _, p := range paths {
+		m := obj
+		segments := splitPath(p[len(prefix):])
+		for _, key := range segments[:len(segments)-1] {
+			child, ok := m[key].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				m[key] = child
+			}
+			m = child
+		}
+		m[segments[len(segments)-1]] = deepCopy(j.doc.defaults[p])
+	}
+	return obj, true
+}
@@ -0,0 +1,30 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestSetDefault(t *testing.T) {
+	js := mustJSON(`{"server":{"host":"a"},"hosts":["x"]}`)
+	js.SetDefault("server.port", 8080)
+	js.SetDefault("hosts.1", "y")
+	js.SetDefault("log.level", "info")
+	js.SetDefault("log.format", "json")
+	js.SetDefault("server.host", "localhost")
+
+	assert.Equal(t, 8080, js.Get("server", "port").Int())
+	assert.Equal(t, 8080, js.Get("server").Get("port").Int())
+	assert.Equal(t, "a", js.Get("server", "host").String())
+	assert.Equal(t, "y", js.Get("hosts", 1).String())
+	assert.Equal(t, "info", js.Get("log").Get("level").String())
+	assert.Equal(t, map[string]interface{}{"level": "info", "format": "json"}, js.Get("log").Interface())
+
+	// defaults are not part of the document
+	_, ok := js.CheckGet("server", "port")
+	assert.Equal(t, false, ok)
+	js.Get("log").Set("level", "debug")
+	assert.Equal(t, "info", js.Get("log", "level").String())
+	assert.Equal(t, "", js.Get("missing").String())
+}
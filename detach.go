@@ -0,0 +1,34 @@
+package simplejson
+
+import "fmt"
+
+// Detach removes the value at `branch` from the document and returns it
+// as a new, independent document
+func (j *JSON) Detach(branch ...interface{}) (*JSON, error) {
+	j = j.orMissing()
+	if j.IsFrozen() {
+		return nil, ErrFrozen
+	}
+	if len(branch) == 0 {
+		return nil, fmt.Errorf("simplejson: cannot detach the root of a document")
+	}
+	parent, ok := j.walk(branch[:len(branch)-1])
+	if !ok {
+		return nil, fmt.Errorf("simplejson: cannot detach %s, not found", formatPath(branch))
+	}
+	key := branch[len(branch)-1]
+	val, ok := lookup(parent.data, []interface{}{key})
+	if !ok {
+		return nil, fmt.Errorf("simplejson: cannot detach %s, not found", formatPath(branch))
+	}
+	parent.delChild(key)
+
+	d := &JSON{data: val}
+	if j.doc != nil && j.doc.shared {
+		// the tree may still be referenced by snapshots
+		d.document().share()
+	} else {
+		d.document()
+	}
+	return d, nil
+}
@@ -0,0 +1,39 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestDetach(t *testing.T) {
+	js, err := NewJSON([]byte(`{"users":[{"id":1},{"id":2,"tags":["a"]}],"meta":{"n":2}}`))
+	assert.Equal(t, nil, err)
+
+	user, err := js.Detach("users", 1)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"id":2,"tags":["a"]}`, string(mustEncode(t, user)))
+	assert.Equal(t, `{"meta":{"n":2},"users":[{"id":1}]}`, string(mustEncode(t, js)))
+
+	meta, err := js.Detach("meta")
+	assert.Equal(t, nil, err)
+	meta.Set("n", 1)
+	_, ok := js.CheckGet("meta")
+	assert.Equal(t, false, ok)
+
+	_, err = js.Detach("missing", "x")
+	assert.Equal(t, "simplejson: cannot detach missing.x, not found", err.Error())
+	_, err = js.Detach()
+	assert.NotEqual(t, nil, err)
+}
+
+func TestDetachSnapshot(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"b":1}}`))
+	assert.Equal(t, nil, err)
+	snap := js.Snapshot()
+
+	a, err := js.Detach("a")
+	assert.Equal(t, nil, err)
+	a.Set("b", 2)
+	assert.Equal(t, 1, snap.Get("a", "b").Int())
+}
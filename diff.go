@@ -0,0 +1,149 @@
+package simplejson
+
+import (
+	"sort"
+	"strings"
+)
+
+// DiffOption configures FormatDiff
+type DiffOption func(*diffConfig)
+
+type diffConfig struct {
+	color    bool
+	pointers bool
+}
+
+// DiffColor highlights removed values in red and added values in green
+// with ANSI escape sequences, for display on terminals
+func DiffColor() DiffOption {
+	return func(c *diffConfig) {
+		c.color = true
+	}
+}
+
+// DiffPointers locates values with RFC 6901 JSON Pointers, such as
+// `/items/3/name`, instead of `items[3].name`
+func DiffPointers() DiffOption {
+	return func(c *diffConfig) {
+		c.pointers = true
+	}
+}
+
+const (
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiReset = "\x1b[0m"
+)
+
+// FormatDiff returns the differences between the document and `other` for
+// humans, a line per removed value prefixed by `-` and a line per added value
+// prefixed by `+`, each annotated with its path:
+//
+//	fmt.Print(deployed.FormatDiff(desired))
+//	// - server.port: 80
+//	// + server.port: 8080
+//	// + server.tls: {"cert":"server.pem"}
+//
+// Objects are compared key by key and arrays element by element, numbers
+// by value. An empty string is returned when both are equal.
+func (j *JSON) FormatDiff(other *JSON, opts ...DiffOption) string {
+	j = j.orMissing()
+	c := &diffConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	var b strings.Builder
+	line := func(sign, color string, path []interface{}, val interface{}) {
+		if c.color {
+			b.WriteString(color)
+		}
+		b.WriteString(sign)
+		b.WriteByte(' ')
+		if c.pointers {
+			b.WriteString(formatPointer(path))
+		} else {
+			b.WriteString(formatPath(path))
+		}
+		b.WriteString(": ")
+		enc, err := newEncodeConfig([]EncodeOption{SortedKeys()}).encode(val)
+		if err != nil {
+			enc = []byte("<" + err.Error() + ">")
+		}
+		b.Write(enc)
+		if c.color {
+			b.WriteString(ansiReset)
+		}
+		b.WriteByte('\n')
+	}
+
+	var data interface{}
+	if other != nil {
+		data = other.data
+	}
+	diff(j.data, data, nil, func(ch Change) {
+		if ch.Op != ChangeAdd {
+			line("-", ansiRed, ch.Path, ch.Old)
+		}
+		if ch.Op != ChangeRemove {
+			line("+", ansiGreen, ch.Path, ch.New)
+		}
+	})
+	return b.String()
+}
+
+// diff calls `fn` with the changes turning `a` into `b`, found at `path`
+func diff(a, b interface{}, path []interface{}, fn func(Change)) {
+	at := func(key interface{}) []interface{} {
+		return append(path[:len(path):len(path)], key)
+	}
+
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := sortedKeys(av)
+		for key := range bv {
+			if _, ok := av[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			aval, inA := av[key]
+			bval, inB := bv[key]
+			switch {
+			case !inB:
+				fn(Change{Op: ChangeRemove, Path: at(key), Old: aval})
+			case !inA:
+				fn(Change{Op: ChangeAdd, Path: at(key), New: bval})
+			default:
+				diff(aval, bval, at(key), fn)
+			}
+		}
+		return
+
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(av) || i < len(bv); i++ {
+			switch {
+			case i >= len(bv):
+				fn(Change{Op: ChangeRemove, Path: at(i), Old: av[i]})
+			case i >= len(av):
+				fn(Change{Op: ChangeAdd, Path: at(i), New: bv[i]})
+			default:
+				diff(av[i], bv[i], at(i), fn)
+			}
+		}
+		return
+	}
+
+	if !deepEqual(a, b) {
+		fn(Change{Op: ChangeReplace, Path: path, Old: a, New: b})
+	}
+}
@@ -0,0 +1,27 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestFormatDiff(t *testing.T) {
+	a := mustJSON(`{"server":{"port":80,"debug":true,"hosts":["a","b"]},"name":"x","n":1.0}`)
+	b := mustJSON(`{"server":{"port":8080,"tls":{"key":"k","cert":"c"},"hosts":["a"]},"name":"x","n":1}`)
+
+	assert.Equal(t, `- server.debug: true
+- server.hosts[1]: "b"
+- server.port: 80
++ server.port: 8080
++ server.tls: {"cert":"c","key":"k"}
+`, a.FormatDiff(b))
+
+	assert.Equal(t, "- /server/debug: true\n", a.FormatDiff(mustJSON(`{"server":{"port":80,"hosts":["a","b"]},"name":"x","n":1}`), DiffPointers()))
+
+	assert.Equal(t, "\x1b[31m- name: \"x\"\x1b[0m\n\x1b[32m+ name: [\"x\"]\x1b[0m\n",
+		mustJSON(`{"name":"x"}`).FormatDiff(mustJSON(`{"name":["x"]}`), DiffColor()))
+
+	assert.Equal(t, "- (root): 1\n+ (root): null\n", mustJSON(`1`).FormatDiff(nil))
+	assert.Equal(t, "", a.FormatDiff(a.Clone()))
+}
@@ -0,0 +1,59 @@
+package simplejson
+
+import "unicode/utf8"
+
+// FindDuplicateValues reports the strings and numbers appearing at more than
+// one path of the document, such as repeated secrets in configurations.
+// Values are keyed by their JSON encoding, so the string "42" and the number
+// 42 are distinct, and numbers are equal by value. Values shorter than
+// `minLen` characters, numbers by their encoding, are ignored. Paths are
+// listed in document order, keys sorted.
+func (j *JSON) FindDuplicateValues(minLen int) map[string][][]interface{} {
+	j = j.orMissing()
+	c := newEncodeConfig([]EncodeOption{NormalizedNumbers(), EscapeHTML(false)})
+	found := make(map[string][][]interface{})
+	var walk func(data interface{}, path []interface{})
+	walk = func(data interface{}, path []interface{}) {
+		at := func(key interface{}) []interface{} {
+			return append(path[:len(path):len(path)], key)
+		}
+		switch v := data.(type) {
+		case map[string]interface{}:
+			for _, key := range sortedKeys(v) {
+				walk(v[key], at(key))
+			}
+			return
+		case []interface{}:
+			for i, val := range v {
+				walk(val, at(i))
+			}
+			return
+		case string:
+			if utf8.RuneCountInString(v) < minLen {
+				return
+			}
+		default:
+			if !isNumber(data) {
+				return
+			}
+		}
+		b, err := c.encode(data)
+		if err != nil || !isString(data) && len(b) < minLen {
+			return
+		}
+		found[string(b)] = append(found[string(b)], path)
+	}
+	walk(j.data, nil)
+
+	for key, paths := range found {
+		if len(paths) < 2 {
+			delete(found, key)
+		}
+	}
+	return found
+}
+
+func isString(data interface{}) bool {
+	_, ok := data.(string)
+	return ok
+}
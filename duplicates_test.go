@@ -0,0 +1,28 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestFindDuplicateValues(t *testing.T) {
+	js := mustJSON(`{
+		"db": {"password": "s3cr3t!", "port": 5432, "user": "app"},
+		"cache": {"password": "s3cr3t!", "port": 5432.0, "user": "app"},
+		"ids": ["5432", 7, 7],
+		"flags": [true, true]
+	}`)
+
+	assert.Equal(t, map[string][][]interface{}{
+		`"s3cr3t!"`: {{"cache", "password"}, {"db", "password"}},
+		`5432`:      {{"cache", "port"}, {"db", "port"}},
+		`"app"`:     {{"cache", "user"}, {"db", "user"}},
+		`7`:         {{"ids", 1}, {"ids", 2}},
+	}, js.FindDuplicateValues(0))
+
+	assert.Equal(t, map[string][][]interface{}{
+		`"s3cr3t!"`: {{"cache", "password"}, {"db", "password"}},
+		`5432`:      {{"cache", "port"}, {"db", "port"}},
+	}, js.FindDuplicateValues(4))
+}
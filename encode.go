@@ -0,0 +1,576 @@
+package simplejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// EncodeOption configures how documents are encoded by an Encoder
+type EncodeOption func(*encodeConfig)
+
+type encodeConfig struct {
+	prefix     string
+	indent     string
+	escapeHTML bool
+	sortKeys   bool
+	// floats is set when floats are not written as encoding/json does
+	floats *floatFormat
+	// width is the line width objects and arrays are kept within
+	width int
+	// normalize writes equal numbers the same way
+	normalize bool
+	utf8      UTF8Mode
+	ascii     bool
+	progress  func(int64)
+	// stable writes the format of EncodeStable without relying on
+	// encoding/json or the codec
+	stable bool
+}
+
+type floatFormat struct {
+	precision  int
+	plainBelow float64
+	trimZeros  bool
+}
+
+func newEncodeConfig(opts []EncodeOption) *encodeConfig {
+	c := &encodeConfig{escapeHTML: true}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Indent makes each element of objects and arrays begin on a new line,
+// starting with `prefix` followed by one or more copies of `indent`
+func Indent(prefix, indent string) EncodeOption {
+	return func(c *encodeConfig) {
+		c.prefix = prefix
+		c.indent = indent
+	}
+}
+
+// EscapeHTML sets whether the characters <, > and & are escaped within
+// strings, they are by default as done by encoding/json
+func EscapeHTML(on bool) EncodeOption {
+	return func(c *encodeConfig) {
+		c.escapeHTML = on
+	}
+}
+
+// SortedKeys guarantees keys are sorted at every level of the output,
+// including when a codec that does not sort map keys is in use
+func SortedKeys() EncodeOption {
+	return func(c *encodeConfig) {
+		c.sortKeys = true
+	}
+}
+
+// LineWidth keeps objects and arrays on a single line when they fit within
+// `width` characters, breaking them across lines otherwise:
+//
+//	{
+//	  "id": 1,
+//	  "tags": ["a", "b"],
+//	  "owner": {"id": 2, "name": "x"}
+//	}
+//
+// Lines are indented as set by Indent, with two spaces by default.
+func LineWidth(width int) EncodeOption {
+	return func(c *encodeConfig) {
+		c.width = width
+	}
+}
+
+// NormalizedNumbers writes numbers that are equal the same way, whatever
+// their representation in the document: integer literals are written as is,
+// other numbers in the shortest form decoding to the same float64, as
+// encoding/json writes them, and negative zero as 0. So 1.0, 1e0 and the
+// float64 1 are all written 1, and 0.50 is written 0.5.
+func NormalizedNumbers() EncodeOption {
+	return func(c *encodeConfig) {
+		c.normalize = true
+	}
+}
+
+// FloatPrecision writes floats with `digits` digits after the decimal point,
+// never using exponent notation
+func FloatPrecision(digits int) EncodeOption {
+	return func(c *encodeConfig) {
+		c.floatFormat().precision = digits
+	}
+}
+
+// PlainFloatsBelow writes floats whose absolute value is below `max` without
+// exponent notation, so 1e-7 is written 0.0000001. Larger floats are written
+// as encoding/json does, with an exponent from 1e21.
+func PlainFloatsBelow(max float64) EncodeOption {
+	return func(c *encodeConfig) {
+		c.floatFormat().plainBelow = max
+	}
+}
+
+// TrimFloatZeros removes trailing zeros after the decimal point of floats,
+// and the decimal point itself if no digit remains, which is mostly useful
+// with FloatPrecision
+func TrimFloatZeros() EncodeOption {
+	return func(c *encodeConfig) {
+		c.floatFormat().trimZeros = true
+	}
+}
+
+// floatFormat returns the float settings, enabling them
+func (c *encodeConfig) floatFormat() *floatFormat {
+	if c.floats == nil {
+		c.floats = &floatFormat{precision: -1}
+	}
+	return c.floats
+}
+
+// Encoder writes documents to a stream, each followed by a newline,
+// with formatting settings configured once:
+//
+//	enc := simplejson.NewEncoder(w, simplejson.Indent("", "\t"), simplejson.EscapeHTML(false))
+//	err := enc.Encode(js)
+type Encoder struct {
+	w      io.Writer
+	config *encodeConfig
+}
+
+// NewEncoder returns an Encoder writing to `w`
+func NewEncoder(w io.Writer, opts ...EncodeOption) *Encoder {
+	c := newEncodeConfig(opts)
+	return &Encoder{w: c.output(w), config: c}
+}
+
+// Encode writes the encoding of `j` to the stream
+func (e *Encoder) Encode(j *JSON) error {
+	b, err := e.config.forDocument(j).encode(j.data)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(append(b, '\n'))
+	return err
+}
+
+// EncodeStable returns an encoding of the document meant to be committed to
+// version control, which only changes when the document does: keys are
+// sorted, objects and arrays indented by two spaces, numbers normalized as
+// by NormalizedNumbers, strings are not HTML escaped and the output ends
+// with a newline. Within strings, quotes, backslashes, \n, \r and \t are
+// escaped as such, other control characters and U+2028, U+2029 as \u00XX
+// and \u20XX, and invalid UTF-8 is replaced by U+FFFD. Go values other than
+// those of decoded documents are converted as by NewFromValue.
+//
+// This format is part of the API and will not change across versions of the
+// package nor of Go, it is written by this package alone.
+func (j *JSON) EncodeStable() ([]byte, error) {
+	c := newEncodeConfig([]EncodeOption{SortedKeys(), NormalizedNumbers(), EscapeHTML(false)})
+	c.stable = true
+	convs, _ := converters.Load().(map[reflect.Type]ConverterFunc)
+	data, _, err := (&converter{convs: convs, full: true}).convert(j.Interface(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := c.writeWidth(&buf, data, 0, 0); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// EncodeWith returns the encoding of the document with the given options,
+// see Encoder
+func (j *JSON) EncodeWith(opts ...EncodeOption) ([]byte, error) {
+	return newEncodeConfig(opts).forDocument(j).encode(j.Interface())
+}
+
+// forDocument returns the settings to encode `j` with,
+// which may be overridden by its document
+func (c *encodeConfig) forDocument(j *JSON) *encodeConfig {
+	if j == nil || j.doc == nil || !j.doc.sortKeys || c.sortKeys {
+		return c
+	}
+	d := *c
+	d.sortKeys = true
+	return &d
+}
+
+// encode returns the encoding of `data` according to the settings
+func (c *encodeConfig) encode(data interface{}) ([]byte, error) {
+	if metrics == nil {
+		return c.marshal(data)
+	}
+	start := time.Now()
+	b, err := c.marshal(data)
+	metrics.Encoded(len(b), time.Since(start), err)
+	return b, err
+}
+
+// marshal is encode, without reporting metrics
+func (c *encodeConfig) marshal(data interface{}) ([]byte, error) {
+	if c.width > 0 {
+		var buf bytes.Buffer
+		err := c.writeWidth(&buf, data, 0, 0)
+		return buf.Bytes(), err
+	}
+	var b []byte
+	var err error
+	if c.native() {
+		b, err = codec.Marshal(data)
+	} else {
+		var buf bytes.Buffer
+		err = c.write(&buf, data)
+		b = buf.Bytes()
+	}
+	if err != nil || c.prefix == "" && c.indent == "" {
+		return b, err
+	}
+	var buf bytes.Buffer
+	if err = json.Indent(&buf, b, c.prefix, c.indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// native reports whether documents can be encoded by the codec,
+// rather than by walking the tree
+func (c *encodeConfig) native() bool {
+	return c.escapeHTML && !c.sortKeys && c.floats == nil && !c.normalize &&
+		c.utf8 == UTF8Replace && !c.ascii && preservesNumbers(codec)
+}
+
+// write encodes `data` to `buf`, walking objects and arrays itself
+func (c *encodeConfig) write(buf *bytes.Buffer, data interface{}) error {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		buf.WriteByte('{')
+		for i, key := range sortedKeys(v) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := c.writeString(buf, key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := c.write(buf, v[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, val := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := c.write(buf, val); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case string:
+		return c.writeString(buf, v)
+	case json.Number:
+		if !isValidNumber(string(v)) {
+			return fmt.Errorf("simplejson: invalid number literal %q", string(v))
+		}
+		if (c.floats != nil || c.normalize) && strings.ContainsAny(string(v), ".eE") {
+			f, err := v.Float64()
+			if err != nil {
+				return err
+			}
+			return c.writeFloat(buf, f, 64)
+		}
+		if c.normalize && v == "-0" {
+			v = "0"
+		}
+		buf.WriteString(string(v))
+	case int, int8, int16, int32, int64:
+		buf.WriteString(strconv.FormatInt(reflect.ValueOf(v).Int(), 10))
+	case uint, uint8, uint16, uint32, uint64:
+		buf.WriteString(strconv.FormatUint(reflect.ValueOf(v).Uint(), 10))
+	case float64:
+		return c.writeFloat(buf, v, 64)
+	case float32:
+		return c.writeFloat(buf, float64(v), 32)
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case nil:
+		buf.WriteString("null")
+	default:
+		if c.stable {
+			return fmt.Errorf("simplejson: unsupported value of type %T", v)
+		}
+		b, err := codec.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+	return nil
+}
+
+// writeWidth writes `data` at nesting level `depth`, on a line where `used`
+// characters are already taken, breaking it if it does not fit
+func (c *encodeConfig) writeWidth(buf *bytes.Buffer, data interface{}, depth, used int) error {
+	m, isMap := data.(map[string]interface{})
+	a, isArray := data.([]interface{})
+	if !isMap && !isArray || len(m) == 0 && len(a) == 0 {
+		return c.writeFlat(buf, data)
+	}
+	// without width, every object and array is broken
+	if c.width > 0 {
+		var flat bytes.Buffer
+		if err := c.writeFlat(&flat, data); err != nil {
+			return err
+		}
+		if used+utf8.RuneCount(flat.Bytes()) <= c.width {
+			buf.Write(flat.Bytes())
+			return nil
+		}
+	}
+
+	indent := c.indent
+	if indent == "" {
+		indent = "  "
+	}
+	newline := func(depth int) int {
+		buf.WriteByte('\n')
+		buf.WriteString(c.prefix)
+		buf.WriteString(strings.Repeat(indent, depth))
+		return utf8.RuneCountInString(c.prefix) + depth*utf8.RuneCountInString(indent)
+	}
+	// elements are followed by a comma, except the last one
+	comma := func(i, n int) int {
+		if i < n-1 {
+			return 1
+		}
+		return 0
+	}
+
+	if isMap {
+		buf.WriteByte('{')
+		keys := sortedKeys(m)
+		for i, key := range keys {
+			col := newline(depth + 1)
+			start := buf.Len()
+			if err := c.writeString(buf, key); err != nil {
+				return err
+			}
+			buf.WriteString(": ")
+			col += utf8.RuneCount(buf.Bytes()[start:])
+			if err := c.writeWidth(buf, m[key], depth+1, col+comma(i, len(keys))); err != nil {
+				return err
+			}
+			if comma(i, len(keys)) == 1 {
+				buf.WriteByte(',')
+			}
+		}
+		newline(depth)
+		buf.WriteByte('}')
+		return nil
+	}
+
+	buf.WriteByte('[')
+	for i, val := range a {
+		col := newline(depth + 1)
+		if err := c.writeWidth(buf, val, depth+1, col+comma(i, len(a))); err != nil {
+			return err
+		}
+		if comma(i, len(a)) == 1 {
+			buf.WriteByte(',')
+		}
+	}
+	newline(depth)
+	buf.WriteByte(']')
+	return nil
+}
+
+// writeFlat writes `data` on a single line, with a space after separators
+func (c *encodeConfig) writeFlat(buf *bytes.Buffer, data interface{}) error {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		buf.WriteByte('{')
+		for i, key := range sortedKeys(v) {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if err := c.writeString(buf, key); err != nil {
+				return err
+			}
+			buf.WriteString(": ")
+			if err := c.writeFlat(buf, v[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, val := range v {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if err := c.writeFlat(buf, val); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		return c.write(buf, v)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// preservesNumbers reports whether `c` implements NumberPreserver
+// and preserves numbers
+func preservesNumbers(c Codec) bool {
+	p, ok := c.(NumberPreserver)
+	return ok && p.PreservesNumbers()
+}
+
+// isValidNumber reports whether `s` is a JSON number literal
+func isValidNumber(s string) bool {
+	if s == "" || s[0] != '-' && (s[0] < '0' || s[0] > '9') {
+		return false
+	}
+	return json.Valid([]byte(s))
+}
+
+// writeFloat writes `f` as encoding/json does
+func writeFloat(buf *bytes.Buffer, f float64, bits int) error {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return fmt.Errorf("simplejson: unsupported value %v", f)
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	b := strconv.AppendFloat(nil, f, format, -1, bits)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	buf.Write(b)
+	return nil
+}
+
+// writeFloat writes `f` according to the float settings,
+// which also apply to json.Number values that are not integers
+func (c *encodeConfig) writeFloat(buf *bytes.Buffer, f float64, bits int) error {
+	if c.normalize && f == 0 {
+		f = 0 // negative zero
+	}
+	ff := c.floats
+	if ff == nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return writeFloat(buf, f, bits)
+	}
+	var b []byte
+	switch {
+	case ff.precision >= 0:
+		b = strconv.AppendFloat(nil, f, 'f', ff.precision, bits)
+	case math.Abs(f) < ff.plainBelow:
+		b = strconv.AppendFloat(nil, f, 'f', -1, bits)
+	default:
+		var tmp bytes.Buffer
+		writeFloat(&tmp, f, bits)
+		b = tmp.Bytes()
+	}
+	if ff.trimZeros && bytes.IndexByte(b, '.') >= 0 && bytes.IndexByte(b, 'e') < 0 {
+		b = bytes.TrimRight(b, "0")
+		b = bytes.TrimSuffix(b, []byte("."))
+	}
+	if len(b) == 2 && b[0] == '-' && b[1] == '0' {
+		b = b[1:]
+	}
+	buf.Write(b)
+	return nil
+}
+
+func (c *encodeConfig) writeString(buf *bytes.Buffer, s string) error {
+	if c.utf8 == UTF8Reject && !utf8.ValidString(s) {
+		return fmt.Errorf("simplejson: invalid UTF-8 in string %q", s)
+	}
+	if c.stable {
+		writeStableString(buf, s)
+		return nil
+	}
+	start := buf.Len()
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(c.escapeHTML)
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+	buf.Truncate(buf.Len() - 1) // trailing newline
+	if c.ascii {
+		escapeASCII(buf, start)
+	}
+	return nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+// writeStableString writes `s` quoted with the escaping of EncodeStable
+func writeStableString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(c)
+			case c == '\n':
+				buf.WriteString(`\n`)
+			case c == '\r':
+				buf.WriteString(`\r`)
+			case c == '\t':
+				buf.WriteString(`\t`)
+			case c < 0x20:
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[c>>4])
+				buf.WriteByte(hexDigits[c&0xf])
+			default:
+				buf.WriteByte(c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			buf.WriteString("\ufffd")
+		case r == '\u2028' || r == '\u2029':
+			buf.WriteString(`\u202`)
+			buf.WriteByte(hexDigits[r&0xf])
+		default:
+			buf.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	buf.WriteByte('"')
+}
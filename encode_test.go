@@ -0,0 +1,256 @@
+package simplejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestEncoder(t *testing.T) {
+	js, err := NewJSON([]byte(`{"b":"<a&b>","a":[1,{"d":null,"c":true}]}`))
+	assert.Equal(t, nil, err)
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	assert.Equal(t, nil, enc.Encode(js))
+	assert.Equal(t, nil, enc.Encode(js.Get("a", 0)))
+	assert.Equal(t, "{\"a\":[1,{\"c\":true,\"d\":null}],\"b\":\"\\u003ca\\u0026b\\u003e\"}\n1\n", buf.String())
+
+	buf.Reset()
+	enc = NewEncoder(&buf, EscapeHTML(false), Indent("", "\t"))
+	assert.Equal(t, nil, enc.Encode(js))
+	assert.Equal(t, "{\n\t\"a\": [\n\t\t1,\n\t\t{\n\t\t\t\"c\": true,\n\t\t\t\"d\": null\n\t\t}\n\t],\n\t\"b\": \"<a&b>\"\n}\n", buf.String())
+}
+
+type unsortedCodec struct {
+	StdCodec
+}
+
+func (unsortedCodec) Marshal(v interface{}) ([]byte, error) {
+	if m, ok := v.(map[string]interface{}); ok && len(m) > 1 {
+		return []byte(`"unsorted"`), nil
+	}
+	return StdCodec{}.Marshal(v)
+}
+
+func TestEncoderSortedKeys(t *testing.T) {
+	SetCodec(unsortedCodec{})
+	defer SetCodec(nil)
+
+	js, _ := NewJSON([]byte(`{"b":1,"a":{"y":2,"x":3}}`))
+	var buf bytes.Buffer
+	assert.Equal(t, nil, NewEncoder(&buf).Encode(js))
+	assert.Equal(t, "\"unsorted\"\n", buf.String())
+
+	buf.Reset()
+	assert.Equal(t, nil, NewEncoder(&buf, SortedKeys()).Encode(js))
+	assert.Equal(t, "{\"a\":{\"x\":3,\"y\":2},\"b\":1}\n", buf.String())
+}
+
+// floatCodec mimics engines converting every number to a float64
+type floatCodec struct {
+	StdCodec
+}
+
+func (floatCodec) Marshal(v interface{}) ([]byte, error) {
+	switch n := v.(type) {
+	case json.Number:
+		f, _ := n.Float64()
+		return []byte(strconv.FormatFloat(f, 'g', -1, 64)), nil
+	case int64:
+		return []byte(strconv.FormatFloat(float64(n), 'g', -1, 64)), nil
+	}
+	return StdCodec{}.Marshal(v)
+}
+
+func (floatCodec) PreservesNumbers() bool {
+	return false
+}
+
+func TestEncodePreservesIntegers(t *testing.T) {
+	js, err := NewJSON([]byte(`{"id":1234567890123456789,"e":1.5e3}`))
+	assert.Equal(t, nil, err)
+	js.Set("n", int64(-1234567890123456789))
+	b, _ := js.Encode()
+	assert.Equal(t, `{"e":1.5e3,"id":1234567890123456789,"n":-1234567890123456789}`, string(b))
+
+	SetCodec(floatCodec{})
+	defer SetCodec(nil)
+
+	js, err = NewJSON([]byte(`{"id":1234567890123456789,"f":1.5,"small":1e-7}`))
+	assert.Equal(t, nil, err)
+	js.Set("n", int64(-1234567890123456789))
+	js.Set("u", uint64(18446744073709551615))
+	js.Set("g", float32(0.1))
+
+	b, err = js.Encode()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"f":1.5,"g":0.1,"id":1234567890123456789,"n":-1234567890123456789,"small":1e-7,"u":18446744073709551615}`, string(b))
+
+	b, err = js.EncodePretty()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, bytes.Contains(b, []byte(`"id": 1234567890123456789`)))
+
+	js.Set("bad", json.Number("12abc"))
+	_, err = js.Encode()
+	assert.Equal(t, `simplejson: invalid number literal "12abc"`, err.Error())
+}
+
+func TestWriteFloat(t *testing.T) {
+	for _, f := range []float64{0, 1, -2.5, 1e20, 1e21, 1e-6, 1e-7, 123456789.125, 1.234567890123457e+18} {
+		want, _ := json.Marshal(f)
+		var buf bytes.Buffer
+		assert.Equal(t, nil, writeFloat(&buf, f, 64))
+		assert.Equal(t, string(want), buf.String())
+	}
+	var buf bytes.Buffer
+	assert.NotEqual(t, nil, writeFloat(&buf, math.Inf(1), 64))
+}
+
+func TestFloatFormat(t *testing.T) {
+	js := New()
+	js.Set("small", 1e-7)
+	js.Set("pi", 3.14159)
+	js.Set("whole", 2.0)
+	js.Set("neg", -0.0001)
+	js.Set("big", 1e22)
+	js.Set("id", json.Number("10"))
+	js.Set("num", json.Number("2.50"))
+
+	b, err := js.EncodeWith()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"big":1e+22,"id":10,"neg":-0.0001,"num":2.50,"pi":3.14159,"small":1e-7,"whole":2}`, string(b))
+
+	b, _ = js.EncodeWith(PlainFloatsBelow(1))
+	assert.Equal(t, `{"big":1e+22,"id":10,"neg":-0.0001,"num":2.5,"pi":3.14159,"small":0.0000001,"whole":2}`, string(b))
+
+	b, _ = js.EncodeWith(FloatPrecision(3))
+	assert.Equal(t, `{"big":10000000000000000000000.000,"id":10,"neg":-0.000,"num":2.500,"pi":3.142,"small":0.000,"whole":2.000}`, string(b))
+
+	b, _ = js.EncodeWith(FloatPrecision(3), TrimFloatZeros())
+	assert.Equal(t, `{"big":10000000000000000000000,"id":10,"neg":0,"num":2.5,"pi":3.142,"small":0,"whole":2}`, string(b))
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, NewEncoder(&buf, FloatPrecision(1)).Encode(js.Get("pi")))
+	assert.Equal(t, "3.1\n", buf.String())
+}
+
+func TestLineWidth(t *testing.T) {
+	js, err := NewJSON([]byte(`{"id":1,"tags":["a","b"],"owner":{"id":2,"name":"x"},"items":[{"sku":"abc","qty":10},{"sku":"defghij","qty":200}],"empty":{}}`))
+	assert.Equal(t, nil, err)
+
+	b, err := js.EncodeWith(LineWidth(40))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{
+  "empty": {},
+  "id": 1,
+  "items": [
+    {"qty": 10, "sku": "abc"},
+    {"qty": 200, "sku": "defghij"}
+  ],
+  "owner": {"id": 2, "name": "x"},
+  "tags": ["a", "b"]
+}`, string(b))
+
+	b, _ = js.Get("items").EncodeWith(LineWidth(80))
+	assert.Equal(t, `[{"qty": 10, "sku": "abc"}, {"qty": 200, "sku": "defghij"}]`, string(b))
+
+	b, _ = js.Get("items").EncodeWith(LineWidth(30), Indent("> ", "\t"))
+	assert.Equal(t, "[\n> \t{\"qty\": 10, \"sku\": \"abc\"},\n> \t{\n> \t\t\"qty\": 200,\n> \t\t\"sku\": \"defghij\"\n> \t}\n> ]", string(b))
+}
+
+func TestEncodeStable(t *testing.T) {
+	js, err := NewJSON([]byte(`{"z":[1.0,1e0,0.50,-0,-0.0,100,1e2],"a":{"y":"<b>","x":12345678901234567890}}`))
+	assert.Equal(t, nil, err)
+	js.Set("f", 2.0)
+
+	b, err := js.EncodeStable()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{
+  "a": {
+    "x": 12345678901234567890,
+    "y": "<b>"
+  },
+  "f": 2,
+  "z": [
+    1,
+    1,
+    0.5,
+    0,
+    0,
+    100,
+    100
+  ]
+}
+`, string(b))
+
+	b, _ = js.Get("z").EncodeWith(NormalizedNumbers())
+	assert.Equal(t, `[1,1,0.5,0,0,100,100]`, string(b))
+}
+
+// TestEncodeStableGolden pins the output of EncodeStable, which must never change
+func TestEncodeStableGolden(t *testing.T) {
+	type point struct {
+		X int     `json:"x"`
+		Y float64 `json:"y,omitempty"`
+	}
+	js := New()
+	js.Set("strings", []interface{}{"\b\f\n\r\t\x00\x1f", `"\\/`, "<a&b>", "é\u2028\u2029💡", "\xff"})
+	js.Set("numbers", []interface{}{1e21, 1e-7, 0.1, float32(0.1), -0.0, int8(-8), uint64(1 << 63), json.Number("1.50"), json.Number("-0")})
+	js.Set("other", []interface{}{true, false, nil, map[string]interface{}{}, []interface{}{}})
+	js.Set("go", []interface{}{point{X: 1}, map[string]int{"b": 2, "a": 1}, []string{"s"}})
+
+	b, err := js.EncodeStable()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{
+  "go": [
+    {
+      "x": 1
+    },
+    {
+      "a": 1,
+      "b": 2
+    },
+    [
+      "s"
+    ]
+  ],
+  "numbers": [
+    1e+21,
+    1e-7,
+    0.1,
+    0.1,
+    0,
+    -8,
+    9223372036854775808,
+    1.5,
+    0
+  ],
+  "other": [
+    true,
+    false,
+    null,
+    {},
+    []
+  ],
+  "strings": [
+    "\u0008\u000c\n\r\t\u0000\u001f",
+    "\"\\\\/",
+    "<a&b>",
+    "é\u2028\u2029💡",
+    "�"
+  ]
+}
+`, string(b))
+
+	b, err = New().EncodeStable()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "{}\n", string(b))
+	b, err = mustJSON(`"x"`).EncodeStable()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "\"x\"\n", string(b))
+}
@@ -0,0 +1,81 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ApplyEnv overrides values of the document with the environment variables
+// starting with `prefix`. The rest of the variable name is split by `sep`
+// into a path of keys, matched case insensitively against existing keys and
+// lower cased otherwise, so with prefix "APP_" and sep "__":
+//
+//	APP_SERVER__PORT=8080  sets  {"server": {"port": 8080}}
+//
+// Values are converted to booleans and numbers when they parse as such,
+// and kept as strings otherwise.
+func (j *JSON) ApplyEnv(prefix, sep string) error {
+	return j.applyEnv(os.Environ(), prefix, sep)
+}
+
+func (j *JSON) applyEnv(environ []string, prefix, sep string) error {
+	j = j.orMissing()
+	if j.IsFrozen() {
+		return ErrFrozen
+	}
+	// sorted for nested variables to be applied after their parents
+	sort.Strings(environ)
+	for _, kv := range environ {
+		if !strings.HasPrefix(kv, prefix) {
+			continue
+		}
+		i := strings.IndexByte(kv, '=')
+		if i < len(prefix) {
+			continue
+		}
+		name, value := kv[len(prefix):i], kv[i+1:]
+		if name == "" {
+			continue
+		}
+		if err := j.setAt(j.envBranch(strings.Split(name, sep)), inferType(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envBranch maps variable name segments to existing keys where possible
+func (j *JSON) envBranch(segments []string) []interface{} {
+	branch := make([]interface{}, len(segments))
+	data := j.data
+	for i, s := range segments {
+		key := strings.ToLower(s)
+		if m, ok := data.(map[string]interface{}); ok {
+			for k := range m {
+				if strings.EqualFold(k, s) {
+					key = k
+					break
+				}
+			}
+			data = m[key]
+		} else {
+			data = nil
+		}
+		branch[i] = key
+	}
+	return branch
+}
+
+// inferType converts textual values to booleans and numbers when possible
+func inferType(s string) interface{} {
+	if b, err := strconv.ParseBool(s); err == nil && (s == "true" || s == "false") {
+		return b
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil && json.Valid([]byte(s)) {
+		return json.Number(s)
+	}
+	return s
+}
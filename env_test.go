@@ -0,0 +1,39 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestApplyEnv(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"server":{"port":80,"Host":"a"},"debug":false}`))
+
+	err := js.applyEnv([]string{
+		"APP_SERVER__PORT=8080",
+		"APP_SERVER__HOST=example.com",
+		"APP_DEBUG=true",
+		"APP_LIMITS__RATE=1.5",
+		"APP_NAME=0755",
+		"OTHER_X=1",
+		"APP_=ignored",
+	}, "APP_", "__")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, json.Number("8080"), js.Get("server", "port").Interface())
+	assert.Equal(t, "example.com", js.Get("server", "Host").String())
+	assert.Equal(t, true, js.Get("debug").Bool())
+	assert.Equal(t, 1.5, js.Get("limits", "rate").Float64())
+	assert.Equal(t, "0755", js.Get("name").String())
+	_, ok := js.CheckGet("x")
+	assert.Equal(t, false, ok)
+
+	err = js.applyEnv([]string{"APP_DEBUG__LEVEL=1"}, "APP_", "__")
+	assert.NotEqual(t, nil, err)
+
+	os.Setenv("SIMPLEJSON_TEST_A", "b")
+	defer os.Unsetenv("SIMPLEJSON_TEST_A")
+	assert.Equal(t, nil, js.ApplyEnv("SIMPLEJSON_TEST_", "__"))
+	assert.Equal(t, "b", js.Get("a").String())
+}
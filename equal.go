@@ -0,0 +1,150 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+// EqualIgnoring reports whether the document and `other` hold the same JSON
+// value, numbers compared by value, except at the dotted paths `ignore`, such
+// as volatile timestamps or request IDs. A `*` segment matches every key of an
+// object or element of an array:
+//
+//	ok := got.EqualIgnoring(want, "meta.requestId", "items.*.updatedAt")
+//
+// Ignored values may differ or be missing from either document.
+func (j *JSON) EqualIgnoring(other *JSON, ignore ...string) bool {
+	patterns := make([][]string, len(ignore))
+	for i, p := range ignore {
+		patterns[i] = splitPath(p)
+	}
+	return equalIgnoring(j.Interface(), other.Interface(), nil, patterns)
+}
+
+// equalIgnoring is deepEqual, skipping the values below `path` matching `patterns`
+func equalIgnoring(a, b interface{}, path []string, patterns [][]string) bool {
+	if len(patterns) == 0 {
+		return deepEqual(a, b)
+	}
+	ignored := func(key string) bool {
+		for _, p := range patterns {
+			if len(p) != len(path)+1 {
+				continue
+			}
+			match := true
+			for i, s := range append(path[:len(path):len(path)], key) {
+				if p[i] != "*" && p[i] != s {
+					match = false
+					break
+				}
+			}
+			if match {
+				return true
+			}
+		}
+		return false
+	}
+	at := func(key string) []string {
+		return append(path[:len(path):len(path)], key)
+	}
+
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, val := range av {
+			if ignored(key) {
+				continue
+			}
+			other, ok := bv[key]
+			if !ok || !equalIgnoring(val, other, at(key), patterns) {
+				return false
+			}
+		}
+		for key := range bv {
+			if _, ok := av[key]; !ok && !ignored(key) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok {
+			return false
+		}
+		for i := 0; i < len(av) || i < len(bv); i++ {
+			key := fmt.Sprint(i)
+			if ignored(key) {
+				continue
+			}
+			if i >= len(av) || i >= len(bv) || !equalIgnoring(av[i], bv[i], at(key), patterns) {
+				return false
+			}
+		}
+		return true
+	}
+	return deepEqual(a, b)
+}
+
+// deepEqual reports whether two values are the same JSON value,
+// numbers being compared by value regardless of their representation
+func deepEqual(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for key, val := range av {
+			other, ok := bv[key]
+			if !ok || !deepEqual(val, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !deepEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if isNumber(a) && isNumber(b) {
+		x, xok := numberRat(a)
+		y, yok := numberRat(b)
+		return xok && yok && x.Cmp(y) == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// numberRat returns the exact value of a number
+func numberRat(v interface{}) (*big.Rat, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		return new(big.Rat).SetString(string(n))
+	case float32:
+		r := new(big.Rat).SetFloat64(float64(n))
+		return r, r != nil
+	case float64:
+		r := new(big.Rat).SetFloat64(n)
+		return r, r != nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(rv.Uint())), true
+	}
+	return nil, false
+}
@@ -0,0 +1,24 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestEqualIgnoring(t *testing.T) {
+	got := mustJSON(`{"meta":{"requestId":"abc","v":1},"items":[{"id":1,"at":"t1"},{"id":2.0,"at":"t2"}]}`)
+	want := mustJSON(`{"meta":{"v":1.0},"items":[{"id":1,"at":"x"},{"id":2}]}`)
+
+	assert.Equal(t, false, got.EqualIgnoring(want))
+	assert.Equal(t, false, got.EqualIgnoring(want, "meta.requestId"))
+	assert.Equal(t, true, got.EqualIgnoring(want, "meta.requestId", "items.*.at"))
+	assert.Equal(t, true, want.EqualIgnoring(got, "meta.requestId", "items.*.at"))
+	assert.Equal(t, true, got.EqualIgnoring(want, "meta", "items"))
+	assert.Equal(t, false, got.EqualIgnoring(want, "meta", "items.0"))
+	assert.Equal(t, true, got.EqualIgnoring(want, "meta", "items.0", "items.1.at"))
+
+	assert.Equal(t, true, got.EqualIgnoring(got.Clone()))
+	assert.Equal(t, false, got.EqualIgnoring(nil))
+	assert.Equal(t, true, (*JSON)(nil).EqualIgnoring(nil))
+}
@@ -0,0 +1,81 @@
+package simplejson
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// ETag returns a strong HTTP entity tag of the document, such as
+// `"5d41402abc4b2a76b9719d911017c592"`. It is computed over the canonical
+// encoding of the document, sorted keys and normalized numbers, so documents
+// holding the same values have the same tag. ETag returns an empty string if
+// the document can't be encoded.
+func (j *JSON) ETag() string {
+	b, err := j.EncodeWith(SortedKeys(), NormalizedNumbers(), EscapeHTML(false))
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// CheckPreconditions evaluates the If-Match and If-None-Match headers of `r`
+// against the current state of a resource, `j` being nil when it does not
+// exist. It returns true when the request should proceed, or false and the
+// status to reply with otherwise:
+//
+//	if status, ok := simplejson.CheckPreconditions(r, doc); !ok {
+//		w.WriteHeader(status)
+//		return
+//	}
+//
+// The status is http.StatusNotModified for GET and HEAD requests whose
+// If-None-Match matches, http.StatusPreconditionFailed in other failures
+// and http.StatusOK when the request should proceed.
+func CheckPreconditions(r *http.Request, j *JSON) (int, bool) {
+	etag := ""
+	if j != nil {
+		etag = j.ETag()
+	}
+
+	if im := r.Header.Get("If-Match"); im != "" {
+		if !matchETag(im, etag, j != nil, true) {
+			return http.StatusPreconditionFailed, false
+		}
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if matchETag(inm, etag, j != nil, false) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				return http.StatusNotModified, false
+			}
+			return http.StatusPreconditionFailed, false
+		}
+	}
+	return http.StatusOK, true
+}
+
+// matchETag reports whether the `header` list of entity tags matches `etag`,
+// using the strong comparison of RFC 7232 if `strong` is set
+func matchETag(header, etag string, exists, strong bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return exists
+	}
+	if !exists {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(tag, "W/") {
+			if strong {
+				continue
+			}
+			tag = tag[2:]
+		}
+		if tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package simplejson
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestETag(t *testing.T) {
+	a, _ := NewJSON([]byte(`{"a":1.0,"b":[1,2]}`))
+	b, _ := NewJSON([]byte(`{"b":[1,2],"a":1}`))
+	tag := a.ETag()
+	assert.Equal(t, 34, len(tag))
+	assert.Equal(t, tag, b.ETag())
+
+	b.Set("c", true)
+	assert.NotEqual(t, tag, b.ETag())
+}
+
+func TestCheckPreconditions(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"a":1}`))
+	tag := js.ETag()
+
+	cases := []struct {
+		method, header, value string
+		doc                   *JSON
+		status                int
+		ok                    bool
+	}{
+		{"GET", "", "", js, http.StatusOK, true},
+		{"GET", "If-None-Match", tag, js, http.StatusNotModified, false},
+		{"GET", "If-None-Match", `"other", W/` + tag, js, http.StatusNotModified, false},
+		{"GET", "If-None-Match", `"other"`, js, http.StatusOK, true},
+		{"PUT", "If-None-Match", "*", js, http.StatusPreconditionFailed, false},
+		{"PUT", "If-None-Match", "*", nil, http.StatusOK, true},
+		{"PUT", "If-Match", tag, js, http.StatusOK, true},
+		{"PUT", "If-Match", "W/" + tag, js, http.StatusPreconditionFailed, false},
+		{"PUT", "If-Match", `"stale"`, js, http.StatusPreconditionFailed, false},
+		{"PUT", "If-Match", "*", nil, http.StatusPreconditionFailed, false},
+		{"DELETE", "If-Match", `"stale", ` + tag, js, http.StatusOK, true},
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest(c.method, "/doc", nil)
+		if c.header != "" {
+			r.Header.Set(c.header, c.value)
+		}
+		status, ok := CheckPreconditions(r, c.doc)
+		assert.Equal(t, c.status, status)
+		assert.Equal(t, c.ok, ok)
+	}
+}
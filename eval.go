@@ -0,0 +1,610 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Eval evaluates the expression `expr` against the document and returns its result.
+//
+// Expressions are made of:
+//
+//	literals     numbers, "strings" or 'strings', true, false and null
+//	paths        payload.items[0].name or payload["some key"], null when missing
+//	arithmetic   + - * / % on numbers, + concatenates strings
+//	comparisons  == != < <= > >=, numbers and strings are ordered,
+//	             ordering with null is false
+//	logic        && || !, null and false are false, any other value true
+//	functions    len(x), contains(x, y), startsWith(s, p), endsWith(s, p),
+//	             lower(s), upper(s), exists(path)
+//
+// Arithmetic is exact, so 0.1 + 0.2 == 0.3 holds.
+func (j *JSON) Eval(expr string) (*JSON, error) {
+	j = j.orMissing()
+	p := &exprParser{name: "eval", expr: expr, ops: exprOperators}
+	p.next()
+	n, err := p.parse(0)
+	if err == nil && p.tok.kind != tokEOF {
+		err = p.errorf("unexpected %s", p.tok)
+	}
+	if err != nil {
+		return nil, err
+	}
+	val, err := n.eval(j.data)
+	if err != nil {
+		return nil, fmt.Errorf("simplejson: eval %q: %w", expr, err)
+	}
+	return j.wrap(val), nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+// exprParser is a precedence climbing parser of Eval expressions
+type exprParser struct {
+	// name is the function parsing, used in errors
+	name string
+	expr string
+	// ops are the operators recognized by the lexer
+	ops []string
+	pos int
+	tok token
+	err error
+}
+
+func (p *exprParser) errorf(format string, args ...interface{}) error {
+	return p.errorAt(p.tok.pos, format, args...)
+}
+
+func (p *exprParser) errorAt(pos int, format string, args ...interface{}) error {
+	return fmt.Errorf("simplejson: %s %q: offset %d: %s", p.name, p.expr, pos, fmt.Sprintf(format, args...))
+}
+
+// operators are ordered longest first, so "<=" is not read as "<"
+var exprOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")", "[", "]", ",", "."}
+
+// next reads the next token of the expression
+func (p *exprParser) next() {
+	for p.pos < len(p.expr) && isSpace(p.expr[p.pos]) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos == len(p.expr) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+
+	c := p.expr[p.pos]
+	switch {
+	case c >= '0' && c <= '9':
+		for ; p.pos < len(p.expr); p.pos++ {
+			c := p.expr[p.pos]
+			exponent := c == '+' || c == '-'
+			if exponent && p.expr[p.pos-1] != 'e' && p.expr[p.pos-1] != 'E' ||
+				!exponent && strings.IndexByte("0123456789.eE", c) < 0 {
+				break
+			}
+		}
+		p.tok = token{kind: tokNumber, text: p.expr[start:p.pos], pos: start}
+	case c == '"' || c == '\'':
+		p.pos++
+		var b strings.Builder
+		for p.pos < len(p.expr) && p.expr[p.pos] != c {
+			if p.expr[p.pos] == '\\' && p.pos+1 < len(p.expr) {
+				p.pos++
+			}
+			b.WriteByte(p.expr[p.pos])
+			p.pos++
+		}
+		if p.pos == len(p.expr) {
+			p.tok = token{kind: tokOp, text: string(c), pos: start}
+			p.err = p.errorf("unterminated string")
+			return
+		}
+		p.pos++
+		p.tok = token{kind: tokString, text: b.String(), pos: start}
+	case isIdentStart(p.expr[p.pos:]):
+		for p.pos < len(p.expr) {
+			r, size := utf8.DecodeRuneInString(p.expr[p.pos:])
+			if r != '_' && r != '$' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				break
+			}
+			p.pos += size
+		}
+		p.tok = token{kind: tokIdent, text: p.expr[start:p.pos], pos: start}
+	default:
+		for _, op := range p.ops {
+			if strings.HasPrefix(p.expr[p.pos:], op) {
+				p.pos += len(op)
+				p.tok = token{kind: tokOp, text: op, pos: start}
+				return
+			}
+		}
+		p.tok = token{kind: tokOp, text: p.expr[start : start+1], pos: start}
+		p.err = p.errorf("unexpected character %q", c)
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isIdentStart(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+// binary operators by precedence, from lowest to highest
+var exprPrecedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+// parse parses an expression made of operators of at least `min` precedence
+func (p *exprParser) parse(min int) (exprNode, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.err == nil && p.tok.kind == tokOp {
+		prec, ok := exprPrecedence[p.tok.text]
+		if !ok || prec <= min {
+			break
+		}
+		op := p.tok.text
+		p.next()
+		right, err := p.parse(prec)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+	return left, p.err
+}
+
+func (p *exprParser) unary() (exprNode, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.tok.kind == tokOp && (p.tok.text == "!" || p.tok.text == "-") {
+		op := p.tok.text
+		p.next()
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, operand: operand}, nil
+	}
+	return p.primary()
+}
+
+func (p *exprParser) primary() (exprNode, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokNumber:
+		if !isValidNumber(tok.text) {
+			return nil, p.errorf("invalid number %s", tok)
+		}
+		p.next()
+		return literalNode{json.Number(tok.text)}, p.err
+	case tokString:
+		p.next()
+		return literalNode{tok.text}, p.err
+	case tokIdent:
+		p.next()
+		switch tok.text {
+		case "true":
+			return literalNode{true}, p.err
+		case "false":
+			return literalNode{false}, p.err
+		case "null":
+			return literalNode{nil}, p.err
+		}
+		if p.tok.kind == tokOp && p.tok.text == "(" {
+			return p.call(tok)
+		}
+		return p.path(tok.text)
+	case tokOp:
+		if tok.text == "(" {
+			p.next()
+			n, err := p.parse(0)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return n, nil
+		}
+	}
+	return nil, p.errorf("unexpected %s", tok)
+}
+
+func (p *exprParser) expect(op string) error {
+	if p.err != nil {
+		return p.err
+	}
+	if p.tok.kind != tokOp || p.tok.text != op {
+		return p.errorf("expected %q, found %s", op, p.tok)
+	}
+	p.next()
+	return p.err
+}
+
+// path parses the selectors following the identifier `first`
+func (p *exprParser) path(first string) (exprNode, error) {
+	n := &pathNode{branch: []interface{}{first}}
+	for p.err == nil && p.tok.kind == tokOp {
+		switch p.tok.text {
+		case ".":
+			p.next()
+			if p.tok.kind != tokIdent {
+				return nil, p.errorf("expected a key after \".\", found %s", p.tok)
+			}
+			n.branch = append(n.branch, p.tok.text)
+			p.next()
+		case "[":
+			p.next()
+			sign := ""
+			if p.tok.kind == tokOp && p.tok.text == "-" {
+				sign = "-"
+				p.next()
+			}
+			switch {
+			case p.tok.kind == tokString && sign == "":
+				n.branch = append(n.branch, p.tok.text)
+			case p.tok.kind == tokNumber:
+				i, err := strconv.Atoi(sign + p.tok.text)
+				if err != nil {
+					return nil, p.errorf("invalid index %s", p.tok)
+				}
+				n.branch = append(n.branch, i)
+			default:
+				return nil, p.errorf("expected an index or key, found %s", p.tok)
+			}
+			p.next()
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+		default:
+			return n, nil
+		}
+	}
+	return n, p.err
+}
+
+// call parses the arguments of a call to the function `name`
+func (p *exprParser) call(name token) (exprNode, error) {
+	fn, ok := exprFuncs[name.text]
+	if !ok && name.text != "exists" {
+		return nil, p.errorAt(name.pos, "unknown function %s", name)
+	}
+	p.next()
+	var args []exprNode
+	for p.err == nil && !(p.tok.kind == tokOp && p.tok.text == ")") {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parse(0)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	if name.text == "exists" {
+		var path *pathNode
+		if len(args) == 1 {
+			path, _ = args[0].(*pathNode)
+		}
+		if path == nil {
+			return nil, p.errorAt(name.pos, "exists takes a path")
+		}
+		return existsNode{path}, nil
+	}
+	if len(args) != fn.arity {
+		return nil, p.errorAt(name.pos, "%s takes %d arguments, got %d", name.text, fn.arity, len(args))
+	}
+	return &callNode{name: name.text, fn: fn.fn, args: args}, nil
+}
+
+// exprNode is a node of a parsed expression
+type exprNode interface {
+	eval(doc interface{}) (interface{}, error)
+}
+
+type literalNode struct {
+	val interface{}
+}
+
+func (n literalNode) eval(doc interface{}) (interface{}, error) {
+	return n.val, nil
+}
+
+type pathNode struct {
+	branch []interface{}
+}
+
+func (n *pathNode) eval(doc interface{}) (interface{}, error) {
+	val, _ := lookup(doc, n.branch)
+	return val, nil
+}
+
+type existsNode struct {
+	path *pathNode
+}
+
+func (n existsNode) eval(doc interface{}) (interface{}, error) {
+	_, ok := lookup(doc, n.path.branch)
+	return ok, nil
+}
+
+type unaryNode struct {
+	op      string
+	operand exprNode
+}
+
+func (n *unaryNode) eval(doc interface{}) (interface{}, error) {
+	val, err := n.operand.eval(doc)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		return !truthy(val), nil
+	}
+	r, ok := numberRat(val)
+	if !ok {
+		return nil, fmt.Errorf("cannot negate %s", jsonType(val))
+	}
+	return ratNumber(r.Neg(r)), nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *binaryNode) eval(doc interface{}) (interface{}, error) {
+	left, err := n.left.eval(doc)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "&&":
+		if !truthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(doc)
+		return truthy(right), err
+	case "||":
+		if truthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(doc)
+		return truthy(right), err
+	}
+
+	right, err := n.right.eval(doc)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return deepEqual(left, right), nil
+	case "!=":
+		return !deepEqual(left, right), nil
+	case "<", "<=", ">", ">=":
+		return compare(n.op, left, right)
+	}
+	return arithmetic(n.op, left, right)
+}
+
+// truthy reports whether `val` holds in a logical expression
+func truthy(val interface{}) bool {
+	return val != nil && val != false
+}
+
+// compare orders numbers and strings, comparisons involving null are false
+func compare(op string, left, right interface{}) (interface{}, error) {
+	if left == nil || right == nil {
+		return false, nil
+	}
+	var c int
+	if x, ok := numberRat(left); ok {
+		y, ok := numberRat(right)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare number with %s", jsonType(right))
+		}
+		c = x.Cmp(y)
+	} else if x, ok := left.(string); ok {
+		y, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare string with %s", jsonType(right))
+		}
+		c = strings.Compare(x, y)
+	} else {
+		return nil, fmt.Errorf("cannot compare %s", jsonType(left))
+	}
+	switch op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	}
+	return c >= 0, nil
+}
+
+func arithmetic(op string, left, right interface{}) (interface{}, error) {
+	if op == "+" {
+		if x, ok := left.(string); ok {
+			if y, ok := right.(string); ok {
+				return x + y, nil
+			}
+		}
+	}
+	x, xok := numberRat(left)
+	y, yok := numberRat(right)
+	if !xok || !yok {
+		return nil, fmt.Errorf("invalid operation %s %s %s", jsonType(left), op, jsonType(right))
+	}
+	r := new(big.Rat)
+	switch op {
+	case "+":
+		r.Add(x, y)
+	case "-":
+		r.Sub(x, y)
+	case "*":
+		r.Mul(x, y)
+	case "/":
+		if y.Sign() == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		r.Quo(x, y)
+	case "%":
+		if !x.IsInt() || !y.IsInt() {
+			return nil, fmt.Errorf("%% requires integers")
+		}
+		if y.Sign() == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		r.SetInt(new(big.Int).Rem(x.Num(), y.Num()))
+	}
+	return ratNumber(r), nil
+}
+
+// ratNumber returns `r` as a json.Number, as a float64 if it has no finite
+// decimal representation
+func ratNumber(r *big.Rat) interface{} {
+	if r.IsInt() {
+		return json.Number(r.Num().String())
+	}
+	if prec, exact := r.FloatPrec(); exact {
+		return json.Number(r.FloatString(prec))
+	}
+	f, _ := r.Float64()
+	return f
+}
+
+type callNode struct {
+	name string
+	fn   func(args []interface{}) (interface{}, error)
+	args []exprNode
+}
+
+func (n *callNode) eval(doc interface{}) (interface{}, error) {
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		val, err := arg.eval(doc)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = val
+	}
+	val, err := n.fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return val, nil
+}
+
+type exprFunc struct {
+	arity int
+	fn    func(args []interface{}) (interface{}, error)
+}
+
+var exprFuncs = map[string]exprFunc{
+	"len": {1, func(args []interface{}) (interface{}, error) {
+		switch v := args[0].(type) {
+		case nil:
+			return json.Number("0"), nil
+		case string:
+			return json.Number(strconv.Itoa(utf8.RuneCountInString(v))), nil
+		case []interface{}:
+			return json.Number(strconv.Itoa(len(v))), nil
+		case map[string]interface{}:
+			return json.Number(strconv.Itoa(len(v))), nil
+		}
+		return nil, fmt.Errorf("invalid argument %s", jsonType(args[0]))
+	}},
+	"contains": {2, func(args []interface{}) (interface{}, error) {
+		switch v := args[0].(type) {
+		case nil:
+			return false, nil
+		case string:
+			s, ok := args[1].(string)
+			return ok && strings.Contains(v, s), nil
+		case []interface{}:
+			for _, elem := range v {
+				if deepEqual(elem, args[1]) {
+					return true, nil
+				}
+			}
+			return false, nil
+		case map[string]interface{}:
+			s, ok := args[1].(string)
+			if ok {
+				_, ok = v[s]
+			}
+			return ok, nil
+		}
+		return nil, fmt.Errorf("invalid argument %s", jsonType(args[0]))
+	}},
+	"startsWith": {2, stringsFunc(func(s, prefix string) interface{} { return strings.HasPrefix(s, prefix) })},
+	"endsWith":   {2, stringsFunc(func(s, suffix string) interface{} { return strings.HasSuffix(s, suffix) })},
+	"lower":      {1, stringsFunc(func(s, _ string) interface{} { return strings.ToLower(s) })},
+	"upper":      {1, stringsFunc(func(s, _ string) interface{} { return strings.ToUpper(s) })},
+}
+
+// stringsFunc returns a function of one or two strings, null when given null
+func stringsFunc(fn func(s, t string) interface{}) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		strs := make([]string, 2)
+		for i, arg := range args {
+			if arg == nil {
+				return nil, nil
+			}
+			s, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid argument %s", jsonType(arg))
+			}
+			strs[i] = s
+		}
+		return fn(strs[0], strs[1]), nil
+	}
+}
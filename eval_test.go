@@ -0,0 +1,62 @@
+package simplejson
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestEval(t *testing.T) {
+	js, err := NewJSON([]byte(`{"payload":{"amount":1500,"country":"DE","tags":["a","b"],"price":0.1,"some key":3,"name":"Widget"},"limits":[10,20]}`))
+	assert.Equal(t, nil, err)
+
+	cases := []struct {
+		expr string
+		want string
+	}{
+		{`payload.amount > 1000 && payload.country == "DE"`, `true`},
+		{`payload.amount > 1000 && payload.country == 'FR'`, `false`},
+		{`payload.missing > 1 || !payload.missing`, `true`},
+		{`payload.missing == null`, `true`},
+		{`payload.price + 0.2 == 0.3`, `true`},
+		{`payload.amount * 2 - limits[1] / 4`, `2995`},
+		{`-payload.amount % 7 + 1e1`, `8`},
+		{`1 / 3`, `0.3333333333333333`},
+		{`7 / 2`, `3.5`},
+		{`payload["some key"] >= limits[-2] - 7`, `true`},
+		{`len(payload.tags) + len(payload.name) + len(payload)`, `14`},
+		{`contains(payload.tags, "b") && contains(payload.name, "idg") && contains(payload, "amount")`, `true`},
+		{`startsWith(lower(payload.name), "wid") && endsWith(upper(payload.name), "GET")`, `true`},
+		{`exists(payload.tags[1]) && !exists(payload.tags[2])`, `true`},
+		{`"a" < "b" && payload.name + "s"  == "Widgets"`, `true`},
+		{`payload.tags`, `["a","b"]`},
+		{`(1 + 2) * 3`, `9`},
+	}
+	for _, c := range cases {
+		res, err := js.Eval(c.expr)
+		assert.Equal(t, nil, err, c.expr)
+		b, _ := res.Encode()
+		assert.Equal(t, c.want, string(b), c.expr)
+	}
+
+	for expr, msg := range map[string]string{
+		`payload.amount >`:    `simplejson: eval "payload.amount >": offset 16: unexpected end of expression`,
+		`"abc`:                `simplejson: eval "\"abc": offset 0: unterminated string`,
+		`foo(1)`:              `simplejson: eval "foo(1)": offset 0: unknown function "foo"`,
+		`len(1, 2)`:           `simplejson: eval "len(1, 2)": offset 0: len takes 1 arguments, got 2`,
+		`payload.country > 1`: `simplejson: eval "payload.country > 1": cannot compare string with number`,
+		`payload.amount / 0`:  `simplejson: eval "payload.amount / 0": division by zero`,
+		`payload.tags + 1`:    `simplejson: eval "payload.tags + 1": invalid operation array + number`,
+		`len(true)`:           `simplejson: eval "len(true)": len: invalid argument bool`,
+		`1 2`:                 `simplejson: eval "1 2": offset 2: unexpected "2"`,
+		`payload.amount # 2`:  `simplejson: eval "payload.amount # 2": offset 15: unexpected character '#'`,
+	} {
+		_, err := js.Eval(expr)
+		assert.Equal(t, msg, err.Error())
+	}
+
+	_, err = js.Eval("len(true)")
+	assert.Equal(t, "len: invalid argument bool", errors.Unwrap(err).Error())
+	assert.Equal(t, "invalid argument bool", errors.Unwrap(errors.Unwrap(err)).Error())
+}
@@ -0,0 +1,162 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expand substitutes `${name}` placeholders within the string values of the
+// document. `name` is first looked up as a dotted path within the document,
+// such as `${server.host}` or `${hosts.0}`, then passed to `lookup`, which
+// may be nil, os.LookupEnv expands environment variables.
+//
+// A string made of a single placeholder referencing a non string value of the
+// document takes that value, keeping its type. `$$` escapes a dollar sign.
+// Referenced values are expanded first, unresolved placeholders and circular
+// references are reported as errors.
+func (j *JSON) Expand(lookup func(name string) (string, bool)) error {
+	j = j.orMissing()
+	if j.IsFrozen() {
+		return ErrFrozen
+	}
+	e := &expander{root: j.data, lookup: lookup, done: make(map[string]interface{})}
+	data, err := e.expand(j.data, nil)
+	if err != nil {
+		return err
+	}
+	return j.replace(data)
+}
+
+type expander struct {
+	root   interface{}
+	lookup func(string) (string, bool)
+	done   map[string]interface{}
+	stack  []string
+}
+
+// expand returns a copy of `data` with placeholders substituted
+func (e *expander) expand(data interface{}, path []interface{}) (interface{}, error) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			res, err := e.expand(val, append(path, key))
+			if err != nil {
+				return nil, err
+			}
+			m[key] = res
+		}
+		return m, nil
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, val := range v {
+			res, err := e.expand(val, append(path, i))
+			if err != nil {
+				return nil, err
+			}
+			a[i] = res
+		}
+		return a, nil
+	case string:
+		return e.expandString(v, path)
+	}
+	return data, nil
+}
+
+func (e *expander) expandString(s string, path []interface{}) (interface{}, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+	if strings.HasPrefix(s, "${") && strings.Index(s, "}") == len(s)-1 {
+		val, err := e.resolve(s[2:len(s)-1], path)
+		if err != nil {
+			return nil, err
+		}
+		return val, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "$$"):
+			b.WriteByte('$')
+			i++
+		case strings.HasPrefix(s[i:], "${"):
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("simplejson: unterminated placeholder in %q at %s", s, formatPath(path))
+			}
+			val, err := e.resolve(s[i+2:i+end], path)
+			if err != nil {
+				return nil, err
+			}
+			str, err := placeholderString(val)
+			if err != nil {
+				return nil, fmt.Errorf("simplejson: placeholder %s at %s: %w", s[i:i+end+1], formatPath(path), err)
+			}
+			b.WriteString(str)
+			i += end
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// resolve returns the expanded value of the placeholder `name`
+func (e *expander) resolve(name string, path []interface{}) (interface{}, error) {
+	if val, ok := e.done[name]; ok {
+		return val, nil
+	}
+	for _, active := range e.stack {
+		if active == name {
+			return nil, fmt.Errorf("simplejson: circular placeholder ${%s} at %s", name, formatPath(path))
+		}
+	}
+
+	if val, branch, ok := lookupDotted(e.root, name); ok {
+		e.stack = append(e.stack, name)
+		res, err := e.expand(val, branch)
+		e.stack = e.stack[:len(e.stack)-1]
+		if err != nil {
+			return nil, err
+		}
+		e.done[name] = res
+		return res, nil
+	}
+	if e.lookup != nil {
+		if val, ok := e.lookup(name); ok {
+			return val, nil
+		}
+	}
+	return nil, fmt.Errorf("simplejson: unresolved placeholder ${%s} at %s", name, formatPath(path))
+}
+
+// lookupDotted returns the value at a dotted path, numeric segments
+// indexing arrays, and its branch
+func lookupDotted(data interface{}, path string) (interface{}, []interface{}, bool) {
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		return nil, nil, false
+	}
+	return lookupPointer(data, segments)
+}
+
+// placeholderString formats a scalar value substituted within a string
+func placeholderString(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case nil:
+		return "null", nil
+	case map[string]interface{}, []interface{}:
+		return "", fmt.Errorf("cannot substitute an object or array within a string")
+	}
+	return fmt.Sprint(val), nil
+}
@@ -0,0 +1,45 @@
+package simplejson
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestExpand(t *testing.T) {
+	js, _ := NewJSON([]byte(`{
+		"server": {"host": "${HOST}", "port": 8080},
+		"url": "http://${server.host}:${server.port}/${hosts.1}",
+		"port": "${server.port}",
+		"price": "$$5 ${missing.ok}",
+		"hosts": ["a", "b"],
+		"base": "${url}/api"
+	}`))
+
+	env := map[string]string{"HOST": "example.com", "missing.ok": "each"}
+	err := js.Expand(func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "http://example.com:8080/b", js.Get("url").String())
+	assert.Equal(t, "http://example.com:8080/b/api", js.Get("base").String())
+	assert.Equal(t, 8080, js.Get("port").Int())
+	assert.Equal(t, "$5 each", js.Get("price").String())
+}
+
+func TestExpandErrors(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"a": "${b}", "b": "x${a}"}`))
+	err := js.Expand(nil)
+	assert.NotEqual(t, nil, err)
+	assert.T(t, err.Error()[:31] == "simplejson: circular placeholde", err)
+
+	js, _ = NewJSON([]byte(`{"a": ["x ${nope}"]}`))
+	assert.Equal(t, "simplejson: unresolved placeholder ${nope} at a[0]", js.Expand(nil).Error())
+
+	js, _ = NewJSON([]byte(`{"a": "x ${b}", "b": {}}`))
+	err = js.Expand(nil)
+	assert.Equal(t, "simplejson: placeholder ${b} at a: cannot substitute an object or array within a string", err.Error())
+	assert.NotEqual(t, nil, errors.Unwrap(err))
+}
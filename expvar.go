@@ -0,0 +1,25 @@
+package simplejson
+
+import "expvar"
+
+// String implements expvar.Var, returning the compact encoding of the document
+// taken under the read lock, or null if it cannot be encoded
+func (s *SyncJSON) String() string {
+	b, err := s.Encode()
+	if err != nil {
+		return "null"
+	}
+	return string(b)
+}
+
+// PublishExpvar exposes the document `j` as the expvar `name`, listed at /debug/vars.
+//
+// JSON cannot implement expvar.Var itself, its String method returns the value
+// of string documents. `j` is guarded by the returned SyncJSON, which must be used
+// to modify it afterwards, so it is never read while being modified.
+// Like expvar.Publish, it panics if `name` is already registered.
+func PublishExpvar(name string, j *JSON) *SyncJSON {
+	s := NewSync(j)
+	expvar.Publish(name, s)
+	return s
+}
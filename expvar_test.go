@@ -0,0 +1,22 @@
+package simplejson
+
+import (
+	"expvar"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	js, err := NewJSON([]byte(`{"state":"starting"}`))
+	assert.Equal(t, nil, err)
+
+	s := PublishExpvar("simplejson_test_state", js)
+	assert.Equal(t, `{"state":"starting"}`, expvar.Get("simplejson_test_state").String())
+
+	s.Set("state", "ready")
+	assert.Equal(t, `{"state":"ready"}`, expvar.Get("simplejson_test_state").String())
+
+	var v expvar.Var = NewSync(nil)
+	assert.Equal(t, `{}`, v.String())
+}
@@ -0,0 +1,129 @@
+package simplejson
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/brunotm/go-simplejson/scanner"
+)
+
+// Extract scans the raw `data` and decodes only the values found at `paths`,
+// skipping everything else, which is much cheaper than a full decode when a
+// few fields of a large payload are needed.
+//
+// Paths are dot separated keys, numeric segments address array elements.
+// The returned document has the shape of `data` restricted to the requested
+// paths, arrays keeping the positions of their extracted elements, with the
+// ones before them set to null. Paths not found in `data` are absent from it:
+//
+//	js, err := Extract(payload, "user.name", "items.1.id")
+//	// {"items":[null,{"id":20}],"user":{"name":"bob"}}
+//	name := js.Get("user", "name").String()
+func Extract(data []byte, paths ...string) (*JSON, error) {
+	root := &extractNode{}
+	for _, path := range paths {
+		root.add(splitPath(path))
+	}
+
+	s := scanner.New(data)
+	val, ok, err := root.walk(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.End(); err != nil {
+		return nil, err
+	}
+	out := New()
+	if ok {
+		out.data = val
+	}
+	return out, nil
+}
+
+// splitPath splits a dotted path into its segments
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// extractNode is a trie of the requested path segments
+type extractNode struct {
+	children map[string]*extractNode
+	leaf     bool
+}
+
+func (n *extractNode) add(segments []string) {
+	if len(segments) == 0 {
+		n.leaf = true
+		return
+	}
+	if n.children == nil {
+		n.children = make(map[string]*extractNode)
+	}
+	child, ok := n.children[segments[0]]
+	if !ok {
+		child = &extractNode{}
+		n.children[segments[0]] = child
+	}
+	child.add(segments[1:])
+}
+
+// walk consumes the value at the scanner position, returning its requested
+// parts and whether there were any
+func (n *extractNode) walk(s *scanner.Scanner) (interface{}, bool, error) {
+	if n.leaf {
+		raw, err := s.SkipValue()
+		if err != nil {
+			return nil, false, err
+		}
+		val, err := buildTree(raw)
+		if err != nil {
+			return nil, false, err
+		}
+		return val, true, nil
+	}
+
+	b, err := s.Peek()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(n.children) == 0 || (b != '{' && b != '[') {
+		_, err = s.SkipValue()
+		return nil, false, err
+	}
+
+	object := make(map[string]interface{})
+	var array []interface{}
+	err = s.Members(func(key string, index int) error {
+		if b == '[' {
+			key = strconv.Itoa(index)
+		}
+		child, ok := n.children[key]
+		if !ok {
+			_, err := s.SkipValue()
+			return err
+		}
+		val, ok, err := child.walk(s)
+		if err != nil || !ok {
+			return err
+		}
+		if b == '{' {
+			object[key] = val
+			return nil
+		}
+		for len(array) < index {
+			array = append(array, nil)
+		}
+		array = append(array, val)
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if b == '[' {
+		return array, len(array) > 0, nil
+	}
+	return object, len(object) > 0, nil
+}
@@ -0,0 +1,113 @@
+package simplejson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/brunotm/go-simplejson/scanner"
+)
+
+// errFound stops a scan once its target has been reached
+var errFound = errors.New("found")
+
+// ExtractArrayRange scans the raw `data` for the array at the dotted `path` and
+// decodes only its elements from `start` up to, but not including, `end`,
+// skipping everything else. Negative offsets count from the end of the array and
+// offsets past its bounds are clamped, so the last 10 elements of a large array
+// are extracted with:
+//
+//	js, err := ExtractArrayRange(payload, "events", -10, math.MaxInt32)
+//
+// The document is only scanned as far as needed, content after the requested
+// range is not validated.
+func ExtractArrayRange(data []byte, path string, start, end int) (*JSON, error) {
+	s := scanner.New(data)
+	switch err := seekPath(s, splitPath(path)); err {
+	case errFound:
+	case nil:
+		return nil, fmt.Errorf("simplejson: path %q not found", path)
+	default:
+		return nil, err
+	}
+
+	b, err := s.Peek()
+	if err != nil {
+		return nil, err
+	}
+	if b != '[' {
+		return nil, fmt.Errorf("simplejson: value at %q is not an array", path)
+	}
+
+	// with non negative offsets the scan stops at the end of the range,
+	// otherwise the whole array must be scanned to know its length
+	var raws [][]byte
+	err = s.Members(func(_ string, index int) error {
+		if start >= 0 && end >= 0 && index >= end {
+			return errFound
+		}
+		raw, err := s.SkipValue()
+		raws = append(raws, raw)
+		return err
+	})
+	if err != nil && err != errFound {
+		return nil, err
+	}
+
+	start, end = clampRange(start, len(raws)), clampRange(end, len(raws))
+	if end < start {
+		end = start
+	}
+	a := []interface{}{}
+	for _, raw := range raws[start:end] {
+		val, err := buildTree(raw)
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, val)
+	}
+
+	j := &JSON{data: a}
+	j.document()
+	return j, nil
+}
+
+// seekPath advances `s` to the value at `segments`, returning errFound when
+// positioned on it or nil after consuming a value that does not hold it
+func seekPath(s *scanner.Scanner, segments []string) error {
+	if len(segments) == 0 {
+		return errFound
+	}
+	b, err := s.Peek()
+	if err != nil {
+		return err
+	}
+	if b != '{' && b != '[' {
+		_, err = s.SkipValue()
+		return err
+	}
+	return s.Members(func(key string, index int) error {
+		if b == '[' {
+			key = strconv.Itoa(index)
+		}
+		if key == segments[0] {
+			return seekPath(s, segments[1:])
+		}
+		_, err := s.SkipValue()
+		return err
+	})
+}
+
+// clampRange resolves a possibly negative `offset` into [0, length]
+func clampRange(offset, length int) int {
+	if offset < 0 {
+		offset += length
+	}
+	switch {
+	case offset < 0:
+		return 0
+	case offset > length:
+		return length
+	}
+	return offset
+}
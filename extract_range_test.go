@@ -0,0 +1,49 @@
+package simplejson
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestExtractArrayRange(t *testing.T) {
+	data := []byte(`{"meta":{"n":[0]},"log":{"events":[{"id":0},{"id":1},{"id":2},{"id":3},{"id":4}]},"after":1}`)
+
+	js, err := ExtractArrayRange(data, "log.events", 1, 3)
+	assert.Equal(t, nil, err)
+	b, _ := js.Encode()
+	assert.Equal(t, `[{"id":1},{"id":2}]`, string(b))
+
+	js, err = ExtractArrayRange(data, "log.events", -2, math.MaxInt32)
+	assert.Equal(t, nil, err)
+	b, _ = js.Encode()
+	assert.Equal(t, `[{"id":3},{"id":4}]`, string(b))
+
+	js, _ = ExtractArrayRange(data, "log.events", -10, -4)
+	b, _ = js.Encode()
+	assert.Equal(t, `[{"id":0}]`, string(b))
+
+	js, _ = ExtractArrayRange(data, "log.events", 3, 1)
+	b, _ = js.Encode()
+	assert.Equal(t, `[]`, string(b))
+
+	js, _ = ExtractArrayRange([]byte(`[[1,2],[3,4,5]]`), "1", 1, 2)
+	b, _ = js.Encode()
+	assert.Equal(t, `[4]`, string(b))
+
+	js, _ = ExtractArrayRange([]byte(` [1,2,3]`), "", 0, 1)
+	b, _ = js.Encode()
+	assert.Equal(t, `[1]`, string(b))
+
+	// content after the range is not scanned
+	_, err = ExtractArrayRange([]byte(`[1,2,3,garbage`), "", 0, 2)
+	assert.Equal(t, nil, err)
+
+	_, err = ExtractArrayRange(data, "log.missing", 0, 1)
+	assert.NotEqual(t, nil, err)
+	_, err = ExtractArrayRange(data, "after", 0, 1)
+	assert.NotEqual(t, nil, err)
+	_, err = ExtractArrayRange([]byte(`{"a":[1,`), "a", -1, 1)
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,61 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestExtract(t *testing.T) {
+	raw := []byte(`{
+		"skip": {"deep": [1, {"x": "}]\"{"}], "s": "a\"b"},
+		"user": {"name": "bob", "tags": ["a", "b"], "esc": true},
+		"items": [{"id": 10}, {"id": 20}],
+		"n": 12345678901234567890
+	}`)
+
+	js, err := Extract(raw, "user.name", "items.1.id", "user.tags.1", "n", "missing.path", "items.9")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "bob", js.Get("user", "name").String())
+	assert.Equal(t, 20, js.Get("items", 1, "id").Int())
+	assert.Equal(t, "b", js.Get("user", "tags", 1).String())
+	assert.Equal(t, uint64(12345678901234567890), js.Get("n").Uint64())
+	assert.Equal(t, `{"items":[null,{"id":20}],"n":12345678901234567890,"user":{"name":"bob","tags":[null,"b"]}}`, string(mustEncode(t, js)))
+	_, ok := js.CheckGet("missing")
+	assert.Equal(t, false, ok)
+
+	// a requested value holds the paths requested below it
+	js, err = Extract(raw, "user.name", "user", "user.esc")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"user":{"esc":true,"name":"bob","tags":["a","b"]}}`, string(mustEncode(t, js)))
+
+	js, err = Extract([]byte(`[1, [2, 3]]`), "1.0")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `[null,[2]]`, string(mustEncode(t, js)))
+
+	js, err = Extract(raw, "missing")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{}`, string(mustEncode(t, js)))
+
+	_, err = Extract([]byte(`{"a": [1, 2`), "a.0")
+	assert.NotEqual(t, nil, err)
+
+	_, err = Extract([]byte(`{"a": 1} x`), "a")
+	assert.NotEqual(t, nil, err)
+
+	// malformed strings are rejected, even when skipped
+	for _, raw := range []string{"{\"a\": \"\x01\", \"b\": 1}", `{"a": "\x", "b": 1}`, `{"a": "\u12", "b": 1}`} {
+		_, err = Extract([]byte(raw), "b")
+		assert.NotEqual(t, nil, err, raw)
+	}
+}
+
+func TestExtractSharedPrefix(t *testing.T) {
+	raw := []byte(`{"a": {"b": 1, "c": {"d": 2, "e": 3}, "f": 4}, "g": [5, {"h": 6, "i": 7}]}`)
+
+	js, err := Extract(raw, "a.b", "a.c.d", "a.c.e", "g.1.h", "g.1.i")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"a":{"b":1,"c":{"d":2,"e":3}},"g":[null,{"h":6,"i":7}]}`, string(mustEncode(t, js)))
+	assert.Equal(t, 2, js.Get("a", "c", "d").Int())
+	assert.Equal(t, 7, js.Get("g", 1, "i").Int())
+}
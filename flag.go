@@ -0,0 +1,61 @@
+package simplejson
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PathFlag is a flag.Value applying `path=value` assignments onto a document,
+// giving command line tools `--set` style overrides:
+//
+//	js := New()
+//	flag.Var(NewPathFlag(js), "set", "override a value, such as server.port=8080")
+//
+// Paths are dotted keys, numeric segments index existing arrays. Values are
+// converted to booleans and numbers when they parse as such, JSON objects,
+// arrays, quoted strings and null are decoded, anything else is a string.
+// A zero PathFlag creates its Target on the first assignment.
+type PathFlag struct {
+	Target *JSON
+	values []string
+}
+
+// NewPathFlag returns a PathFlag applying assignments onto `target`
+func NewPathFlag(target *JSON) *PathFlag {
+	return &PathFlag{Target: target}
+}
+
+// String returns the assignments applied so far
+func (f *PathFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return strings.Join(f.values, ",")
+}
+
+// Set applies a `path=value` assignment
+func (f *PathFlag) Set(s string) error {
+	i := strings.IndexByte(s, '=')
+	if i <= 0 {
+		return fmt.Errorf("simplejson: expected path=value, got %q", s)
+	}
+	path, raw := s[:i], s[i+1:]
+
+	val := inferType(raw)
+	if raw == "null" || strings.HasPrefix(raw, "{") || strings.HasPrefix(raw, "[") || strings.HasPrefix(raw, `"`) {
+		js, err := NewJSON([]byte(raw))
+		if err != nil {
+			return fmt.Errorf("simplejson: invalid value for %s: %w", path, err)
+		}
+		val = js.data
+	}
+
+	if f.Target == nil {
+		f.Target = New()
+	}
+	if err := f.Target.setAt(dottedBranch(f.Target.data, path), val); err != nil {
+		return err
+	}
+	f.values = append(f.values, s)
+	return nil
+}
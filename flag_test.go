@@ -0,0 +1,44 @@
+package simplejson
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestPathFlag(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"server":{"port":80},"hosts":["a","b"]}`))
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	set := NewPathFlag(js)
+	fs.Var(set, "set", "override a value")
+
+	err := fs.Parse([]string{
+		"--set", "server.port=8080",
+		"--set", "features.x=true",
+		"--set", "hosts.1=c",
+		"--set", `tags=["x","y"]`,
+		"--set", `name="007"`,
+		"--set", "nothing=null",
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"features":{"x":true},"hosts":["a","c"],"name":"007","nothing":null,"server":{"port":8080},"tags":["x","y"]}`,
+		string(mustEncode(t, js)))
+	assert.Equal(t, "server.port=8080,features.x=true", set.String()[:32])
+
+	assert.NotEqual(t, nil, fs.Parse([]string{"--set", "novalue"}))
+	assert.NotEqual(t, nil, fs.Parse([]string{"--set", "x={bad"}))
+	err = set.Set("x={bad")
+	assert.T(t, strings.HasPrefix(err.Error(), "simplejson: invalid value for x: "), err)
+	assert.NotEqual(t, nil, errors.Unwrap(err))
+
+	var zero PathFlag
+	assert.Equal(t, nil, zero.Set("server.port=8080"))
+	assert.Equal(t, `{"server":{"port":8080}}`, string(mustEncode(t, zero.Target)))
+	assert.Equal(t, "", (*PathFlag)(nil).String())
+}
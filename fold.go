@@ -0,0 +1,74 @@
+package simplejson
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SetCaseInsensitive makes lookups through Get and friends on the document
+// match keys regardless of their case and of `-`, `_` and space separators,
+// so that Get("ContentType") finds "content-type" or "contentType".
+// Mutators such as Set and Del still use keys as given.
+//
+// Exact matches are always preferred, then keys differing only in case,
+// then keys differing in separators, ties being broken by the sort order
+// of the keys.
+func (j *JSON) SetCaseInsensitive(on bool) {
+	if j == nil {
+		return
+	}
+	j.document().foldKeys = on
+}
+
+// GetFold is like Get, matching keys as documented by SetCaseInsensitive
+func (j *JSON) GetFold(branch ...interface{}) *JSON {
+	j = j.orMissing()
+	jin, ok := j.walkWith(branch, true)
+	if ok {
+		return jin
+	}
+	return j.wrap(nil)
+}
+
+// getKeyFold finds `key` within the `map` representation of `data` ignoring
+// case and separators, and returns the matched key
+func getKeyFold(data interface{}, key string) (interface{}, interface{}, bool) {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return key, nil, false
+	}
+	if val, ok := m[key]; ok {
+		return key, val, true
+	}
+
+	var candidates []string
+	normalized := normalizeKey(key)
+	for k := range m {
+		if normalizeKey(k) == normalized {
+			candidates = append(candidates, k)
+		}
+	}
+	if len(candidates) == 0 {
+		return key, nil, false
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		fa, fb := strings.EqualFold(candidates[a], key), strings.EqualFold(candidates[b], key)
+		if fa != fb {
+			return fa
+		}
+		return candidates[a] < candidates[b]
+	})
+	return candidates[0], m[candidates[0]], true
+}
+
+// normalizeKey lower cases `key` and removes separators
+func normalizeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', ' ':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, key)
+}
@@ -0,0 +1,29 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestCaseInsensitive(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"headers":{"content-type":"a","Content_Type":"b","contentType":"c","X-Id":1}}`))
+
+	_, ok := js.CheckGet("Headers", "x-id")
+	assert.Equal(t, false, ok)
+	assert.Equal(t, 1, js.GetFold("Headers", "x-id").Int())
+	assert.Equal(t, "c", js.GetFold("headers", "contentType").String())
+	assert.Equal(t, "b", js.GetFold("headers", "CONTENT_TYPE").String())
+	assert.Equal(t, "c", js.GetFold("headers", "ContentType").String())
+	assert.Equal(t, "a", js.GetFold("headers", "Content-Type").String())
+	assert.Equal(t, nil, js.GetFold("headers", "missing").Interface())
+
+	js.SetCaseInsensitive(true)
+	assert.Equal(t, 1, js.Get("HEADERS", "x_id").Int())
+	js.Get("Headers").Set("new", true)
+	assert.Equal(t, true, js.Get("headers", "new").Bool())
+
+	js.SetCaseInsensitive(false)
+	_, ok = js.CheckGet("HEADERS")
+	assert.Equal(t, false, ok)
+}
@@ -0,0 +1,38 @@
+package simplejson
+
+import "errors"
+
+// ErrFrozen is the panic value of mutations attempted on a frozen document,
+// and the error returned by UnmarshalJSON on one
+var ErrFrozen = errors.New("simplejson: document is frozen")
+
+// Freeze marks the document as immutable and returns it.
+//
+// Every mutating method called on the document, or on any view obtained
+// from it afterwards through Get and friends, panics with ErrFrozen.
+// Containers returned by Map and Array are the live underlying values and
+// must be treated as read only, unless CopyOnGet is set. Clone returns a
+// mutable copy.
+//
+// A frozen document, and the views obtained from it, are safe for concurrent
+// use by multiple goroutines without locking, as long as its settings, such as
+// SetTracer or SortKeys, are not changed meanwhile.
+func (j *JSON) Freeze() *JSON {
+	if j == nil {
+		return nil
+	}
+	j.document().frozen = true
+	return j
+}
+
+// IsFrozen reports whether the document rejects mutations
+func (j *JSON) IsFrozen() bool {
+	return j != nil && j.doc != nil && j.doc.frozen
+}
+
+// checkWritable panics if the document is frozen
+func (j *JSON) checkWritable() {
+	if j.IsFrozen() {
+		panic(ErrFrozen)
+	}
+}
@@ -0,0 +1,88 @@
+package simplejson
+
+import (
+	"io"
+	"log"
+	"sync"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func assertPanics(t *testing.T, want interface{}, fn func()) {
+	t.Helper()
+	defer func() {
+		assert.Equal(t, want, recover())
+	}()
+	fn()
+}
+
+func TestFreeze(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"b":[{"c":1}]}}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, js.IsFrozen())
+
+	assert.Equal(t, js, js.Freeze())
+	assert.Equal(t, true, js.IsFrozen())
+	assert.Equal(t, true, js.Get("a", "b", 0).IsFrozen())
+	assert.Equal(t, true, js.Get("missing").IsFrozen())
+	assert.Equal(t, true, js.Get("a").JSONMap()["b"].IsFrozen())
+
+	assertPanics(t, ErrFrozen, func() { js.Set("x", 1) })
+	assertPanics(t, ErrFrozen, func() { js.Get("a", "b", 0).Set("c", 2) })
+	assertPanics(t, ErrFrozen, func() { js.Get("missing").SetPath([]string{"x"}, 1) })
+	assertPanics(t, ErrFrozen, func() { js.Get("a").Del("b") })
+	assertPanics(t, ErrFrozen, func() { js.Reset() })
+	assert.Equal(t, ErrFrozen, js.UnmarshalJSON([]byte(`{}`)))
+	assert.Equal(t, 1, js.Get("a", "b", 0, "c").Int())
+
+	c := js.Clone()
+	assert.Equal(t, false, c.IsFrozen())
+	c.Set("x", 1)
+	assert.Equal(t, 1, c.Get("x").Int())
+}
+
+// TestFrozenConcurrentReads is meant to be run with the race detector
+func TestFrozenConcurrentReads(t *testing.T) {
+	js := mustJSON(`{"users":[{"name":"a","age":30,"tags":["x"]},{"name":"b","age":40,"tags":[]}],"meta":{"Version":1}}`)
+	js.SetTracer(LogTracer(log.New(io.Discard, "", 0)))
+	js.Freeze()
+	shared := js.Get("users", 0)
+	before, _ := js.Encode()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for n := 0; n < 50; n++ {
+				assert.Equal(t, "a", shared.Get("name").String())
+				assert.Equal(t, 0, shared.Get("missing").Int())
+				assert.Equal(t, "", shared.Get("age").String())
+				assert.Equal(t, "b", js.Get("users", -1, "name").String())
+				assert.Equal(t, []string{"meta", "users"}, js.SortedKeys())
+				js.ForEachSorted(func(string, *JSON) bool { return true })
+				js.Encode()
+				js.EncodePretty()
+				js.ETag()
+				js.Stats()
+				js.Sum("users", "*", "age")
+				js.Search("users[?age > `35`].name")
+				js.Eval("users[0].age * 2")
+				js.Transform(".users | map(.name)")
+				js.FormatDiff(js)
+				js.Clone().Set("meta", i)
+
+				s := js.Snapshot()
+				s.Get("users", 0).Set("name", i)
+				s.Get("users", 1, "tags").Append(i)
+				tx := js.Begin()
+				tx.Get("meta").Set("Version", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	after, _ := js.Encode()
+	assert.Equal(t, string(before), string(after))
+}
@@ -0,0 +1,154 @@
+package simplejson
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// GenerateFromSchema returns a random document valid against `schema`, for
+// tests and load generation. Enums, bounds, lengths, required properties and
+// the "date-time", "date", "email", "uuid", "uri" and "ipv4" formats are
+// honored, optional properties are set at random. The same `seed` always
+// generates the same document.
+func GenerateFromSchema(schema *Schema, seed int64) *JSON {
+	g := &generator{r: rand.New(rand.NewSource(seed))}
+	j := &JSON{data: g.value(schema)}
+	j.document()
+	return j
+}
+
+type generator struct {
+	r *rand.Rand
+}
+
+const letters = "abcdefghijklmnopqrstuvwxyz"
+
+// value returns a random value valid against `s`
+func (g *generator) value(s *Schema) interface{} {
+	if s == nil {
+		s = &Schema{}
+	}
+	if len(s.Enum) > 0 {
+		return deepCopy(s.Enum[g.r.Intn(len(s.Enum))])
+	}
+	if s.Nullable && g.r.Intn(10) == 0 {
+		return nil
+	}
+
+	switch s.Type {
+	case "object":
+		m := make(map[string]interface{})
+		required := make(map[string]bool, len(s.Required))
+		for _, key := range s.Required {
+			required[key] = true
+			m[key] = g.value(s.Properties[key])
+		}
+		keys := make([]string, 0, len(s.Properties))
+		for key := range s.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !required[key] && g.r.Intn(2) == 0 {
+				m[key] = g.value(s.Properties[key])
+			}
+		}
+		return m
+	case "array":
+		a := make([]interface{}, g.length(s.MinItems, s.MaxItems, 0, 3))
+		for i := range a {
+			a[i] = g.value(s.Items)
+		}
+		return a
+	case "string":
+		return g.string(s)
+	case "integer":
+		lo, hi := g.bounds(s)
+		lo, hi = math.Ceil(lo), math.Floor(hi)
+		if hi < lo {
+			return int64(lo)
+		}
+		return int64(lo) + g.r.Int63n(int64(hi-lo)+1)
+	case "number":
+		lo, hi := g.bounds(s)
+		return lo + g.r.Float64()*(hi-lo)
+	case "boolean":
+		return g.r.Intn(2) == 0
+	case "null":
+		return nil
+	}
+
+	// any type, pick a scalar
+	types := []string{"string", "number", "integer", "boolean"}
+	return g.value(&Schema{Type: types[g.r.Intn(len(types))]})
+}
+
+// bounds returns the range of numbers allowed by `s`,
+// a range of 100 from the bound given when only one is
+func (g *generator) bounds(s *Schema) (float64, float64) {
+	switch {
+	case s.Minimum != nil && s.Maximum != nil:
+		return *s.Minimum, *s.Maximum
+	case s.Minimum != nil:
+		return *s.Minimum, *s.Minimum + 100
+	case s.Maximum != nil:
+		return *s.Maximum - 100, *s.Maximum
+	}
+	return 0, 100
+}
+
+// length returns a random length within the optional `min` and `max`,
+// which default to `lo` and `span` more than the minimum
+func (g *generator) length(min, max *int, lo, span int) int {
+	if min != nil {
+		lo = *min
+	}
+	hi := lo + span
+	if max != nil {
+		hi = *max
+	}
+	if hi <= lo {
+		return lo
+	}
+	return lo + g.r.Intn(hi-lo+1)
+}
+
+func (g *generator) word(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[g.r.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+// string returns a random string of the format of `s`
+func (g *generator) string(s *Schema) string {
+	switch s.Format {
+	case "date-time":
+		return g.time().Format(time.RFC3339)
+	case "date":
+		return g.time().Format("2006-01-02")
+	case "email":
+		return g.word(8) + "@example.com"
+	case "uri":
+		return "https://example.com/" + g.word(8)
+	case "uuid":
+		b := make([]byte, 16)
+		g.r.Read(b)
+		b[6] = b[6]&0x0f | 0x40 // version 4
+		b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case "ipv4":
+		return fmt.Sprintf("%d.%d.%d.%d", 1+g.r.Intn(254), g.r.Intn(256), g.r.Intn(256), 1+g.r.Intn(254))
+	}
+	return g.word(g.length(s.MinLength, s.MaxLength, 1, 11))
+}
+
+// time returns a random time between 2000 and 2030
+func (g *generator) time() time.Time {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	return time.Unix(start+g.r.Int63n(30*365*24*3600), 0).UTC()
+}
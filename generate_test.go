@@ -0,0 +1,60 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestGenerateFromSchema(t *testing.T) {
+	var schema Schema
+	err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"required": ["id", "status", "tags", "created", "owner"],
+		"properties": {
+			"id": {"type": "integer", "minimum": 10, "maximum": 20},
+			"score": {"type": "number", "minimum": -1, "maximum": 1},
+			"status": {"type": "string", "enum": ["on", "off"]},
+			"tags": {"type": "array", "minItems": 1, "maxItems": 3, "items": {"type": "string", "minLength": 2, "maxLength": 4}},
+			"created": {"type": "string", "format": "date-time"},
+			"owner": {"type": "object", "required": ["email", "id"], "properties": {
+				"email": {"type": "string", "format": "email"},
+				"id": {"type": "string", "format": "uuid"}
+			}},
+			"note": {"type": "string", "nullable": true}
+		}
+	}`), &schema)
+	assert.Equal(t, nil, err)
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	for seed := int64(0); seed < 50; seed++ {
+		js := GenerateFromSchema(&schema, seed)
+
+		id := js.Get("id").Int64()
+		assert.T(t, id >= 10 && id <= 20, id)
+		if score, ok := js.CheckGet("score"); ok {
+			f := score.Float64()
+			assert.T(t, f >= -1 && f <= 1, f)
+		}
+		status := js.Get("status").String()
+		assert.T(t, status == "on" || status == "off", status)
+		tags := js.Get("tags").Array()
+		assert.T(t, len(tags) >= 1 && len(tags) <= 3, tags)
+		for _, tag := range tags {
+			assert.T(t, len(tag.(string)) >= 2 && len(tag.(string)) <= 4, tag)
+		}
+		_, err := time.Parse(time.RFC3339, js.Get("created").String())
+		assert.Equal(t, nil, err)
+		assert.T(t, regexp.MustCompile(`^[a-z]+@example\.com$`).MatchString(js.Get("owner", "email").String()))
+		assert.T(t, uuid.MatchString(js.Get("owner", "id").String()))
+	}
+
+	a, _ := GenerateFromSchema(&schema, 7).Encode()
+	b, _ := GenerateFromSchema(&schema, 7).Encode()
+	assert.Equal(t, string(a), string(b))
+
+	assert.Equal(t, nil, GenerateFromSchema(&Schema{Type: "null"}, 1).Interface())
+}
@@ -0,0 +1,70 @@
+package simplejson
+
+import "fmt"
+
+// Graft inserts the tree of `other` into the document at `branch`,
+// creating intermediate objects for missing keys. Array elements along the
+// branch must exist, the last element of the branch may address an existing
+// array element, which is replaced.
+//
+// When `copy` is false the tree is inserted by reference, so both documents
+// share it and modifications made through either are visible in both.
+// The tree of a frozen document, or of one sharing its containers with
+// snapshots, is always copied, so it can't be modified through `j`.
+func (j *JSON) Graft(branch []interface{}, other *JSON, copy bool) error {
+	j = j.orMissing()
+	other = other.orMissing()
+	val := other.data
+	if copy || other.IsFrozen() || other.doc != nil && other.doc.shared {
+		val = deepCopy(val)
+	}
+	return j.setAt(branch, val)
+}
+
+// setAt sets `val` at `branch`, creating intermediate objects for missing keys
+func (j *JSON) setAt(branch []interface{}, val interface{}) error {
+	return j.setAtWith(branch, val, j.doc != nil && j.doc.foldKeys)
+}
+
+// setAtWith is like setAt, matching keys case insensitively if `fold` is set
+func (j *JSON) setAtWith(branch []interface{}, val interface{}, fold bool) error {
+	if j.IsFrozen() {
+		return ErrFrozen
+	}
+	if len(branch) == 0 {
+		return j.replace(val)
+	}
+
+	parent := j
+	for i, p := range branch[:len(branch)-1] {
+		next, ok := parent.walkWith([]interface{}{p}, fold)
+		if !ok {
+			if _, isMap := parent.data.(map[string]interface{}); !isMap {
+				return fmt.Errorf("simplejson: cannot set %s, %v is not an existing key or index", formatPath(branch), p)
+			}
+			if err := parent.setChild(p, map[string]interface{}{}); err != nil {
+				return err
+			}
+			next, _ = parent.walkWith([]interface{}{p}, fold)
+		}
+		if _, isMap := next.data.(map[string]interface{}); !isMap {
+			if _, isArray := next.data.([]interface{}); !isArray {
+				return fmt.Errorf("simplejson: cannot set %s, %s is not an object or array", formatPath(branch), formatPath(branch[:i+1]))
+			}
+		}
+		parent = next
+	}
+
+	key := branch[len(branch)-1]
+	switch k := key.(type) {
+	case string:
+		if _, ok := parent.data.(map[string]interface{}); ok {
+			return parent.set(k, val)
+		}
+	case int:
+		if _, ok := getIndex(parent.data, k); ok {
+			return parent.setChild(k, val)
+		}
+	}
+	return fmt.Errorf("simplejson: cannot set %s, %v is not an existing key or index", formatPath(branch), key)
+}
@@ -0,0 +1,52 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestGraft(t *testing.T) {
+	js, err := NewJSON([]byte(`{"data":{"items":[{"id":1},{"id":2}]},"n":1}`))
+	assert.Equal(t, nil, err)
+	part, err := NewJSON([]byte(`{"name":"x"}`))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, nil, js.Graft([]interface{}{"meta", "part"}, part, false))
+	assert.Equal(t, nil, js.Graft([]interface{}{"data", "items", 1}, part, true))
+	assert.Equal(t, `{"data":{"items":[{"id":1},{"name":"x"}]},"meta":{"part":{"name":"x"}},"n":1}`,
+		string(mustEncode(t, js)))
+
+	// by reference shares the tree, copies don't
+	part.Set("name", "y")
+	assert.Equal(t, "y", js.Get("meta", "part", "name").String())
+	assert.Equal(t, "x", js.Get("data", "items", 1, "name").String())
+
+	err = js.Graft([]interface{}{"data", "items", 5}, part, true)
+	assert.Equal(t, "simplejson: cannot set data.items[5], 5 is not an existing key or index", err.Error())
+	err = js.Graft([]interface{}{"n", "x"}, part, true)
+	assert.Equal(t, "simplejson: cannot set n.x, n is not an object or array", err.Error())
+
+	assert.Equal(t, nil, js.Graft(nil, part, true))
+	assert.Equal(t, "y", js.Get("name").String())
+
+	js.Freeze()
+	assert.Equal(t, ErrFrozen, js.Graft([]interface{}{"a"}, part, true))
+}
+
+func TestGraftFrozen(t *testing.T) {
+	frozen := mustJSON(`{"v":1}`).Freeze()
+	js := New()
+	assert.Equal(t, nil, js.Graft([]interface{}{"f"}, frozen, false))
+	js.Get("f").Set("v", 2)
+	assert.Equal(t, 1, frozen.Get("v").Int())
+	assert.Equal(t, 2, js.Get("f", "v").Int())
+
+	// trees shared with snapshots are copied too
+	orig := mustJSON(`{"v":1}`)
+	snap := orig.Snapshot()
+	assert.Equal(t, nil, js.Graft([]interface{}{"s"}, orig, false))
+	js.Get("s").Set("v", 3)
+	assert.Equal(t, 1, orig.Get("v").Int())
+	assert.Equal(t, 1, snap.Get("v").Int())
+}
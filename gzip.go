@@ -0,0 +1,50 @@
+package simplejson
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// MaxGzipSize is the default limit of the decompressed size of documents
+// read by NewFromGzip, protecting against decompression bombs.
+// It is overridden by the MaxBytes option.
+var MaxGzipSize int64 = 256 << 20
+
+// NewFromGzip returns a *JSON by decoding gzip compressed JSON from `r`,
+// failing with ErrTooLarge when the decompressed document exceeds
+// MaxGzipSize or the limit set with MaxBytes
+func NewFromGzip(r io.Reader, opts ...DecodeOption) (*JSON, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	opts = append([]DecodeOption{MaxBytes(MaxGzipSize)}, opts...)
+	js, err := NewFromReaderWith(zr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return js, nil
+}
+
+// EncodeGzip returns the document encoded as JSON and compressed with gzip
+// at `level`, such as gzip.BestCompression or gzip.DefaultCompression
+func (j *JSON) EncodeGzip(level int) ([]byte, error) {
+	b, err := j.Encode()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = zw.Write(b); err != nil {
+		return nil, err
+	}
+	if err = zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,55 @@
+package simplejson
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestGzip(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":[1,2,3],"b":"` + strings.Repeat("x", 1000) + `"}`))
+	assert.Equal(t, nil, err)
+
+	b, err := js.EncodeGzip(gzip.BestCompression)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, len(b) < 200)
+
+	got, err := NewFromGzip(bytes.NewReader(b))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, got.Get("a", -1).Int())
+
+	_, err = NewFromGzip(bytes.NewReader(b), MaxBytes(500))
+	assert.Equal(t, ErrTooLarge, err)
+
+	defer func(n int64) { MaxGzipSize = n }(MaxGzipSize)
+	MaxGzipSize = 100
+	_, err = NewFromGzip(bytes.NewReader(b))
+	assert.Equal(t, ErrTooLarge, err)
+
+	_, err = NewFromGzip(strings.NewReader(`{"a":1,"b":[true]}`))
+	assert.Equal(t, gzip.ErrHeader, err)
+
+	_, err = js.EncodeGzip(42)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestMaxBytes(t *testing.T) {
+	body := `{"a":"0123456789"}`
+	_, err := NewJSONWith([]byte(body), MaxBytes(10))
+	assert.Equal(t, ErrTooLarge, err)
+	_, err = NewFromReaderWith(strings.NewReader(body), MaxBytes(10))
+	assert.Equal(t, ErrTooLarge, err)
+
+	js, err := NewFromReaderWith(strings.NewReader(body), MaxBytes(int64(len(body))))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "0123456789", js.Get("a").String())
+
+	dec := NewDecoder(strings.NewReader(body+body), MaxBytes(int64(len(body))+5))
+	_, err = dec.Decode()
+	assert.Equal(t, nil, err)
+	_, err = dec.Decode()
+	assert.Equal(t, ErrTooLarge, err)
+}
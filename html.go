@@ -0,0 +1,176 @@
+package simplejson
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// HTMLOption configures ToHTML
+type HTMLOption func(*htmlConfig)
+
+type htmlConfig struct {
+	openDepth    int
+	maxItems     int
+	maxStringLen int
+	classes      string
+}
+
+// HTMLOpenDepth expands the objects and arrays nested less than `depth`
+// levels deep, only the root is expanded by default
+func HTMLOpenDepth(depth int) HTMLOption {
+	return func(c *htmlConfig) {
+		c.openDepth = depth
+	}
+}
+
+// HTMLMaxItems renders the first `n` members of objects and arrays only,
+// followed by the number of members left out
+func HTMLMaxItems(n int) HTMLOption {
+	return func(c *htmlConfig) {
+		c.maxItems = n
+	}
+}
+
+// HTMLMaxStringLen truncates strings longer than `n` characters
+func HTMLMaxStringLen(n int) HTMLOption {
+	return func(c *htmlConfig) {
+		c.maxStringLen = n
+	}
+}
+
+// HTMLClasses wraps keys and values in spans of the classes `prefix` followed
+// by "key", "string", "number", "bool", "null" or "summary", for styling
+// with CSS, such as `<span class="json-string">`
+func HTMLClasses(prefix string) HTMLOption {
+	return func(c *htmlConfig) {
+		c.classes = prefix
+	}
+}
+
+// ToHTML renders the document as an HTML fragment for debug pages, objects
+// and arrays as collapsible <details> elements listing their members,
+// keys in sorted order:
+//
+//	<details open><summary>{2 keys}</summary><ul>
+//	<li>id: 1</li>
+//	<li><details><summary>tags: [2 items]</summary><ul>...</ul></details></li>
+//	</ul></details>
+//
+// Keys and strings are HTML escaped.
+func (j *JSON) ToHTML(opts ...HTMLOption) (string, error) {
+	j = j.orMissing()
+	c := &htmlConfig{openDepth: 1}
+	for _, opt := range opts {
+		opt(c)
+	}
+	var b strings.Builder
+	if err := c.write(&b, "", j.data, 0); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// span writes `text`, escaped and within a span of `class` if enabled
+func (c *htmlConfig) span(b *strings.Builder, class, text string) {
+	if c.classes == "" {
+		b.WriteString(html.EscapeString(text))
+		return
+	}
+	b.WriteString(`<span class="`)
+	b.WriteString(html.EscapeString(c.classes + class))
+	b.WriteString(`">`)
+	b.WriteString(html.EscapeString(text))
+	b.WriteString("</span>")
+}
+
+// write renders `data`, labelled with `label` unless empty,
+// at nesting level `depth`
+func (c *htmlConfig) write(b *strings.Builder, label string, data interface{}, depth int) error {
+	prefix := func() {
+		if label != "" {
+			c.span(b, "key", label)
+			b.WriteString(": ")
+		}
+	}
+
+	var keys []string
+	var summary string
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			prefix()
+			c.span(b, "summary", "{}")
+			return nil
+		}
+		keys = sortedKeys(v)
+		summary = "{" + plural(len(v), "key") + "}"
+	case []interface{}:
+		if len(v) == 0 {
+			prefix()
+			c.span(b, "summary", "[]")
+			return nil
+		}
+		summary = "[" + plural(len(v), "item") + "]"
+	default:
+		prefix()
+		return c.scalar(b, data)
+	}
+
+	b.WriteString("<details")
+	if depth < c.openDepth {
+		b.WriteString(" open")
+	}
+	b.WriteString("><summary>")
+	prefix()
+	c.span(b, "summary", summary)
+	b.WriteString("</summary><ul>\n")
+
+	n := len(keys)
+	if a, ok := data.([]interface{}); ok {
+		n = len(a)
+	}
+	for i := 0; i < n; i++ {
+		if c.maxItems > 0 && i == c.maxItems {
+			b.WriteString("<li>")
+			c.span(b, "summary", "… "+strconv.Itoa(n-i)+" more")
+			b.WriteString("</li>\n")
+			break
+		}
+		b.WriteString("<li>")
+		var err error
+		if keys != nil {
+			err = c.write(b, keys[i], data.(map[string]interface{})[keys[i]], depth+1)
+		} else {
+			err = c.write(b, strconv.Itoa(i), data.([]interface{})[i], depth+1)
+		}
+		if err != nil {
+			return err
+		}
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</ul></details>")
+	return nil
+}
+
+// scalar renders a value other than an object or array
+func (c *htmlConfig) scalar(b *strings.Builder, data interface{}) error {
+	class := jsonType(data)
+	if s, ok := data.(string); ok && c.maxStringLen > 0 && utf8.RuneCountInString(s) > c.maxStringLen {
+		data = string([]rune(s)[:c.maxStringLen]) + "…"
+	}
+	enc, err := newEncodeConfig([]EncodeOption{EscapeHTML(false)}).encode(data)
+	if err != nil {
+		return err
+	}
+	c.span(b, class, string(enc))
+	return nil
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(n) + " " + noun + "s"
+}
@@ -0,0 +1,36 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestToHTML(t *testing.T) {
+	js := mustJSON(`{"name":"<b>&","tags":["a","b","c"],"meta":{},"n":null,"ok":true}`)
+
+	out, err := js.ToHTML()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `<details open><summary>{5 keys}</summary><ul>
+<li>meta: {}</li>
+<li>n: null</li>
+<li>name: &#34;&lt;b&gt;&amp;&#34;</li>
+<li>ok: true</li>
+<li><details><summary>tags: [3 items]</summary><ul>
+<li>0: &#34;a&#34;</li>
+<li>1: &#34;b&#34;</li>
+<li>2: &#34;c&#34;</li>
+</ul></details></li>
+</ul></details>`, out)
+
+	out, err = js.Get("tags").ToHTML(HTMLMaxItems(1), HTMLOpenDepth(0), HTMLClasses("j-"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `<details><summary><span class="j-summary">[3 items]</span></summary><ul>
+<li><span class="j-key">0</span>: <span class="j-string">&#34;a&#34;</span></li>
+<li><span class="j-summary">… 2 more</span></li>
+</ul></details>`, out)
+
+	out, err = mustJSON(`"abcdef"`).ToHTML(HTMLMaxStringLen(3))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `&#34;abc…&#34;`, out)
+}
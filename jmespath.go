@@ -0,0 +1,1232 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Search evaluates the JMESPath expression `expr` against the document,
+// following the specification at https://jmespath.org/specification.html,
+// including projections, filters, slices, multiselects, pipes and the
+// builtin functions. The values of objects are projected in sorted key order.
+func (j *JSON) Search(expr string) (*JSON, error) {
+	j = j.orMissing()
+	toks, err := jpLex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &jpParser{expr: expr, toks: toks}
+	n, err := p.parse(0)
+	if err == nil && p.peek().kind != jpEOF {
+		err = p.errorf("unexpected %s", p.peek())
+	}
+	if err != nil {
+		return nil, err
+	}
+	val, err := n.eval(j.data)
+	if err != nil {
+		return nil, fmt.Errorf("simplejson: search %q: %w", expr, err)
+	}
+	return j.wrap(val), nil
+}
+
+type jpKind int
+
+const (
+	jpEOF jpKind = iota
+	jpIdent
+	jpQuoted
+	jpNumber
+	jpLiteral
+	jpString
+	jpDot
+	jpStar
+	jpFlatten
+	jpFilter
+	jpLbracket
+	jpRbracket
+	jpLbrace
+	jpRbrace
+	jpLparen
+	jpRparen
+	jpComma
+	jpColon
+	jpPipe
+	jpOr
+	jpAnd
+	jpNot
+	jpCurrent
+	jpExpref
+	jpEQ
+	jpNE
+	jpLT
+	jpLTE
+	jpGT
+	jpGTE
+)
+
+type jpToken struct {
+	kind jpKind
+	text string
+	val  interface{}
+	pos  int
+}
+
+func (t jpToken) String() string {
+	if t.kind == jpEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+// jpPunct are the tokens made of punctuation, longest first
+var jpPunct = []struct {
+	text string
+	kind jpKind
+}{
+	{"[?", jpFilter}, {"[]", jpFlatten}, {"||", jpOr}, {"&&", jpAnd},
+	{"==", jpEQ}, {"!=", jpNE}, {"<=", jpLTE}, {">=", jpGTE},
+	{"<", jpLT}, {">", jpGT}, {"!", jpNot}, {".", jpDot}, {"*", jpStar},
+	{"[", jpLbracket}, {"]", jpRbracket}, {"{", jpLbrace}, {"}", jpRbrace},
+	{"(", jpLparen}, {")", jpRparen}, {",", jpComma}, {":", jpColon},
+	{"|", jpPipe}, {"@", jpCurrent}, {"&", jpExpref},
+}
+
+// jpLex splits a JMESPath expression into tokens
+func jpLex(expr string) ([]jpToken, error) {
+	var toks []jpToken
+	errorf := func(pos int, format string, args ...interface{}) error {
+		return fmt.Errorf("simplejson: search %q: offset %d: %s", expr, pos, fmt.Sprintf(format, args...))
+	}
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		start := i
+		switch {
+		case isSpace(c):
+			i++
+			continue
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			for i < len(expr) && (expr[i] == '_' || expr[i] >= 'a' && expr[i] <= 'z' || expr[i] >= 'A' && expr[i] <= 'Z' || expr[i] >= '0' && expr[i] <= '9') {
+				i++
+			}
+			toks = append(toks, jpToken{kind: jpIdent, text: expr[start:i], pos: start})
+			continue
+		case c == '-' || c >= '0' && c <= '9':
+			i++
+			for i < len(expr) && expr[i] >= '0' && expr[i] <= '9' {
+				i++
+			}
+			n, err := strconv.Atoi(expr[start:i])
+			if err != nil {
+				return nil, errorf(start, "invalid number %q", expr[start:i])
+			}
+			toks = append(toks, jpToken{kind: jpNumber, text: expr[start:i], val: n, pos: start})
+			continue
+		case c == '"' || c == '\'' || c == '`':
+			i++
+			for i < len(expr) && expr[i] != c {
+				if expr[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(expr) {
+				return nil, errorf(start, "unterminated %c", c)
+			}
+			i++
+			body := expr[start+1 : i-1]
+			tok := jpToken{text: expr[start:i], pos: start}
+			switch c {
+			case '"':
+				var s string
+				if err := json.Unmarshal([]byte(expr[start:i]), &s); err != nil {
+					return nil, errorf(start, "invalid quoted identifier %s", expr[start:i])
+				}
+				tok.kind, tok.val = jpQuoted, s
+			case '\'':
+				tok.kind, tok.val = jpString, strings.Replace(body, `\'`, `'`, -1)
+			default:
+				js, err := NewJSON([]byte(strings.Replace(body, "\\`", "`", -1)))
+				if err != nil {
+					return nil, errorf(start, "invalid literal %s", expr[start:i])
+				}
+				tok.kind, tok.val = jpLiteral, js.data
+			}
+			toks = append(toks, tok)
+			continue
+		}
+		matched := false
+		for _, p := range jpPunct {
+			if strings.HasPrefix(expr[i:], p.text) {
+				toks = append(toks, jpToken{kind: p.kind, text: p.text, pos: start})
+				i += len(p.text)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, errorf(start, "unexpected character %q", c)
+		}
+	}
+	return append(toks, jpToken{kind: jpEOF, pos: len(expr)}), nil
+}
+
+// jpPowers are the binding powers of the tokens
+var jpPowers = map[jpKind]int{
+	jpPipe:     1,
+	jpOr:       2,
+	jpAnd:      3,
+	jpEQ:       5,
+	jpNE:       5,
+	jpLT:       5,
+	jpLTE:      5,
+	jpGT:       5,
+	jpGTE:      5,
+	jpFlatten:  9,
+	jpStar:     20,
+	jpFilter:   21,
+	jpDot:      40,
+	jpNot:      45,
+	jpLbrace:   50,
+	jpLbracket: 55,
+	jpLparen:   60,
+}
+
+// jpParser is a top down operator precedence parser of JMESPath expressions
+type jpParser struct {
+	expr string
+	toks []jpToken
+	i    int
+}
+
+func (p *jpParser) peek() jpToken {
+	return p.toks[p.i]
+}
+
+func (p *jpParser) advance() jpToken {
+	t := p.toks[p.i]
+	if t.kind != jpEOF {
+		p.i++
+	}
+	return t
+}
+
+// back returns to the token `t`, which was just read, to report errors at its offset
+func (p *jpParser) back(t jpToken) {
+	if t.kind != jpEOF {
+		p.i--
+	}
+}
+
+func (p *jpParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("simplejson: search %q: offset %d: %s", p.expr, p.peek().pos, fmt.Sprintf(format, args...))
+}
+
+func (p *jpParser) expect(kind jpKind, what string) error {
+	if p.peek().kind != kind {
+		return p.errorf("expected %q, found %s", what, p.peek())
+	}
+	p.advance()
+	return nil
+}
+
+func (p *jpParser) parse(power int) (jpNode, error) {
+	left, err := p.nud(p.advance())
+	if err != nil {
+		return nil, err
+	}
+	for power < jpPowers[p.peek().kind] {
+		if left, err = p.led(p.advance(), left); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// nud parses the expressions starting with `t`
+func (p *jpParser) nud(t jpToken) (jpNode, error) {
+	switch t.kind {
+	case jpIdent:
+		return jpField(t.text), nil
+	case jpQuoted:
+		if p.peek().kind == jpLparen {
+			return nil, p.errorf("quoted identifiers cannot be called")
+		}
+		return jpField(t.val.(string)), nil
+	case jpLiteral, jpString:
+		return jpLiteralNode{t.val}, nil
+	case jpCurrent:
+		return jpIdentity{}, nil
+	case jpStar:
+		right, err := p.projectionRHS(jpPowers[jpStar])
+		return &jpValueProjection{jpIdentity{}, right}, err
+	case jpFilter:
+		return p.filter(jpIdentity{})
+	case jpFlatten:
+		right, err := p.projectionRHS(jpPowers[jpFlatten])
+		return &jpProjection{jpFlattenNode{jpIdentity{}}, right}, err
+	case jpLbrace:
+		return p.multiselectHash()
+	case jpLbracket:
+		switch k := p.peek().kind; {
+		case k == jpNumber || k == jpColon:
+			return p.index(jpIdentity{})
+		case k == jpStar && p.toks[p.i+1].kind == jpRbracket:
+			p.advance()
+			p.advance()
+			right, err := p.projectionRHS(jpPowers[jpStar])
+			return &jpProjection{jpIdentity{}, right}, err
+		}
+		return p.multiselectList()
+	case jpExpref:
+		n, err := p.parse(jpPowers[jpExpref])
+		return jpExpRef{n}, err
+	case jpNot:
+		n, err := p.parse(jpPowers[jpNot])
+		return jpNotNode{n}, err
+	case jpLparen:
+		n, err := p.parse(0)
+		if err != nil {
+			return nil, err
+		}
+		return n, p.expect(jpRparen, ")")
+	}
+	p.back(t)
+	return nil, p.errorf("unexpected %s", t)
+}
+
+// led parses the expressions continuing `left` with `t`
+func (p *jpParser) led(t jpToken, left jpNode) (jpNode, error) {
+	switch t.kind {
+	case jpDot:
+		if p.peek().kind == jpStar {
+			p.advance()
+			right, err := p.projectionRHS(jpPowers[jpStar])
+			return &jpValueProjection{left, right}, err
+		}
+		right, err := p.dotRHS(jpPowers[jpDot])
+		return &jpSubexpr{left, right}, err
+	case jpPipe:
+		right, err := p.parse(jpPowers[jpPipe])
+		return &jpPipeNode{left, right}, err
+	case jpOr, jpAnd:
+		right, err := p.parse(jpPowers[t.kind])
+		return &jpLogical{t.kind, left, right}, err
+	case jpEQ, jpNE, jpLT, jpLTE, jpGT, jpGTE:
+		right, err := p.parse(jpPowers[t.kind])
+		return &jpComparator{t.kind, left, right}, err
+	case jpLparen:
+		name, ok := left.(jpField)
+		if !ok {
+			return nil, p.errorf("invalid function call")
+		}
+		return p.call(string(name))
+	case jpFilter:
+		return p.filter(left)
+	case jpFlatten:
+		right, err := p.projectionRHS(jpPowers[jpFlatten])
+		return &jpProjection{jpFlattenNode{left}, right}, err
+	case jpLbracket:
+		switch p.peek().kind {
+		case jpNumber, jpColon:
+			return p.index(left)
+		}
+		if err := p.expect(jpStar, "*"); err != nil {
+			return nil, err
+		}
+		if err := p.expect(jpRbracket, "]"); err != nil {
+			return nil, err
+		}
+		right, err := p.projectionRHS(jpPowers[jpStar])
+		return &jpProjection{left, right}, err
+	}
+	p.back(t)
+	return nil, p.errorf("unexpected %s", t)
+}
+
+// index parses an index or a slice following "["
+func (p *jpParser) index(left jpNode) (jpNode, error) {
+	var parts [3]*int
+	n := 0
+	for p.peek().kind != jpRbracket {
+		switch p.peek().kind {
+		case jpNumber:
+			v := p.advance().val.(int)
+			parts[n] = &v
+		case jpColon:
+			if n++; n > 2 {
+				return nil, p.errorf("too many colons in slice")
+			}
+			p.advance()
+		default:
+			return nil, p.errorf("expected a number or \":\", found %s", p.peek())
+		}
+	}
+	p.advance()
+	if n == 0 {
+		if parts[0] == nil {
+			return nil, p.errorf("expected an index")
+		}
+		return &jpSubexpr{left, jpIndex(*parts[0])}, nil
+	}
+	if parts[2] != nil && *parts[2] == 0 {
+		return nil, p.errorf("slice step cannot be 0")
+	}
+	right, err := p.projectionRHS(jpPowers[jpStar])
+	return &jpProjection{&jpSubexpr{left, jpSlice(parts)}, right}, err
+}
+
+// projectionRHS parses the expression applied to each element of a projection
+func (p *jpParser) projectionRHS(power int) (jpNode, error) {
+	switch t := p.peek(); {
+	case jpPowers[t.kind] < 10:
+		return jpIdentity{}, nil
+	case t.kind == jpLbracket || t.kind == jpFilter:
+		return p.parse(power)
+	case t.kind == jpDot:
+		p.advance()
+		return p.dotRHS(power)
+	}
+	return nil, p.errorf("unexpected %s", p.peek())
+}
+
+// dotRHS parses the expression following a "."
+func (p *jpParser) dotRHS(power int) (jpNode, error) {
+	switch p.peek().kind {
+	case jpIdent, jpQuoted, jpStar:
+		return p.parse(power)
+	case jpLbracket:
+		p.advance()
+		return p.multiselectList()
+	case jpLbrace:
+		p.advance()
+		return p.multiselectHash()
+	}
+	return nil, p.errorf("expected an identifier, \"[\" or \"{\", found %s", p.peek())
+}
+
+func (p *jpParser) filter(left jpNode) (jpNode, error) {
+	cond, err := p.parse(0)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(jpRbracket, "]"); err != nil {
+		return nil, err
+	}
+	var right jpNode = jpIdentity{}
+	if p.peek().kind != jpFlatten {
+		if right, err = p.projectionRHS(jpPowers[jpFilter]); err != nil {
+			return nil, err
+		}
+	}
+	return &jpFilterProjection{left, right, cond}, nil
+}
+
+func (p *jpParser) multiselectList() (jpNode, error) {
+	var list jpMultiList
+	for {
+		n, err := p.parse(0)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, n)
+		if p.peek().kind == jpRbracket {
+			p.advance()
+			return list, nil
+		}
+		if err := p.expect(jpComma, ","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *jpParser) multiselectHash() (jpNode, error) {
+	var hash jpMultiHash
+	for {
+		t := p.advance()
+		var key string
+		switch t.kind {
+		case jpIdent:
+			key = t.text
+		case jpQuoted:
+			key = t.val.(string)
+		default:
+			p.back(t)
+			return nil, p.errorf("expected a key, found %s", t)
+		}
+		if err := p.expect(jpColon, ":"); err != nil {
+			return nil, err
+		}
+		n, err := p.parse(0)
+		if err != nil {
+			return nil, err
+		}
+		hash = append(hash, jpKeyVal{key, n})
+		if p.peek().kind == jpRbrace {
+			p.advance()
+			return hash, nil
+		}
+		if err := p.expect(jpComma, ","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *jpParser) call(name string) (jpNode, error) {
+	fn, ok := jpFuncs[name]
+	if !ok {
+		return nil, p.errorf("unknown function %s()", name)
+	}
+	var args []jpNode
+	for p.peek().kind != jpRparen {
+		if len(args) > 0 {
+			if err := p.expect(jpComma, ","); err != nil {
+				return nil, err
+			}
+		}
+		n, err := p.parse(0)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, n)
+	}
+	p.advance()
+	if len(args) < fn.min || fn.max >= 0 && len(args) > fn.max {
+		return nil, p.errorf("invalid number of arguments for %s()", name)
+	}
+	return &jpCall{name, fn.fn, args}, nil
+}
+
+// jpNode is a node of a parsed JMESPath expression
+type jpNode interface {
+	eval(v interface{}) (interface{}, error)
+}
+
+type jpIdentity struct{}
+
+func (jpIdentity) eval(v interface{}) (interface{}, error) {
+	return v, nil
+}
+
+type jpLiteralNode struct {
+	val interface{}
+}
+
+func (n jpLiteralNode) eval(interface{}) (interface{}, error) {
+	return n.val, nil
+}
+
+type jpField string
+
+func (n jpField) eval(v interface{}) (interface{}, error) {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m[string(n)], nil
+	}
+	return nil, nil
+}
+
+type jpIndex int
+
+func (n jpIndex) eval(v interface{}) (interface{}, error) {
+	if a, ok := v.([]interface{}); ok {
+		val, _ := getIndex(a, int(n))
+		return val, nil
+	}
+	return nil, nil
+}
+
+type jpSlice [3]*int
+
+func (n jpSlice) eval(v interface{}) (interface{}, error) {
+	a, ok := v.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	step := 1
+	if n[2] != nil {
+		step = *n[2]
+	}
+	bound := func(p *int, def int) int {
+		if p == nil {
+			return def
+		}
+		i := *p
+		if i < 0 {
+			i += len(a)
+		}
+		if step < 0 {
+			return clamp(i, -1, len(a)-1)
+		}
+		return clamp(i, 0, len(a))
+	}
+	out := []interface{}{}
+	if step > 0 {
+		for i := bound(n[0], 0); i < bound(n[1], len(a)); i += step {
+			out = append(out, a[i])
+		}
+	} else {
+		for i := bound(n[0], len(a)-1); i > bound(n[1], -1); i += step {
+			out = append(out, a[i])
+		}
+	}
+	return out, nil
+}
+
+func clamp(i, min, max int) int {
+	if i < min {
+		return min
+	}
+	if i > max {
+		return max
+	}
+	return i
+}
+
+type jpSubexpr struct {
+	left, right jpNode
+}
+
+func (n *jpSubexpr) eval(v interface{}) (interface{}, error) {
+	left, err := n.left.eval(v)
+	if err != nil || left == nil {
+		return nil, err
+	}
+	return n.right.eval(left)
+}
+
+// jpPipeNode evaluates `right` with the result of `left`, stopping projections
+type jpPipeNode struct {
+	left, right jpNode
+}
+
+func (n *jpPipeNode) eval(v interface{}) (interface{}, error) {
+	left, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	return n.right.eval(left)
+}
+
+// jpProject applies `right` to every element of `elems`, dropping nulls
+func jpProject(elems []interface{}, right jpNode) (interface{}, error) {
+	out := []interface{}{}
+	for _, elem := range elems {
+		val, err := right.eval(elem)
+		if err != nil {
+			return nil, err
+		}
+		if val != nil {
+			out = append(out, val)
+		}
+	}
+	return out, nil
+}
+
+type jpProjection struct {
+	left, right jpNode
+}
+
+func (n *jpProjection) eval(v interface{}) (interface{}, error) {
+	left, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	a, ok := left.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	return jpProject(a, n.right)
+}
+
+type jpValueProjection struct {
+	left, right jpNode
+}
+
+func (n *jpValueProjection) eval(v interface{}) (interface{}, error) {
+	left, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := left.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	return jpProject(jpValues(m), n.right)
+}
+
+// jpValues returns the values of `m` in sorted key order
+func jpValues(m map[string]interface{}) []interface{} {
+	vals := make([]interface{}, 0, len(m))
+	for _, k := range sortedKeys(m) {
+		vals = append(vals, m[k])
+	}
+	return vals
+}
+
+type jpFilterProjection struct {
+	left, right, cond jpNode
+}
+
+func (n *jpFilterProjection) eval(v interface{}) (interface{}, error) {
+	left, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	a, ok := left.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var matched []interface{}
+	for _, elem := range a {
+		c, err := n.cond.eval(elem)
+		if err != nil {
+			return nil, err
+		}
+		if jpTruthy(c) {
+			matched = append(matched, elem)
+		}
+	}
+	return jpProject(matched, n.right)
+}
+
+type jpFlattenNode struct {
+	left jpNode
+}
+
+func (n jpFlattenNode) eval(v interface{}) (interface{}, error) {
+	left, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	a, ok := left.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	out := []interface{}{}
+	for _, elem := range a {
+		if inner, ok := elem.([]interface{}); ok {
+			out = append(out, inner...)
+		} else {
+			out = append(out, elem)
+		}
+	}
+	return out, nil
+}
+
+type jpMultiList []jpNode
+
+func (n jpMultiList) eval(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	out := make([]interface{}, len(n))
+	for i, e := range n {
+		val, err := e.eval(v)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = val
+	}
+	return out, nil
+}
+
+type jpKeyVal struct {
+	key string
+	val jpNode
+}
+
+type jpMultiHash []jpKeyVal
+
+func (n jpMultiHash) eval(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	out := make(map[string]interface{}, len(n))
+	for _, kv := range n {
+		val, err := kv.val.eval(v)
+		if err != nil {
+			return nil, err
+		}
+		out[kv.key] = val
+	}
+	return out, nil
+}
+
+// jpTruthy reports whether `v` is true in JMESPath, where null, false and
+// empty strings, arrays and objects are false
+func jpTruthy(v interface{}) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case string:
+		return x != ""
+	case []interface{}:
+		return len(x) > 0
+	case map[string]interface{}:
+		return len(x) > 0
+	}
+	return true
+}
+
+type jpLogical struct {
+	op          jpKind
+	left, right jpNode
+}
+
+func (n *jpLogical) eval(v interface{}) (interface{}, error) {
+	left, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	if jpTruthy(left) == (n.op == jpOr) {
+		return left, nil
+	}
+	return n.right.eval(v)
+}
+
+type jpNotNode struct {
+	operand jpNode
+}
+
+func (n jpNotNode) eval(v interface{}) (interface{}, error) {
+	val, err := n.operand.eval(v)
+	return !jpTruthy(val), err
+}
+
+type jpComparator struct {
+	op          jpKind
+	left, right jpNode
+}
+
+func (n *jpComparator) eval(v interface{}) (interface{}, error) {
+	left, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case jpEQ:
+		return deepEqual(left, right), nil
+	case jpNE:
+		return !deepEqual(left, right), nil
+	}
+	x, xok := numberRat(left)
+	y, yok := numberRat(right)
+	if !xok || !yok {
+		return nil, nil
+	}
+	c := x.Cmp(y)
+	switch n.op {
+	case jpLT:
+		return c < 0, nil
+	case jpLTE:
+		return c <= 0, nil
+	case jpGT:
+		return c > 0, nil
+	}
+	return c >= 0, nil
+}
+
+// jpExpRef is an expression passed to functions like sort_by
+type jpExpRef struct {
+	node jpNode
+}
+
+func (n jpExpRef) eval(interface{}) (interface{}, error) {
+	return n, nil
+}
+
+type jpCall struct {
+	name string
+	fn   func(args []interface{}) (interface{}, error)
+	args []jpNode
+}
+
+func (n *jpCall) eval(v interface{}) (interface{}, error) {
+	args := make([]interface{}, len(n.args))
+	for i, a := range n.args {
+		val, err := a.eval(v)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = val
+	}
+	val, err := n.fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", n.name, err)
+	}
+	return val, nil
+}
+
+type jpFunc struct {
+	// min and max are the numbers of arguments, max is -1 for variadic functions
+	min, max int
+	fn       func(args []interface{}) (interface{}, error)
+}
+
+// jpFloat returns the numeric value of `v` as a float64
+func jpFloat(v interface{}) (float64, bool) {
+	r, ok := numberRat(v)
+	if !ok {
+		return 0, false
+	}
+	f, _ := r.Float64()
+	return f, true
+}
+
+func jpInvalidType(v interface{}) error {
+	return fmt.Errorf("invalid type %s", jpType(v))
+}
+
+// jpType returns the JMESPath name of the type of `v`
+func jpType(v interface{}) string {
+	if t := jsonType(v); t != "bool" {
+		return t
+	}
+	return "boolean"
+}
+
+// jpCompareKeys compares values to sort by, numbers or strings
+func jpCompareKeys(a, b interface{}) (int, error) {
+	if x, ok := numberRat(a); ok {
+		if y, ok := numberRat(b); ok {
+			return x.Cmp(y), nil
+		}
+	} else if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %s with %s", jpType(a), jpType(b))
+}
+
+// jpKeys evaluates the expression reference `ref` for each element of `a`
+func jpKeys(a []interface{}, ref interface{}) ([]interface{}, error) {
+	e, ok := ref.(jpExpRef)
+	if !ok {
+		return nil, fmt.Errorf("expected an expression reference")
+	}
+	keys := make([]interface{}, len(a))
+	for i, elem := range a {
+		k, err := e.node.eval(elem)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = k
+	}
+	return keys, nil
+}
+
+// jpExtreme returns the element of `a` with the smallest or largest key
+func jpExtreme(a, keys []interface{}, max bool) (interface{}, error) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	if _, err := jpCompareKeys(keys[0], keys[0]); err != nil {
+		return nil, err
+	}
+	var best int
+	for i := 1; i < len(a); i++ {
+		c, err := jpCompareKeys(keys[i], keys[best])
+		if err != nil {
+			return nil, err
+		}
+		if max && c > 0 || !max && c < 0 {
+			best = i
+		}
+	}
+	return a[best], nil
+}
+
+// jpSortBy sorts `a` by `keys`
+func jpSortBy(a, keys []interface{}) (interface{}, error) {
+	idx := make([]int, len(a))
+	for i := range idx {
+		idx[i] = i
+	}
+	var err error
+	sort.SliceStable(idx, func(x, y int) bool {
+		c, e := jpCompareKeys(keys[idx[x]], keys[idx[y]])
+		if e != nil && err == nil {
+			err = e
+		}
+		return c < 0
+	})
+	if err == nil && len(a) == 1 {
+		_, err = jpCompareKeys(keys[0], keys[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]interface{}, len(a))
+	for i, k := range idx {
+		out[i] = a[k]
+	}
+	return out, nil
+}
+
+var jpFuncs map[string]jpFunc
+
+func init() {
+	jpFuncs = map[string]jpFunc{
+		"abs": {1, 1, func(args []interface{}) (interface{}, error) {
+			r, ok := numberRat(args[0])
+			if !ok {
+				return nil, jpInvalidType(args[0])
+			}
+			return ratNumber(new(big.Rat).Abs(r)), nil
+		}},
+		"avg": {1, 1, func(args []interface{}) (interface{}, error) {
+			a, ok := args[0].([]interface{})
+			if !ok {
+				return nil, jpInvalidType(args[0])
+			}
+			if len(a) == 0 {
+				return nil, nil
+			}
+			sum := new(big.Rat)
+			for _, v := range a {
+				r, ok := numberRat(v)
+				if !ok {
+					return nil, jpInvalidType(v)
+				}
+				sum.Add(sum, r)
+			}
+			return ratNumber(sum.Quo(sum, new(big.Rat).SetInt64(int64(len(a))))), nil
+		}},
+		"ceil": {1, 1, func(args []interface{}) (interface{}, error) {
+			f, ok := jpFloat(args[0])
+			if !ok {
+				return nil, jpInvalidType(args[0])
+			}
+			return math.Ceil(f), nil
+		}},
+		"contains": {2, 2, func(args []interface{}) (interface{}, error) {
+			switch x := args[0].(type) {
+			case string:
+				s, ok := args[1].(string)
+				return ok && strings.Contains(x, s), nil
+			case []interface{}:
+				for _, v := range x {
+					if deepEqual(v, args[1]) {
+						return true, nil
+					}
+				}
+				return false, nil
+			}
+			return nil, jpInvalidType(args[0])
+		}},
+		"ends_with": {2, 2, func(args []interface{}) (interface{}, error) {
+			s, ok1 := args[0].(string)
+			suffix, ok2 := args[1].(string)
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("expected strings")
+			}
+			return strings.HasSuffix(s, suffix), nil
+		}},
+		"floor": {1, 1, func(args []interface{}) (interface{}, error) {
+			f, ok := jpFloat(args[0])
+			if !ok {
+				return nil, jpInvalidType(args[0])
+			}
+			return math.Floor(f), nil
+		}},
+		"join": {2, 2, func(args []interface{}) (interface{}, error) {
+			sep, ok := args[0].(string)
+			a, ok2 := args[1].([]interface{})
+			if !ok || !ok2 {
+				return nil, fmt.Errorf("expected a string and an array of strings")
+			}
+			strs := make([]string, len(a))
+			for i, v := range a {
+				if strs[i], ok = v.(string); !ok {
+					return nil, jpInvalidType(v)
+				}
+			}
+			return strings.Join(strs, sep), nil
+		}},
+		"keys": {1, 1, func(args []interface{}) (interface{}, error) {
+			m, ok := args[0].(map[string]interface{})
+			if !ok {
+				return nil, jpInvalidType(args[0])
+			}
+			return stringsToValues(sortedKeys(m)), nil
+		}},
+		"length": {1, 1, func(args []interface{}) (interface{}, error) {
+			switch x := args[0].(type) {
+			case string:
+				return json.Number(strconv.Itoa(utf8.RuneCountInString(x))), nil
+			case []interface{}:
+				return json.Number(strconv.Itoa(len(x))), nil
+			case map[string]interface{}:
+				return json.Number(strconv.Itoa(len(x))), nil
+			}
+			return nil, jpInvalidType(args[0])
+		}},
+		"map": {2, 2, func(args []interface{}) (interface{}, error) {
+			a, ok := args[1].([]interface{})
+			if !ok {
+				return nil, jpInvalidType(args[1])
+			}
+			return jpKeys(a, args[0])
+		}},
+		"max": {1, 1, func(args []interface{}) (interface{}, error) {
+			a, ok := args[0].([]interface{})
+			if !ok {
+				return nil, jpInvalidType(args[0])
+			}
+			return jpExtreme(a, a, true)
+		}},
+		"max_by": {2, 2, func(args []interface{}) (interface{}, error) {
+			a, ok := args[0].([]interface{})
+			if !ok {
+				return nil, jpInvalidType(args[0])
+			}
+			keys, err := jpKeys(a, args[1])
+			if err != nil {
+				return nil, err
+			}
+			return jpExtreme(a, keys, true)
+		}},
+		"merge": {1, -1, func(args []interface{}) (interface{}, error) {
+			out := make(map[string]interface{})
+			for _, arg := range args {
+				m, ok := arg.(map[string]interface{})
+				if !ok {
+					return nil, jpInvalidType(arg)
+				}
+				for k, v := range m {
+					out[k] = v
+				}
+			}
+			return out, nil
+		}},
+		"min": {1, 1, func(args []interface{}) (interface{}, error) {
+			a, ok := args[0].([]interface{})
+			if !ok {
+				return nil, jpInvalidType(args[0])
+			}
+			return jpExtreme(a, a, false)
+		}},
+		"min_by": {2, 2, func(args []interface{}) (interface{}, error) {
+			a, ok := args[0].([]interface{})
+			if !ok {
+				return nil, jpInvalidType(args[0])
+			}
+			keys, err := jpKeys(a, args[1])
+			if err != nil {
+				return nil, err
+			}
+			return jpExtreme(a, keys, false)
+		}},
+		"not_null": {1, -1, func(args []interface{}) (interface{}, error) {
+			for _, arg := range args {
+				if arg != nil {
+					return arg, nil
+				}
+			}
+			return nil, nil
+		}},
+		"reverse": {1, 1, func(args []interface{}) (interface{}, error) {
+			switch x := args[0].(type) {
+			case string:
+				r := []rune(x)
+				for i, k := 0, len(r)-1; i < k; i, k = i+1, k-1 {
+					r[i], r[k] = r[k], r[i]
+				}
+				return string(r), nil
+			case []interface{}:
+				out := make([]interface{}, len(x))
+				for i, v := range x {
+					out[len(x)-1-i] = v
+				}
+				return out, nil
+			}
+			return nil, jpInvalidType(args[0])
+		}},
+		"sort": {1, 1, func(args []interface{}) (interface{}, error) {
+			a, ok := args[0].([]interface{})
+			if !ok {
+				return nil, jpInvalidType(args[0])
+			}
+			return jpSortBy(a, a)
+		}},
+		"sort_by": {2, 2, func(args []interface{}) (interface{}, error) {
+			a, ok := args[0].([]interface{})
+			if !ok {
+				return nil, jpInvalidType(args[0])
+			}
+			keys, err := jpKeys(a, args[1])
+			if err != nil {
+				return nil, err
+			}
+			return jpSortBy(a, keys)
+		}},
+		"starts_with": {2, 2, func(args []interface{}) (interface{}, error) {
+			s, ok1 := args[0].(string)
+			prefix, ok2 := args[1].(string)
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("expected strings")
+			}
+			return strings.HasPrefix(s, prefix), nil
+		}},
+		"sum": {1, 1, func(args []interface{}) (interface{}, error) {
+			a, ok := args[0].([]interface{})
+			if !ok {
+				return nil, jpInvalidType(args[0])
+			}
+			sum := new(big.Rat)
+			for _, v := range a {
+				r, ok := numberRat(v)
+				if !ok {
+					return nil, jpInvalidType(v)
+				}
+				sum.Add(sum, r)
+			}
+			return ratNumber(sum), nil
+		}},
+		"to_array": {1, 1, func(args []interface{}) (interface{}, error) {
+			if a, ok := args[0].([]interface{}); ok {
+				return a, nil
+			}
+			return []interface{}{args[0]}, nil
+		}},
+		"to_number": {1, 1, func(args []interface{}) (interface{}, error) {
+			if _, ok := numberRat(args[0]); ok {
+				return args[0], nil
+			}
+			if s, ok := args[0].(string); ok && isValidNumber(s) {
+				return json.Number(s), nil
+			}
+			return nil, nil
+		}},
+		"to_string": {1, 1, func(args []interface{}) (interface{}, error) {
+			if s, ok := args[0].(string); ok {
+				return s, nil
+			}
+			b, err := newEncodeConfig(nil).encode(args[0])
+			return string(b), err
+		}},
+		"type": {1, 1, func(args []interface{}) (interface{}, error) {
+			return jpType(args[0]), nil
+		}},
+		"values": {1, 1, func(args []interface{}) (interface{}, error) {
+			m, ok := args[0].(map[string]interface{})
+			if !ok {
+				return nil, jpInvalidType(args[0])
+			}
+			return jpValues(m), nil
+		}},
+	}
+}
@@ -0,0 +1,102 @@
+package simplejson
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestSearch(t *testing.T) {
+	js, err := NewJSON([]byte(`{
+		"reservations": [
+			{"instances": [{"id":"i-1","state":{"name":"running"},"tags":[{"Key":"env","Value":"prod"}],"cpu":4},
+			               {"id":"i-2","state":{"name":"stopped"},"cpu":2}]},
+			{"instances": [{"id":"i-3","state":{"name":"running"},"cpu":8}]}
+		],
+		"people": [{"name":"a","age":30},{"name":"b","age":20},{"name":"c","age":40}],
+		"ops": {"x":{"n":1},"y":{"n":2},"z":{}},
+		"nested": [[1,2],[3,[4]],5],
+		"str": "hello",
+		"foo-bar": 1
+	}`))
+	assert.Equal(t, nil, err)
+
+	cases := []struct {
+		expr string
+		want string
+	}{
+		{`reservations[0].instances[1].id`, `"i-2"`},
+		{`reservations[].instances[].id`, `["i-1","i-2","i-3"]`},
+		{`reservations[*].instances[*].id`, `[["i-1","i-2"],["i-3"]]`},
+		{`reservations[].instances[?state.name == 'running'].id`, `[["i-1"],["i-3"]]`},
+		{`reservations[].instances[] | [?cpu > ` + "`2`" + `].id`, `["i-1","i-3"]`},
+		{`reservations[].instances[].tags[?Key=='env'].Value | []`, `["prod"]`},
+		{`people[?age >= ` + "`30`" + ` && name != 'c'].name`, `["a"]`},
+		{`people[?!(age > ` + "`25`" + `)].name`, `["b"]`},
+		{`people[-1].name`, `"c"`},
+		{`people[::2].name`, `["a","c"]`},
+		{`people[::-1].name`, `["c","b","a"]`},
+		{`people[1:].name | [0]`, `"b"`},
+		{`ops.*.n`, `[1,2]`},
+		{`nested[]`, `[1,2,3,[4],5]`},
+		{`nested[][]`, `[1,2,3,4,5]`},
+		{`people[0].[name, age]`, `["a",30]`},
+		{`people[*].{n: name, "old": age > ` + "`25`" + `}`, `[{"n":"a","old":true},{"n":"b","old":false},{"n":"c","old":true}]`},
+		{`"foo-bar"`, `1`},
+		{`missing || str`, `"hello"`},
+		{`str && 'yes'`, `"yes"`},
+		{"`{\"a\": [1]}`.a[0]", `1`},
+		{`length(people) == ` + "`3`" + ` && length(str) == ` + "`5`", `true`},
+		{`sort_by(people, &age)[*].name`, `["b","a","c"]`},
+		{`max_by(people, &age).name`, `"c"`},
+		{`min_by(people, &age).name`, `"b"`},
+		{`map(&age, people)`, `[30,20,40]`},
+		{`sum(people[*].age)`, `90`},
+		{`avg(people[*].age)`, `30`},
+		{`max(people[*].age)`, `40`},
+		{`sort(people[*].name)`, `["a","b","c"]`},
+		{`join(', ', people[*].name)`, `"a, b, c"`},
+		{`keys(ops)`, `["x","y","z"]`},
+		{`values(ops)[*].n`, `[1,2]`},
+		{`contains(people[*].name, 'b') && starts_with(str, 'he') && ends_with(str, 'lo')`, `true`},
+		{`reverse(str)`, `"olleh"`},
+		{`str[1:3]`, `null`},
+		{`merge(ops.x, ops.y, ` + "`{\"m\": true}`" + `)`, `{"m":true,"n":2}`},
+		{`not_null(missing, str)`, `"hello"`},
+		{`[type(str), type(people), type(ops.x.n), type(` + "`true`" + `), type(missing)]`, `["string","array","number","boolean","null"]`},
+		{`to_number('12.5')`, `12.5`},
+		{`to_string(ops.x)`, `"{\"n\":1}"`},
+		{`abs(` + "`-3`" + `)`, `3`},
+		{`floor(` + "`1.5`" + `) == ` + "`1`", `true`},
+		{`missing.deep[0]`, `null`},
+		{`people | length(@)`, `3`},
+	}
+	for _, c := range cases {
+		res, err := js.Search(c.expr)
+		assert.Equal(t, nil, err, c.expr)
+		if err != nil {
+			continue
+		}
+		b, _ := res.Encode()
+		assert.Equal(t, c.want, string(b), c.expr)
+	}
+
+	for expr, msg := range map[string]string{
+		`people[`:             `simplejson: search "people[": offset 7: expected "*", found end of expression`,
+		`foo(people)`:         `simplejson: search "foo(people)": offset 4: unknown function foo()`,
+		`length(people, str)`: `simplejson: search "length(people, str)": offset 19: invalid number of arguments for length()`,
+		`sum(people)`:         `simplejson: search "sum(people)": sum(): invalid type object`,
+		"`{`":                 "simplejson: search \"`{`\": offset 0: invalid literal `{`",
+		`people[::0]`:         `simplejson: search "people[::0]": offset 11: slice step cannot be 0`,
+	} {
+		_, err := js.Search(expr)
+		if assert.NotEqual(t, nil, err, expr); err != nil {
+			assert.Equal(t, msg, err.Error(), expr)
+		}
+	}
+
+	_, err = js.Search("sum(people)")
+	assert.Equal(t, "sum(): invalid type object", errors.Unwrap(err).Error())
+	assert.Equal(t, "invalid type object", errors.Unwrap(errors.Unwrap(err)).Error())
+}
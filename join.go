@@ -0,0 +1,101 @@
+package simplejson
+
+import "fmt"
+
+// JoinKind selects the records kept by Join
+type JoinKind int
+
+const (
+	// InnerJoin keeps the left records matching at least one right record
+	InnerJoin JoinKind = iota
+	// LeftJoin keeps every left record, unchanged when nothing matches
+	LeftJoin
+)
+
+// Join matches the objects of the arrays `left` and `right` whose values at the
+// dotted paths `leftKey` and `rightKey` are equal, numbers comparing by value,
+// and returns an array of the merged records in the order of `left`.
+// A left record matching several right records is merged with each of them,
+// the fields of the left record win over those of the right one.
+// Records missing the key never match, neither do null keys.
+func Join(left, right *JSON, leftKey, rightKey string, kind JoinKind) (*JSON, error) {
+	l, err := joinRecords(left, "left")
+	if err != nil {
+		return nil, err
+	}
+	r, err := joinRecords(right, "right")
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string][]map[string]interface{})
+	for _, rec := range r {
+		key, ok, err := joinKey(rec, rightKey)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			index[key] = append(index[key], rec)
+		}
+	}
+
+	out := []interface{}{}
+	for _, rec := range l {
+		key, ok, err := joinKey(rec, leftKey)
+		if err != nil {
+			return nil, err
+		}
+		var matches []map[string]interface{}
+		if ok {
+			matches = index[key]
+		}
+		if len(matches) == 0 {
+			if kind == LeftJoin {
+				out = append(out, deepCopy(rec))
+			}
+			continue
+		}
+		for _, match := range matches {
+			merged := deepCopy(match).(map[string]interface{})
+			for k, v := range rec {
+				merged[k] = deepCopy(v)
+			}
+			out = append(out, merged)
+		}
+	}
+
+	j := &JSON{data: out}
+	j.document()
+	return j, nil
+}
+
+// joinRecords returns the objects of the array `j`
+func joinRecords(j *JSON, side string) ([]map[string]interface{}, error) {
+	a, ok := j.CheckArray()
+	if !ok {
+		return nil, fmt.Errorf("simplejson: Join %s side is %s, not an array", side, jsonType(j.data))
+	}
+	recs := make([]map[string]interface{}, len(a))
+	for i, v := range a {
+		if recs[i], ok = v.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("simplejson: Join %s element %d is %s, not an object", side, i, jsonType(v))
+		}
+	}
+	return recs, nil
+}
+
+// joinKey returns the canonical encoding of the value at `path` in `rec`
+func joinKey(rec map[string]interface{}, path string) (string, bool, error) {
+	val, ok := lookup(rec, dottedBranch(rec, path))
+	if !ok || val == nil {
+		return "", false, nil
+	}
+	key, err := canonicalKey(val)
+	return key, err == nil, err
+}
+
+// canonicalKey returns an encoding of `val` which is the same for equal values
+func canonicalKey(val interface{}) (string, error) {
+	b, err := newEncodeConfig([]EncodeOption{SortedKeys(), NormalizedNumbers()}).encode(val)
+	return string(b), err
+}
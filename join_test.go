@@ -0,0 +1,41 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestJoin(t *testing.T) {
+	events, _ := NewJSON([]byte(`[
+		{"id":1,"user":{"id":10},"name":"login"},
+		{"id":2,"user":{"id":20},"name":"logout"},
+		{"id":3,"user":{"id":30}},
+		{"id":4}
+	]`))
+	users, _ := NewJSON([]byte(`[
+		{"uid":10.0,"name":"ann","role":"admin"},
+		{"uid":20,"name":"bob"},
+		{"uid":20,"name":"bob2"}
+	]`))
+
+	js, err := Join(events, users, "user.id", "uid", InnerJoin)
+	assert.Equal(t, nil, err)
+	b, _ := js.Encode()
+	assert.Equal(t, `[{"id":1,"name":"login","role":"admin","uid":10.0,"user":{"id":10}},{"id":2,"name":"logout","uid":20,"user":{"id":20}},{"id":2,"name":"logout","uid":20,"user":{"id":20}}]`, string(b))
+
+	js, err = Join(events, users, "user.id", "uid", LeftJoin)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 5, len(js.Array()))
+	assert.Equal(t, 30, js.Get(3, "user", "id").Int())
+	assert.Equal(t, 4, js.Get(4, "id").Int())
+
+	js.Get(0).Set("role", "changed")
+	assert.Equal(t, "admin", users.Get(0, "role").String())
+
+	_, err = Join(events.Get(0), users, "id", "uid", InnerJoin)
+	assert.Equal(t, "simplejson: Join left side is object, not an array", err.Error())
+	bad, _ := NewJSON([]byte(`[{"uid":1}, 2]`))
+	_, err = Join(events, bad, "id", "uid", InnerJoin)
+	assert.Equal(t, "simplejson: Join right element 1 is number, not an object", err.Error())
+}
@@ -0,0 +1,225 @@
+// Package jsonrpc builds and parses JSON-RPC 2.0 messages as simplejson
+// documents:
+//
+//	req := jsonrpc.NewRequest(1, "subtract", []interface{}{42, 23})
+//	body, _ := req.Encode()
+//
+//	msgs, batch, err := jsonrpc.Parse(reply)
+//	for _, m := range msgs {
+//		if err := m.Err(); err != nil { ... }
+//		result := m.Result()
+//	}
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	simplejson "github.com/brunotm/go-simplejson"
+)
+
+// Version is the protocol version set in every message
+const Version = "2.0"
+
+// Error codes defined by the specification,
+// codes from -32000 to -32099 are reserved for server errors
+const (
+	ParseError     = -32700
+	InvalidRequest = -32600
+	MethodNotFound = -32601
+	InvalidParams  = -32602
+	InternalError  = -32603
+)
+
+// Error is the error object of a response
+type Error struct {
+	Code    int
+	Message string
+	Data    interface{}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonrpc: %s (%d)", e.Message, e.Code)
+}
+
+// NewRequest returns a request calling `method` with `params`, which are
+// omitted when nil. The `id` should be a string or an integer.
+func NewRequest(id interface{}, method string, params interface{}) *simplejson.JSON {
+	js := simplejson.O("jsonrpc", Version, "method", method, "id", id)
+	if params != nil {
+		js.Set("params", unwrap(params))
+	}
+	return js
+}
+
+// NewNotification returns a request without id, to which no response is sent
+func NewNotification(method string, params interface{}) *simplejson.JSON {
+	js := NewRequest(nil, method, params)
+	js.Del("id")
+	return js
+}
+
+// NewResponse returns a successful response to the request `id`
+func NewResponse(id interface{}, result interface{}) *simplejson.JSON {
+	return simplejson.O("jsonrpc", Version, "id", id, "result", unwrap(result))
+}
+
+// NewErrorResponse returns an error response to the request `id`, which is
+// nil when it could not be determined. `data` is omitted when nil.
+func NewErrorResponse(id interface{}, err *Error) *simplejson.JSON {
+	e := simplejson.O("code", err.Code, "message", err.Message)
+	if err.Data != nil {
+		e.Set("data", unwrap(err.Data))
+	}
+	return simplejson.O("jsonrpc", Version, "id", id, "error", e)
+}
+
+// NewBatch returns a batch of messages
+func NewBatch(msgs ...*simplejson.JSON) *simplejson.JSON {
+	vals := make([]interface{}, len(msgs))
+	for i, m := range msgs {
+		vals[i] = m
+	}
+	return simplejson.A(vals...)
+}
+
+func unwrap(v interface{}) interface{} {
+	if js, ok := v.(*simplejson.JSON); ok {
+		return js.Interface()
+	}
+	return v
+}
+
+// Message is a received request, notification or response
+type Message struct {
+	*simplejson.JSON
+}
+
+// Parse decodes a single message or a batch, reported by `batch`.
+// Malformed JSON fails with a ParseError and an empty batch with an
+// InvalidRequest *Error, use Validate to check each message.
+func Parse(body []byte) (msgs []*Message, batch bool, err error) {
+	js, err := simplejson.NewJSON(body)
+	if err != nil {
+		return nil, false, &Error{Code: ParseError, Message: "Parse error", Data: err.Error()}
+	}
+	if elems, ok := js.CheckJSONArray(); ok {
+		if len(elems) == 0 {
+			return nil, true, &Error{Code: InvalidRequest, Message: "Invalid Request", Data: "empty batch"}
+		}
+		msgs = make([]*Message, len(elems))
+		for i, elem := range elems {
+			msgs[i] = &Message{elem}
+		}
+		return msgs, true, nil
+	}
+	return []*Message{{js}}, false, nil
+}
+
+// Validate returns an InvalidRequest *Error if the message is neither a
+// valid request, notification nor response
+func (m *Message) Validate() error {
+	invalid := func(reason string) error {
+		return &Error{Code: InvalidRequest, Message: "Invalid Request", Data: reason}
+	}
+	if _, ok := m.CheckMap(); !ok {
+		return invalid("message is not an object")
+	}
+	if v, _ := m.Get("jsonrpc").CheckString(); v != Version {
+		return invalid(`"jsonrpc" must be "2.0"`)
+	}
+	if id, ok := m.ID(); ok {
+		switch id.(type) {
+		case nil, string, json.Number:
+		default:
+			return invalid(`"id" must be a string, a number or null`)
+		}
+	}
+
+	_, hasResult := m.CheckGet("result")
+	_, hasError := m.CheckGet("error")
+	if method, ok := m.CheckGet("method"); ok {
+		if _, ok := method.CheckString(); !ok {
+			return invalid(`"method" must be a string`)
+		}
+		if params, ok := m.CheckGet("params"); ok {
+			_, isMap := params.CheckMap()
+			_, isArray := params.CheckArray()
+			if !isMap && !isArray {
+				return invalid(`"params" must be an object or an array`)
+			}
+		}
+		return nil
+	}
+	if _, ok := m.ID(); !ok {
+		return invalid(`missing "method" or "id"`)
+	}
+	if hasResult == hasError {
+		return invalid(`responses must hold either "result" or "error"`)
+	}
+	if hasError {
+		if _, ok := m.Get("error", "code").CheckInt(); !ok {
+			return invalid(`"error.code" must be an integer`)
+		}
+	}
+	return nil
+}
+
+// ID returns the id of the message and whether it has one
+func (m *Message) ID() (interface{}, bool) {
+	id, ok := m.CheckGet("id")
+	if !ok {
+		return nil, false
+	}
+	return id.Interface(), true
+}
+
+// IsRequest reports whether the message is a request expecting a response
+func (m *Message) IsRequest() bool {
+	_, hasID := m.ID()
+	return m.Method() != "" && hasID
+}
+
+// IsNotification reports whether the message is a request without id
+func (m *Message) IsNotification() bool {
+	_, hasID := m.ID()
+	return m.Method() != "" && !hasID
+}
+
+// IsResponse reports whether the message is a response
+func (m *Message) IsResponse() bool {
+	if _, ok := m.CheckGet("method"); ok {
+		return false
+	}
+	_, hasResult := m.CheckGet("result")
+	_, hasError := m.CheckGet("error")
+	return hasResult || hasError
+}
+
+// Method returns the method called by a request
+func (m *Message) Method() string {
+	return m.Get("method").String()
+}
+
+// Params returns the parameters of a request
+func (m *Message) Params() *simplejson.JSON {
+	return m.Get("params")
+}
+
+// Result returns the result of a successful response
+func (m *Message) Result() *simplejson.JSON {
+	return m.Get("result")
+}
+
+// Err returns the error of an error response, or nil
+func (m *Message) Err() *Error {
+	e, ok := m.CheckGet("error")
+	if !ok {
+		return nil
+	}
+	return &Error{
+		Code:    e.Get("code").Int(),
+		Message: e.Get("message").String(),
+		Data:    e.Get("data").Interface(),
+	}
+}
@@ -0,0 +1,101 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	simplejson "github.com/brunotm/go-simplejson"
+)
+
+func encode(t *testing.T, js *simplejson.JSON) string {
+	b, err := js.Encode()
+	assert.Equal(t, nil, err)
+	return string(b)
+}
+
+func TestBuild(t *testing.T) {
+	assert.Equal(t, `{"id":1,"jsonrpc":"2.0","method":"subtract","params":[42,23]}`,
+		encode(t, NewRequest(1, "subtract", []interface{}{42, 23})))
+	assert.Equal(t, `{"jsonrpc":"2.0","method":"update","params":{"a":1}}`,
+		encode(t, NewNotification("update", simplejson.O("a", 1))))
+	assert.Equal(t, `{"id":"x","jsonrpc":"2.0","method":"ping"}`,
+		encode(t, NewRequest("x", "ping", nil)))
+	assert.Equal(t, `{"id":1,"jsonrpc":"2.0","result":null}`,
+		encode(t, NewResponse(1, nil)))
+	assert.Equal(t, `{"error":{"code":-32601,"message":"Method not found"},"id":null,"jsonrpc":"2.0"}`,
+		encode(t, NewErrorResponse(nil, &Error{Code: MethodNotFound, Message: "Method not found"})))
+	assert.Equal(t, `{"error":{"code":-32000,"data":{"retry":true},"message":"busy"},"id":2,"jsonrpc":"2.0"}`,
+		encode(t, NewErrorResponse(2, &Error{Code: -32000, Message: "busy", Data: simplejson.O("retry", true)})))
+	assert.Equal(t, `[{"id":1,"jsonrpc":"2.0","result":7},{"jsonrpc":"2.0","method":"n"}]`,
+		encode(t, NewBatch(NewResponse(1, 7), NewNotification("n", nil))))
+}
+
+func TestParse(t *testing.T) {
+	msgs, batch, err := Parse([]byte(`{"jsonrpc":"2.0","method":"sum","params":[1,2],"id":"a"}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, batch)
+	m := msgs[0]
+	assert.Equal(t, nil, m.Validate())
+	assert.Equal(t, true, m.IsRequest())
+	assert.Equal(t, false, m.IsNotification())
+	assert.Equal(t, false, m.IsResponse())
+	assert.Equal(t, "sum", m.Method())
+	assert.Equal(t, 2, m.Params().Get(1).Int())
+	id, _ := m.ID()
+	assert.Equal(t, "a", id)
+
+	msgs, batch, err = Parse([]byte(`[
+		{"jsonrpc":"2.0","result":null,"id":1},
+		{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request","data":"x"},"id":null},
+		{"jsonrpc":"2.0","method":"notify"},
+		{"jsonrpc":"1.0","method":"old","id":1},
+		{"jsonrpc":"2.0","method":1},
+		{"jsonrpc":"2.0","method":"m","params":1},
+		{"jsonrpc":"2.0","result":1,"error":{"code":1},"id":1},
+		{"jsonrpc":"2.0","id":{}},
+		1
+	]`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, batch)
+	assert.Equal(t, 9, len(msgs))
+
+	assert.Equal(t, nil, msgs[0].Validate())
+	assert.Equal(t, true, msgs[0].IsResponse())
+	assert.Equal(t, (*Error)(nil), msgs[0].Err())
+	id, _ = msgs[0].ID()
+	assert.Equal(t, json.Number("1"), id)
+
+	assert.Equal(t, nil, msgs[1].Validate())
+	assert.Equal(t, &Error{Code: InvalidRequest, Message: "Invalid Request", Data: "x"}, msgs[1].Err())
+	id, ok := msgs[1].ID()
+	assert.Equal(t, nil, id)
+	assert.Equal(t, true, ok)
+
+	assert.Equal(t, nil, msgs[2].Validate())
+	assert.Equal(t, true, msgs[2].IsNotification())
+
+	reasons := []string{
+		`"jsonrpc" must be "2.0"`,
+		`"method" must be a string`,
+		`"params" must be an object or an array`,
+		`responses must hold either "result" or "error"`,
+		`"id" must be a string, a number or null`,
+		`message is not an object`,
+	}
+	for i, reason := range reasons {
+		err := msgs[3+i].Validate().(*Error)
+		assert.Equal(t, InvalidRequest, err.Code)
+		assert.Equal(t, reason, err.Data)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	_, _, err := Parse([]byte(`{"jsonrpc"`))
+	assert.Equal(t, ParseError, err.(*Error).Code)
+	assert.Equal(t, "jsonrpc: Parse error (-32700)", err.Error())
+
+	_, batch, err := Parse([]byte(`[]`))
+	assert.Equal(t, true, batch)
+	assert.Equal(t, InvalidRequest, err.(*Error).Code)
+}
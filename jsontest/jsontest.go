@@ -0,0 +1,194 @@
+// Package jsontest provides assertion helpers for tests that compare
+// simplejson documents.
+//
+// Failures are reported as a list of path scoped differences instead of
+// raw encoded bytes, which keeps nested documents readable:
+//
+//	jsontest.AssertEqual(t, want, got)
+//	// $.items[2].name: want "foo", got "bar"
+//	// $.items[3]: missing
+package jsontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	simplejson "github.com/brunotm/go-simplejson"
+)
+
+// AssertEqual fails the test if `want` and `got` differ in any value
+func AssertEqual(t testing.TB, want, got *simplejson.JSON) {
+	t.Helper()
+	if diffs := Diff(want, got); len(diffs) > 0 {
+		t.Errorf("documents differ:\n%s", strings.Join(diffs, "\n"))
+	}
+}
+
+// AssertSubset fails the test if `got` does not contain every value in `want`.
+// Extra keys in `got` objects are ignored, arrays must match in length.
+func AssertSubset(t testing.TB, want, got *simplejson.JSON) {
+	t.Helper()
+	var diffs []string
+	compare("$", interfaceOf(want), interfaceOf(got), true, &diffs)
+	if len(diffs) > 0 {
+		t.Errorf("document is not a superset:\n%s", strings.Join(diffs, "\n"))
+	}
+}
+
+// AssertPathEquals fails the test if the value found at `path` within `js`
+// differs from `want`. `want` may be a *simplejson.JSON or any plain value.
+func AssertPathEquals(t testing.TB, js *simplejson.JSON, path []interface{}, want interface{}) {
+	t.Helper()
+	got, ok := js.CheckGet(path...)
+	if !ok {
+		t.Errorf("%s: missing, want %s", formatPath(path), describe(want))
+		return
+	}
+	if w, ok := want.(*simplejson.JSON); ok {
+		want = w.Interface()
+	}
+	var diffs []string
+	compare(formatPath(path), want, got.Interface(), false, &diffs)
+	if len(diffs) > 0 {
+		t.Errorf("documents differ:\n%s", strings.Join(diffs, "\n"))
+	}
+}
+
+// Diff returns a sorted list of human readable differences between two documents
+func Diff(want, got *simplejson.JSON) []string {
+	var diffs []string
+	compare("$", interfaceOf(want), interfaceOf(got), false, &diffs)
+	return diffs
+}
+
+func interfaceOf(j *simplejson.JSON) interface{} {
+	if j == nil {
+		return nil
+	}
+	return j.Interface()
+}
+
+func compare(path string, want, got interface{}, subset bool, diffs *[]string) {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(w))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			gv, ok := g[k]
+			if !ok {
+				*diffs = append(*diffs, fmt.Sprintf("%s: missing, want %s", keyPath(path, k), describe(w[k])))
+				continue
+			}
+			compare(keyPath(path, k), w[k], gv, subset, diffs)
+		}
+		if subset {
+			return
+		}
+		extra := []string{}
+		for k := range g {
+			if _, ok := w[k]; !ok {
+				extra = append(extra, k)
+			}
+		}
+		sort.Strings(extra)
+		for _, k := range extra {
+			*diffs = append(*diffs, fmt.Sprintf("%s: unexpected %s", keyPath(path, k), describe(g[k])))
+		}
+		return
+
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(w) || i < len(g); i++ {
+			p := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= len(g):
+				*diffs = append(*diffs, fmt.Sprintf("%s: missing, want %s", p, describe(w[i])))
+			case i >= len(w):
+				*diffs = append(*diffs, fmt.Sprintf("%s: unexpected %s", p, describe(g[i])))
+			default:
+				compare(p, w[i], g[i], subset, diffs)
+			}
+		}
+		return
+	}
+
+	if !equalScalar(want, got) {
+		*diffs = append(*diffs, fmt.Sprintf("%s: want %s, got %s", path, describe(want), describe(got)))
+	}
+}
+
+// equalScalar compares leaf values, treating numbers of any representation
+// (json.Number, float64, int...) as equal when they hold the same value
+func equalScalar(want, got interface{}) bool {
+	wf, wok := toFloat(want)
+	gf, gok := toFloat(got)
+	if wok && gok {
+		return wf == gf
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		f, err := strconv.ParseFloat(fmt.Sprint(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func describe(v interface{}) string {
+	if j, ok := v.(*simplejson.JSON); ok {
+		v = j.Interface()
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%#v", v)
+	}
+	if len(b) > 80 {
+		return string(b[:77]) + "..."
+	}
+	return string(b)
+}
+
+func keyPath(path, key string) string {
+	for _, r := range key {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return path + "[" + strconv.Quote(key) + "]"
+		}
+	}
+	return path + "." + key
+}
+
+func formatPath(branch []interface{}) string {
+	path := "$"
+	for _, b := range branch {
+		switch p := b.(type) {
+		case string:
+			path = keyPath(path, p)
+		case int:
+			path += "[" + strconv.Itoa(p) + "]"
+		default:
+			path += fmt.Sprintf("[%v]", p)
+		}
+	}
+	return path
+}
@@ -0,0 +1,57 @@
+package jsontest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	simplejson "github.com/brunotm/go-simplejson"
+)
+
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func mustJSON(t *testing.T, s string) *simplejson.JSON {
+	js, err := simplejson.NewJSON([]byte(s))
+	assert.Equal(t, nil, err)
+	return js
+}
+
+func TestDiff(t *testing.T) {
+	want := mustJSON(t, `{"a":{"b":[1,2,3]},"name":"x","odd key":true}`)
+	got := mustJSON(t, `{"a":{"b":[1,5]},"name":"x","extra":null,"odd key":true}`)
+
+	assert.Equal(t, []string{
+		"$.a.b[1]: want 2, got 5",
+		"$.a.b[2]: missing, want 3",
+		"$.extra: unexpected null",
+	}, Diff(want, got))
+
+	assert.Equal(t, 0, len(Diff(want, mustJSON(t, `{"odd key":true,"name":"x","a":{"b":[1.0,2,3]}}`))))
+}
+
+func TestAssertions(t *testing.T) {
+	js := mustJSON(t, `{"a":{"b":[1,2,3],"c":"d"},"name":"x"}`)
+
+	r := &recorder{TB: t}
+	AssertEqual(r, js, js)
+	AssertSubset(r, mustJSON(t, `{"a":{"c":"d"}}`), js)
+	AssertPathEquals(r, js, []interface{}{"a", "b", 2}, 3)
+	AssertPathEquals(r, js, []interface{}{"a"}, mustJSON(t, `{"c":"d","b":[1,2,3]}`))
+	assert.Equal(t, 0, len(r.errors))
+
+	AssertSubset(r, mustJSON(t, `{"a":{"c":"e"}}`), js)
+	AssertPathEquals(r, js, []interface{}{"a", "missing"}, 1)
+	assert.Equal(t, []string{
+		"document is not a superset:\n$.a.c: want \"e\", got \"d\"",
+		"$.a.missing: missing, want 1",
+	}, r.errors)
+}
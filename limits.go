@@ -0,0 +1,185 @@
+package simplejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/brunotm/go-simplejson/scanner"
+)
+
+// Limits bounds the complexity of decoded documents, protecting services
+// decoding untrusted input from memory exhaustion. Zero values mean no limit.
+type Limits struct {
+	// MaxDepth is the maximum nesting of objects and arrays
+	MaxDepth int
+	// MaxNodes is the maximum number of values, containers included
+	MaxNodes int
+	// MaxArrayLen is the maximum number of elements of an array
+	MaxArrayLen int
+	// MaxObjectKeys is the maximum number of keys of an object
+	MaxObjectKeys int
+	// MaxStringLen is the maximum length in bytes of strings and keys
+	MaxStringLen int
+}
+
+// Errors wrapped by LimitError, one per limit
+var (
+	ErrMaxDepth      = errors.New("maximum depth exceeded")
+	ErrMaxNodes      = errors.New("maximum number of values exceeded")
+	ErrMaxArrayLen   = errors.New("maximum array length exceeded")
+	ErrMaxObjectKeys = errors.New("maximum number of object keys exceeded")
+	ErrMaxStringLen  = errors.New("maximum string length exceeded")
+)
+
+// LimitError reports the value at which a limit was exceeded
+type LimitError struct {
+	Path []interface{}
+	Err  error
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("simplejson: %s: %v", formatPath(e.Path), e.Err)
+}
+
+// Unwrap returns the error of the exceeded limit
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+// EnforceLimits rejects documents exceeding `l` with a *LimitError.
+// The input is checked before it is decoded, readers are buffered to do so,
+// which is best combined with MaxBytes.
+func EnforceLimits(l Limits) DecodeOption {
+	return func(c *decodeConfig) {
+		c.limits = &l
+	}
+}
+
+// decode decodes the next value of `dec` into `v`, enforcing limits
+// and rejecting invalid UTF-8 if configured to
+func (c *decodeConfig) decode(dec StreamDecoder, v *interface{}) error {
+	if c.limits == nil && c.utf8 != UTF8Reject {
+		return dec.Decode(v)
+	}
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	if err := c.check(raw); err != nil {
+		return err
+	}
+	d := codec.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+// check returns an error if `raw` breaks the limits or holds invalid UTF-8
+// that should be rejected
+func (c *decodeConfig) check(raw []byte) error {
+	if c.utf8 == UTF8Reject {
+		if err := checkUTF8(raw); err != nil {
+			return err
+		}
+	}
+	if c.limits != nil {
+		return c.limits.check(raw)
+	}
+	return nil
+}
+
+// check scans `raw` and returns a *LimitError if it exceeds the limits
+func (l *Limits) check(raw []byte) error {
+	return scanner.Scan(raw, &limitChecker{limits: l})
+}
+
+type limitChecker struct {
+	limits *Limits
+	nodes  int
+	stack  []limitFrame
+}
+
+type limitFrame struct {
+	array bool
+	count int
+	key   string
+}
+
+func (c *limitChecker) fail(err error) error {
+	path := make([]interface{}, len(c.stack))
+	for i, f := range c.stack {
+		if f.array {
+			path[i] = f.count - 1
+		} else {
+			path[i] = f.key
+		}
+	}
+	return &LimitError{Path: path, Err: err}
+}
+
+// node accounts for a new value
+func (c *limitChecker) node() error {
+	if n := len(c.stack); n > 0 && c.stack[n-1].array {
+		c.stack[n-1].count++
+		if c.limits.MaxArrayLen > 0 && c.stack[n-1].count > c.limits.MaxArrayLen {
+			return c.fail(ErrMaxArrayLen)
+		}
+	}
+	c.nodes++
+	if c.limits.MaxNodes > 0 && c.nodes > c.limits.MaxNodes {
+		return c.fail(ErrMaxNodes)
+	}
+	return nil
+}
+
+func (c *limitChecker) start(array bool) error {
+	if err := c.node(); err != nil {
+		return err
+	}
+	if c.limits.MaxDepth > 0 && len(c.stack) >= c.limits.MaxDepth {
+		return c.fail(ErrMaxDepth)
+	}
+	c.stack = append(c.stack, limitFrame{array: array})
+	return nil
+}
+
+func (c *limitChecker) end() error {
+	c.stack = c.stack[:len(c.stack)-1]
+	return nil
+}
+
+func (c *limitChecker) OnObjectStart() error { return c.start(false) }
+func (c *limitChecker) OnObjectEnd() error   { return c.end() }
+func (c *limitChecker) OnArrayStart() error  { return c.start(true) }
+func (c *limitChecker) OnArrayEnd() error    { return c.end() }
+
+func (c *limitChecker) OnKey(key string) error {
+	f := &c.stack[len(c.stack)-1]
+	f.count++
+	f.key = key
+	if c.limits.MaxObjectKeys > 0 && f.count > c.limits.MaxObjectKeys {
+		return c.fail(ErrMaxObjectKeys)
+	}
+	if c.limits.MaxStringLen > 0 && len(key) > c.limits.MaxStringLen {
+		return c.fail(ErrMaxStringLen)
+	}
+	return nil
+}
+
+func (c *limitChecker) OnValue(kind scanner.Kind, raw []byte) error {
+	if err := c.node(); err != nil {
+		return err
+	}
+	// the unquoted string is never longer than its quoted form
+	if max := c.limits.MaxStringLen; kind == scanner.String && max > 0 && len(raw)-2 > max {
+		s, err := scanner.Unquote(raw)
+		if err != nil {
+			return err
+		}
+		if len(s) > max {
+			return c.fail(ErrMaxStringLen)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package simplejson
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestLimits(t *testing.T) {
+	l := Limits{MaxDepth: 3, MaxNodes: 12, MaxArrayLen: 3, MaxObjectKeys: 3, MaxStringLen: 5}
+
+	js, err := NewJSONWith([]byte(`{"a":[1,2,{"b":"12345"}],"c":"é"}`), EnforceLimits(l))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "12345", js.Get("a", 2, "b").String())
+
+	cases := []struct {
+		body string
+		err  error
+		msg  string
+	}{
+		{`{"a":[[[1]]]}`, ErrMaxDepth, "simplejson: a[0][0]: maximum depth exceeded"},
+		{`[1,2,3,4]`, ErrMaxArrayLen, "simplejson: [3]: maximum array length exceeded"},
+		{`{"a":1,"b":2,"c":3,"d":4}`, ErrMaxObjectKeys, "simplejson: d: maximum number of object keys exceeded"},
+		{`{"a":"123456"}`, ErrMaxStringLen, "simplejson: a: maximum string length exceeded"},
+		{`{"abcdef":1}`, ErrMaxStringLen, "simplejson: abcdef: maximum string length exceeded"},
+		{`[[1,2,3],[1,2,3],[1,2,3],[1]]`, ErrMaxNodes, "simplejson: [2][2]: maximum number of values exceeded"},
+	}
+	for _, c := range cases {
+		_, err := NewJSONWith([]byte(c.body), EnforceLimits(l))
+		assert.Equal(t, true, errors.Is(err, c.err))
+		assert.Equal(t, c.msg, err.Error())
+
+		_, err = NewFromReaderWith(strings.NewReader(c.body), EnforceLimits(l))
+		assert.Equal(t, c.msg, err.Error())
+	}
+
+	dec := NewDecoder(strings.NewReader(`[1] [1,2,3,4]`), EnforceLimits(l))
+	_, err = dec.Decode()
+	assert.Equal(t, nil, err)
+	_, err = dec.Decode()
+	assert.Equal(t, true, errors.Is(err, ErrMaxArrayLen))
+
+	// escapes do not count towards the length of strings
+	_, err = NewJSONWith([]byte(`["\u0041BC"]`), EnforceLimits(Limits{MaxStringLen: 3}))
+	assert.Equal(t, nil, err)
+}
@@ -0,0 +1,157 @@
+package simplejson
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// maxLineSize is the largest line accepted by ProcessLines
+const maxLineSize = 64 << 20
+
+// lineJob is a single line of a JSON lines stream being processed
+type lineJob struct {
+	line   int
+	data   []byte
+	js     *JSON
+	err    error
+	parsed chan struct{}
+}
+
+// ProcessLines reads a stream of newline delimited JSON documents from `r`,
+// decoding them and calling `fn` for each one on a pool of `workers` goroutines.
+// Blank lines are skipped, `fn` is called concurrently and in no particular order.
+//
+// The first error returned by the decoder or by `fn` stops processing and is
+// returned annotated with its line number, as is the context error when `ctx`
+// is canceled. A non positive `workers` uses one worker per CPU.
+func ProcessLines(ctx context.Context, r io.Reader, workers int, fn func(*JSON) error) error {
+	return processLines(ctx, r, workers, fn, false)
+}
+
+// ProcessLinesOrdered is like ProcessLines, but while documents are decoded
+// concurrently, `fn` is called sequentially in the order of the input lines
+func ProcessLinesOrdered(ctx context.Context, r io.Reader, workers int, fn func(*JSON) error) error {
+	return processLines(ctx, r, workers, fn, true)
+}
+
+func processLines(ctx context.Context, r io.Reader, workers int, fn func(*JSON) error, ordered bool) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	jobs := make(chan *lineJob, workers)
+	// queue holds jobs in input order for the sequential ordered consumer
+	var queue chan *lineJob
+	if ordered {
+		queue = make(chan *lineJob, workers*2)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				job.js, job.err = NewJSON(job.data)
+				if job.err != nil {
+					job.err = fmt.Errorf("simplejson: line %d: %w", job.line, job.err)
+				}
+				if ordered {
+					close(job.parsed)
+					continue
+				}
+				if job.err == nil {
+					if err := fn(job.js); err != nil {
+						job.err = fmt.Errorf("simplejson: line %d: %w", job.line, err)
+					}
+				}
+				if job.err != nil {
+					fail(job.err)
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	if ordered {
+		go func() {
+			defer close(done)
+			for job := range queue {
+				<-job.parsed
+				if ctx.Err() != nil {
+					continue
+				}
+				err := job.err
+				if err == nil {
+					if err = fn(job.js); err != nil {
+						err = fmt.Errorf("simplejson: line %d: %w", job.line, err)
+					}
+				}
+				if err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	line := 0
+read:
+	for sc.Scan() {
+		line++
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		job := &lineJob{line: line, data: append([]byte(nil), sc.Bytes()...)}
+		if ordered {
+			job.parsed = make(chan struct{})
+			select {
+			case queue <- job:
+			case <-ctx.Done():
+				break read
+			}
+		}
+		select {
+		case jobs <- job:
+		case <-ctx.Done():
+			if ordered {
+				close(job.parsed)
+			}
+			break read
+		}
+	}
+	if err := sc.Err(); err != nil {
+		fail(err)
+	}
+
+	close(jobs)
+	wg.Wait()
+	if ordered {
+		close(queue)
+		<-done
+	}
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return firstErr
+}
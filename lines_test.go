@@ -0,0 +1,79 @@
+package simplejson
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/brunotm/go-simplejson/scanner"
+)
+
+func TestProcessLines(t *testing.T) {
+	input := "{\"n\":1}\n\n{\"n\":2}\r\n{\"n\":3}\n   \n{\"n\":4}\n"
+
+	var mu sync.Mutex
+	sum := 0
+	err := ProcessLines(context.Background(), strings.NewReader(input), 3, func(js *JSON) error {
+		mu.Lock()
+		sum += js.Get("n").Int()
+		mu.Unlock()
+		return nil
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 10, sum)
+
+	err = ProcessLines(context.Background(), strings.NewReader("{}\n{\"bad\"\n"), 2, func(js *JSON) error {
+		return nil
+	})
+	assert.NotEqual(t, nil, err)
+	assert.T(t, strings.HasPrefix(err.Error(), "simplejson: line 2:"), err)
+	assert.T(t, errors.Is(err, scanner.ErrUnexpectedEnd), err)
+
+	stop := errors.New("stop")
+	err = ProcessLines(context.Background(), strings.NewReader("{}\n"), 1, func(js *JSON) error {
+		return stop
+	})
+	assert.Equal(t, "simplejson: line 1: stop", err.Error())
+	assert.T(t, errors.Is(err, stop), err)
+}
+
+func TestProcessLinesOrdered(t *testing.T) {
+	var lines []string
+	for i := 0; i < 200; i++ {
+		lines = append(lines, `{"n":`+string(rune('0'+i%10))+`}`)
+	}
+
+	var got []int
+	err := ProcessLinesOrdered(context.Background(), strings.NewReader(strings.Join(lines, "\n")), 8, func(js *JSON) error {
+		got = append(got, js.Get("n").Int())
+		return nil
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 200, len(got))
+	for i, n := range got {
+		assert.Equal(t, i%10, n)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = ProcessLinesOrdered(context.Background(), strings.NewReader(strings.Join(lines, "\n")), 4, func(js *JSON) error {
+		calls++
+		if calls == 5 {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, "simplejson: line 5: stop", err.Error())
+	assert.T(t, errors.Is(err, stop), err)
+	assert.Equal(t, 5, calls)
+}
+
+func TestProcessLinesCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := ProcessLines(ctx, strings.NewReader("{}\n{}\n"), 1, func(*JSON) error { return nil })
+	assert.Equal(t, context.Canceled, err)
+}
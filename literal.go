@@ -0,0 +1,30 @@
+package simplejson
+
+import "fmt"
+
+// O returns a new object from alternating keys and values,
+// values may be documents returned by O and A to nest them:
+//
+//	js := O("id", 1, "tags", A("a", "b"), "owner", O("name", "x"))
+//
+// O panics if a key is not a string, is repeated or lacks a value,
+// or if a value cannot be encoded as JSON.
+func O(pairs ...interface{}) *JSON {
+	if len(pairs)%2 != 0 {
+		panic(fmt.Errorf("simplejson: O: key %v has no value", pairs[len(pairs)-1]))
+	}
+	b := NewObjectBuilder()
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			panic(fmt.Errorf("simplejson: O: key %v is not a string", pairs[i]))
+		}
+		b.Set(key, pairs[i+1])
+	}
+	return b.Build()
+}
+
+// A returns a new array of `vals`, see O
+func A(vals ...interface{}) *JSON {
+	return NewArrayBuilder().Add(vals...).Build()
+}
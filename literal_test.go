@@ -0,0 +1,25 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestLiterals(t *testing.T) {
+	js := O("id", 1, "tags", A("a", "b"), "owner", O("name", "x", "roles", A(O("admin", true))), "none", nil)
+	assert.Equal(t, `{"id":1,"none":null,"owner":{"name":"x","roles":[{"admin":true}]},"tags":["a","b"]}`,
+		string(mustEncode(t, js)))
+	assert.Equal(t, `[]`, string(mustEncode(t, A())))
+	assert.Equal(t, `{}`, string(mustEncode(t, O())))
+
+	assertPanicsWith := func(msg string, fn func()) {
+		defer func() {
+			assert.Equal(t, msg, recover().(error).Error())
+		}()
+		fn()
+	}
+	assertPanicsWith("simplejson: O: key b has no value", func() { O("a", 1, "b") })
+	assertPanicsWith("simplejson: O: key 1 is not a string", func() { O(1, "a") })
+	assertPanicsWith("simplejson: cannot build a: duplicate key", func() { O("a", 1, "a", 2) })
+}
@@ -0,0 +1,87 @@
+package simplejson
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ToMarkdownTable renders the array of objects `j` as a Markdown table with
+// one row per object. `columns` are dotted paths within the objects, by default
+// the keys of every object in sorted order. Missing fields and nulls are rendered
+// as empty cells, nested objects and arrays as JSON, pipes are escaped and
+// line breaks replaced by <br>.
+func (j *JSON) ToMarkdownTable(columns ...string) (string, error) {
+	j = j.orMissing()
+	a, ok := j.CheckArray()
+	if !ok {
+		return "", fmt.Errorf("simplejson: ToMarkdownTable requires an array of objects, got %s", jsonType(j.data))
+	}
+	for i, v := range a {
+		if _, ok := v.(map[string]interface{}); !ok {
+			return "", fmt.Errorf("simplejson: ToMarkdownTable element %d is %s, not an object", i, jsonType(v))
+		}
+	}
+	if len(columns) == 0 {
+		seen := make(map[string]bool)
+		for _, v := range a {
+			for key := range v.(map[string]interface{}) {
+				if !seen[key] {
+					seen[key] = true
+					columns = append(columns, key)
+				}
+			}
+		}
+		sort.Strings(columns)
+	}
+
+	var b strings.Builder
+	writeMarkdownRow(&b, columns)
+	b.WriteByte('|')
+	for range columns {
+		b.WriteString(" --- |")
+	}
+	b.WriteByte('\n')
+
+	cells := make([]string, len(columns))
+	for _, v := range a {
+		for i, col := range columns {
+			val, _ := lookup(v, dottedBranch(v, col))
+			cell, err := markdownCell(val)
+			if err != nil {
+				return "", err
+			}
+			cells[i] = cell
+		}
+		writeMarkdownRow(&b, cells)
+	}
+	return b.String(), nil
+}
+
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	b.WriteByte('|')
+	for _, cell := range cells {
+		b.WriteByte(' ')
+		b.WriteString(escapeMarkdown(cell))
+		b.WriteString(" |")
+	}
+	b.WriteByte('\n')
+}
+
+// markdownCell returns the text of a table cell holding `val`
+func markdownCell(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	}
+	b, err := newEncodeConfig([]EncodeOption{EscapeHTML(false)}).encode(val)
+	return string(b), err
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
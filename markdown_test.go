@@ -0,0 +1,29 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestToMarkdownTable(t *testing.T) {
+	js, err := NewJSON([]byte(`[
+		{"name":"a|b","owner":{"id":7},"tags":["x"],"n":1.5},
+		{"name":"multi\nline","n":null}
+	]`))
+	assert.Equal(t, nil, err)
+
+	s, err := js.ToMarkdownTable()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "| n | name | owner | tags |\n| --- | --- | --- | --- |\n| 1.5 | a\\|b | {\"id\":7} | [\"x\"] |\n|  | multi<br>line |  |  |\n", s)
+
+	s, err = js.ToMarkdownTable("owner.id", "name")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "| owner.id | name |\n| --- | --- |\n| 7 | a\\|b |\n|  | multi<br>line |\n", s)
+
+	_, err = js.Get(0).ToMarkdownTable()
+	assert.NotEqual(t, nil, err)
+	bad, _ := NewJSON([]byte(`[1]`))
+	_, err = bad.ToMarkdownTable()
+	assert.Equal(t, "simplejson: ToMarkdownTable element 0 is number, not an object", err.Error())
+}
@@ -0,0 +1,217 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MergeOption configures how Merge combines documents
+type MergeOption func(*mergeConfig)
+
+type mergeConfig struct {
+	arrayKey string
+	conflict ConflictFunc
+}
+
+// ConflictFunc resolves a conflict of Merge at `path`, where the document
+// holds `a` and the merged document `b`, returning the value to store.
+// `a` and `b` are copies the function may return or modify.
+type ConflictFunc func(path []interface{}, a, b *JSON) (interface{}, error)
+
+// ErrMergeDefault is returned by a ConflictFunc to resolve the conflict
+// as Merge does without one
+var ErrMergeDefault = errors.New("simplejson: default merge")
+
+// MergeArraysByKey makes Merge match array elements by the value of their
+// `key` field instead of their position. Matched objects are merged together,
+// unmatched ones are appended in order.
+func MergeArraysByKey(key string) MergeOption {
+	return func(c *mergeConfig) {
+		c.arrayKey = key
+	}
+}
+
+// WithConflictFn makes Merge call `fn` for every path where both documents
+// hold different values and at least one is not an object, such as
+// arrays, instead of merging or replacing them:
+//
+//	js.Merge(other, WithConflictFn(func(path []interface{}, a, b *JSON) (interface{}, error) {
+//		if len(path) == 1 && path[0] == "tags" {
+//			return append(a.Array(), b.Array()...), nil
+//		}
+//		return nil, ErrMergeDefault
+//	}))
+//
+// Paths are relative to the document merged into.
+// Merge fails with the errors of `fn`, leaving the document unchanged.
+func WithConflictFn(fn ConflictFunc) MergeOption {
+	return func(c *mergeConfig) {
+		c.conflict = fn
+	}
+}
+
+// Merge deeply merges `other` into the document.
+//
+// Objects are merged key by key, arrays element by element, by position
+// unless configured otherwise, with the extra elements of `other` appended.
+// Any other value of `other` replaces the value of the document.
+// Values taken from `other` are copied.
+func (j *JSON) Merge(other *JSON, opts ...MergeOption) error {
+	j = j.orMissing()
+	if j.IsFrozen() {
+		return ErrFrozen
+	}
+	if other == nil {
+		return nil
+	}
+	c := &mergeConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	merged, err := c.merge(j.data, other.data, nil)
+	if err != nil {
+		return err
+	}
+	return j.replace(merged)
+}
+
+// merge returns the result of merging `src` into `dst`, found at `path`,
+// the containers of `dst` are not modified
+func (c *mergeConfig) merge(dst, src interface{}, path []interface{}) (interface{}, error) {
+	if c.conflict != nil && !deepEqual(dst, src) {
+		_, dstMap := dst.(map[string]interface{})
+		_, srcMap := src.(map[string]interface{})
+		if !dstMap || !srcMap {
+			return c.resolve(dst, src, path)
+		}
+	}
+	return c.mergeValues(dst, src, path)
+}
+
+// resolve returns the value chosen by the conflict function
+// for the values `dst` and `src` at `path`
+func (c *mergeConfig) resolve(dst, src interface{}, path []interface{}) (interface{}, error) {
+	at := append([]interface{}(nil), path...)
+	val, err := c.conflict(at, &JSON{data: deepCopy(dst)}, &JSON{data: deepCopy(src)})
+	if err == ErrMergeDefault {
+		return c.mergeValues(dst, src, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("simplejson: merge conflict at %s: %w", formatPath(path), err)
+	}
+	if v, ok := val.(*JSON); ok {
+		val = v.Interface()
+	}
+	val, err = convertValue(val)
+	if err != nil {
+		ce := err.(*ConvertError)
+		ce.Path = append(at, ce.Path...)
+		return nil, ce
+	}
+	return val, nil
+}
+
+// mergeValues is merge without resolving conflicts at `path`
+func (c *mergeConfig) mergeValues(dst, src interface{}, path []interface{}) (interface{}, error) {
+	switch s := src.(type) {
+	case map[string]interface{}:
+		d, ok := dst.(map[string]interface{})
+		if !ok {
+			break
+		}
+		merged := make(map[string]interface{}, len(d)+len(s))
+		for key, val := range d {
+			merged[key] = val
+		}
+		for key, val := range s {
+			if existing, ok := d[key]; ok {
+				v, err := c.merge(existing, val, append(path, key))
+				if err != nil {
+					return nil, err
+				}
+				merged[key] = v
+				continue
+			}
+			merged[key] = deepCopy(val)
+		}
+		return merged, nil
+
+	case []interface{}:
+		d, ok := dst.([]interface{})
+		if !ok {
+			break
+		}
+		if c.arrayKey != "" {
+			return c.mergeByKey(d, s, path)
+		}
+		merged := append([]interface{}(nil), d...)
+		for i, val := range s {
+			if i < len(d) {
+				v, err := c.merge(d[i], val, append(path, i))
+				if err != nil {
+					return nil, err
+				}
+				merged[i] = v
+				continue
+			}
+			merged = append(merged, deepCopy(val))
+		}
+		return merged, nil
+	}
+	return deepCopy(src), nil
+}
+
+func (c *mergeConfig) mergeByKey(dst, src []interface{}, path []interface{}) ([]interface{}, error) {
+	merged := append([]interface{}(nil), dst...)
+	index := make(map[string]int, len(dst))
+	for i, val := range dst {
+		if id, ok := c.elementID(val); ok {
+			if _, dup := index[id]; !dup {
+				index[id] = i
+			}
+		}
+	}
+	for _, val := range src {
+		if id, ok := c.elementID(val); ok {
+			if i, found := index[id]; found {
+				v, err := c.merge(merged[i], val, append(path, i))
+				if err != nil {
+					return nil, err
+				}
+				merged[i] = v
+				continue
+			}
+			index[id] = len(merged)
+		}
+		merged = append(merged, deepCopy(val))
+	}
+	return merged, nil
+}
+
+// elementID returns a string identifying an array element by its key field
+func (c *mergeConfig) elementID(val interface{}) (string, bool) {
+	id, ok := getKey(val, c.arrayKey)
+	if !ok {
+		return "", false
+	}
+	return valueID(id)
+}
+
+// valueID returns a string identifying a scalar value,
+// numbers of different representations but equal value share it
+func valueID(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		return "s" + val, true
+	case bool, nil:
+		return fmt.Sprint(val), true
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(val)
+		return "c" + string(b), err == nil
+	}
+	if r, ok := numberRat(v); ok {
+		return "n" + r.RatString(), true
+	}
+	return "", false
+}
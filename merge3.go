@@ -0,0 +1,100 @@
+package simplejson
+
+import (
+	"errors"
+	"sort"
+)
+
+// Conflict is a value modified differently by both sides of a three way merge
+type Conflict struct {
+	Path []interface{}
+	// Base, Ours and Theirs hold the conflicting values,
+	// nil with the matching Has flag unset when the value is absent
+	Base, Ours, Theirs          interface{}
+	HasBase, HasOurs, HasTheirs bool
+}
+
+// missing marks an absent value during merges
+type missing struct{}
+
+// Merge3 reconciles two documents derived from a common `base`.
+//
+// Changes made by only one side are applied, objects are merged key by key
+// while arrays and scalars are merged as a whole. Values changed differently
+// by both sides are reported as conflicts, and resolved in favor of `ours`.
+// The returned document shares no data with the arguments.
+func Merge3(base, ours, theirs *JSON) (*JSON, []Conflict, error) {
+	if base == nil || ours == nil || theirs == nil {
+		return nil, nil, errors.New("simplejson: Merge3 requires three documents")
+	}
+	var conflicts []Conflict
+	merged := merge3(nil, base.data, ours.data, theirs.data, &conflicts)
+	j := &JSON{data: deepCopy(merged)}
+	j.document()
+	return j, conflicts, nil
+}
+
+func merge3(path []interface{}, base, ours, theirs interface{}, conflicts *[]Conflict) interface{} {
+	switch {
+	case deepEqual(ours, theirs):
+		return ours
+	case deepEqual(base, ours):
+		return theirs
+	case deepEqual(base, theirs):
+		return ours
+	}
+
+	om, oursMap := ours.(map[string]interface{})
+	tm, theirsMap := theirs.(map[string]interface{})
+	bm, baseMap := base.(map[string]interface{})
+	if _, absent := base.(missing); absent {
+		bm, baseMap = map[string]interface{}{}, true
+	}
+	if oursMap && theirsMap && baseMap {
+		merged := make(map[string]interface{}, len(om))
+		for _, key := range unionKeys(bm, om, tm) {
+			val := merge3(append(path[:len(path):len(path)], key), member(bm, key), member(om, key), member(tm, key), conflicts)
+			if _, absent := val.(missing); !absent {
+				merged[key] = val
+			}
+		}
+		return merged
+	}
+
+	c := Conflict{Path: path}
+	c.Base, c.HasBase = present(base)
+	c.Ours, c.HasOurs = present(ours)
+	c.Theirs, c.HasTheirs = present(theirs)
+	*conflicts = append(*conflicts, c)
+	return ours
+}
+
+func member(m map[string]interface{}, key string) interface{} {
+	if val, ok := m[key]; ok {
+		return val
+	}
+	return missing{}
+}
+
+func present(v interface{}) (interface{}, bool) {
+	if _, absent := v.(missing); absent {
+		return nil, false
+	}
+	return v, true
+}
+
+// unionKeys returns the sorted keys found in any of the maps
+func unionKeys(maps ...map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	keys := []string{}
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,45 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestMerge3(t *testing.T) {
+	base, _ := NewJSON([]byte(`{"a":1,"b":{"c":1,"d":1},"e":[1],"gone":true,"f":"x"}`))
+	ours, _ := NewJSON([]byte(`{"a":2,"b":{"c":2,"d":1},"e":[1,2],"f":"y","new":{"x":1}}`))
+	theirs, _ := NewJSON([]byte(`{"a":1,"b":{"c":1,"d":3},"e":[1,3],"gone":true,"f":"z","new":{"y":1}}`))
+
+	merged, conflicts, err := Merge3(base, ours, theirs)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"a":2,"b":{"c":2,"d":3},"e":[1,2],"f":"y","new":{"x":1,"y":1}}`, string(mustEncode(t, merged)))
+
+	assert.Equal(t, 2, len(conflicts))
+	assert.Equal(t, []interface{}{"e"}, conflicts[0].Path)
+	assert.Equal(t, []interface{}{"f"}, conflicts[1].Path)
+	assert.Equal(t, "z", conflicts[1].Theirs)
+	assert.Equal(t, true, conflicts[1].HasBase)
+
+	// deleted on one side, modified on the other
+	ours, _ = NewJSON([]byte(`{"a":1}`))
+	theirs, _ = NewJSON([]byte(`{"a":1,"gone":false}`))
+	merged, conflicts, err = Merge3(base, ours, theirs)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(conflicts))
+	assert.Equal(t, false, conflicts[0].HasOurs)
+	_, ok := merged.CheckGet("gone")
+	assert.Equal(t, false, ok)
+
+	_, _, err = Merge3(nil, ours, theirs)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestDeepEqual(t *testing.T) {
+	a, _ := NewJSON([]byte(`{"n":[1.0,{"x":"y"}],"b":true}`))
+	assert.Equal(t, true, deepEqual(a.data, map[string]interface{}{
+		"b": true, "n": []interface{}{1, map[string]interface{}{"x": "y"}}}))
+	assert.Equal(t, false, deepEqual(a.data, map[string]interface{}{"b": true}))
+	assert.Equal(t, true, deepEqual(uint64(18446744073709551615), json.Number("18446744073709551615")))
+}
@@ -0,0 +1,67 @@
+package simplejson
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestMerge(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"a":{"b":1,"c":[1,{"d":1}]},"e":"x"}`))
+	other, _ := NewJSON([]byte(`{"a":{"c":[2,{"f":1},3],"g":true},"e":{"h":1}}`))
+
+	assert.Equal(t, nil, js.Merge(other))
+	assert.Equal(t, `{"a":{"b":1,"c":[2,{"d":1,"f":1},3],"g":true},"e":{"h":1}}`, string(mustEncode(t, js)))
+
+	other.Get("a").Set("g", false)
+	assert.Equal(t, true, js.Get("a", "g").Bool())
+}
+
+func TestMergeArraysByKey(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"resources":[{"id":"a","v":1},{"id":2,"v":1},{"v":"anon"}]}`))
+	other, _ := NewJSON([]byte(`{"resources":[{"id":2.0,"w":2},{"id":"c"},{"id":"a","v":3},{"v":"anon"}]}`))
+
+	assert.Equal(t, nil, js.Merge(other, MergeArraysByKey("id")))
+	assert.Equal(t, `{"resources":[{"id":"a","v":3},{"id":2.0,"v":1,"w":2},{"v":"anon"},{"id":"c"},{"v":"anon"}]}`,
+		string(mustEncode(t, js)))
+
+	js.Freeze()
+	assert.Equal(t, ErrFrozen, js.Merge(other))
+}
+
+func TestMergeConflictFn(t *testing.T) {
+	js := mustJSON(`{"tags":["a"],"limits":[1,2],"cfg":{"mode":"x","n":1,"same":[1]},"keep":1}`)
+	other := mustJSON(`{"tags":["b"],"limits":[3],"cfg":{"mode":{"y":1},"n":2,"same":[1],"new":1},"keep":2}`)
+
+	var paths []string
+	err := js.Merge(other, WithConflictFn(func(path []interface{}, a, b *JSON) (interface{}, error) {
+		paths = append(paths, formatPath(path))
+		switch formatPath(path) {
+		case "tags":
+			return append(a.Array(), b.Array()...), nil
+		case "keep":
+			return a, nil
+		case "cfg.n":
+			return a.Int() + b.Int(), nil
+		}
+		return nil, ErrMergeDefault
+	}))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"cfg":{"mode":{"y":1},"n":3,"new":1,"same":[1]},"keep":1,"limits":[3,2],"tags":["a","b"]}`,
+		string(mustEncode(t, js)))
+	sort.Strings(paths)
+	assert.Equal(t, []string{"cfg.mode", "cfg.n", "keep", "limits", "limits[0]", "tags"}, paths)
+
+	// elements of arrays merged by default conflict in turn
+	js = mustJSON(`{"a":[{"b":1}]}`)
+	err = js.Merge(mustJSON(`{"a":[{"b":2}],"c":1}`), WithConflictFn(func(path []interface{}, a, b *JSON) (interface{}, error) {
+		if len(path) == 3 {
+			return nil, errors.New("refused")
+		}
+		return nil, ErrMergeDefault
+	}))
+	assert.Equal(t, `simplejson: merge conflict at a[0].b: refused`, err.Error())
+	assert.Equal(t, `{"a":[{"b":1}]}`, string(mustEncode(t, js)))
+}
@@ -0,0 +1,83 @@
+package simplejson
+
+import (
+	"io"
+	"time"
+)
+
+// Metrics receives measurements of parsing and encoding,
+// to be exported as counters and histograms
+type Metrics interface {
+	// Decoded is called after NewJSON or NewFromReader parsed `bytes` bytes in `took`,
+	// `nodes` is the number of values of the document, 0 on errors
+	Decoded(bytes int64, took time.Duration, nodes int, err error)
+	// Encoded is called after a document was encoded to `bytes` bytes in `took`
+	Encoded(bytes int, took time.Duration, err error)
+}
+
+var metrics Metrics
+
+// SetMetrics sets the package level Metrics, nil disables them.
+// Like SetCodec, it is meant to be called once during program initialization.
+func SetMetrics(m Metrics) {
+	metrics = m
+}
+
+// decodeMeasure times a decoding and counts the bytes it reads
+type decodeMeasure struct {
+	start time.Time
+	r     io.Reader
+	n     int64
+}
+
+// startDecode returns a measure of a decoding of `size` bytes,
+// nil if there are no metrics to report to
+func startDecode(size int) *decodeMeasure {
+	if metrics == nil {
+		return nil
+	}
+	return &decodeMeasure{start: time.Now(), n: int64(size)}
+}
+
+// reader returns `r`, counting the bytes read from it
+func (m *decodeMeasure) reader(r io.Reader) io.Reader {
+	if m == nil {
+		return r
+	}
+	m.r = r
+	return m
+}
+
+func (m *decodeMeasure) Read(p []byte) (int, error) {
+	n, err := m.r.Read(p)
+	m.n += int64(n)
+	return n, err
+}
+
+// done reports the decoding of `j`
+func (m *decodeMeasure) done(j *JSON, err error) {
+	if m == nil {
+		return
+	}
+	var nodes int
+	if err == nil {
+		nodes = countNodes(j.data)
+	}
+	metrics.Decoded(m.n, time.Since(m.start), nodes, err)
+}
+
+// countNodes returns the number of values in `data`
+func countNodes(data interface{}) int {
+	n := 1
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for _, val := range v {
+			n += countNodes(val)
+		}
+	case []interface{}:
+		for _, val := range v {
+			n += countNodes(val)
+		}
+	}
+	return n
+}
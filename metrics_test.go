@@ -0,0 +1,54 @@
+package simplejson
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+type recordingMetrics struct {
+	decoded []int64
+	nodes   []int
+	errs    int
+	encoded []int
+}
+
+func (m *recordingMetrics) Decoded(bytes int64, took time.Duration, nodes int, err error) {
+	m.decoded = append(m.decoded, bytes)
+	m.nodes = append(m.nodes, nodes)
+	if err != nil {
+		m.errs++
+	}
+}
+
+func (m *recordingMetrics) Encoded(bytes int, took time.Duration, err error) {
+	m.encoded = append(m.encoded, bytes)
+}
+
+func TestMetrics(t *testing.T) {
+	m := &recordingMetrics{}
+	SetMetrics(m)
+	defer SetMetrics(nil)
+
+	js, err := NewJSON([]byte(`{"a":[1,2],"b":null}`))
+	assert.Equal(t, nil, err)
+	_, err = NewFromReader(strings.NewReader(`[true, "x"]`))
+	assert.Equal(t, nil, err)
+	_, err = NewJSON([]byte(`{`))
+	assert.NotEqual(t, nil, err)
+
+	assert.Equal(t, []int64{20, 11, 1}, m.decoded)
+	assert.Equal(t, []int{5, 3, 0}, m.nodes)
+	assert.Equal(t, 1, m.errs)
+
+	b, _ := js.Encode()
+	js.EncodePretty()
+	assert.Equal(t, 2, len(m.encoded))
+	assert.Equal(t, len(b), m.encoded[0])
+
+	SetMetrics(nil)
+	js.Encode()
+	assert.Equal(t, 2, len(m.encoded))
+}
@@ -0,0 +1,52 @@
+package simplejson
+
+// TrackParents makes the document remember the location of every view
+// obtained from it through Get and friends as a chain of views, one per step
+// of their branch, so Parent needs no further lookup. Get chains then
+// allocate a view per step. Views are located without it too, by walking
+// the branch they were obtained with again when needed.
+func (j *JSON) TrackParents() {
+	if j == nil {
+		return
+	}
+	j.document().track = true
+}
+
+// Parent returns the object or array holding `j` within its document,
+// or nil for the root or when `j` cannot be located, see TrackParents
+func (j *JSON) Parent() *JSON {
+	if j == nil || j.doc == nil || j == j.doc.root {
+		return nil
+	}
+	if j.parent != nil {
+		return j.parent
+	}
+	c := *j
+	if !j.doc.locate(&c) {
+		return nil
+	}
+	return c.parent
+}
+
+// Root returns the root of the document `j` belongs to
+func (j *JSON) Root() *JSON {
+	if j == nil || j.doc == nil {
+		return j
+	}
+	return j.doc.root
+}
+
+// Path returns the keys and indexes leading from the root of the document
+// to `j`, or nil when `j` cannot be located. Views obtained through Get and
+// the other lookups can always be located, other views only if they hold
+// a non empty object or array.
+func (j *JSON) Path() []interface{} {
+	if j == nil {
+		return nil
+	}
+	path, ok := j.location()
+	if !ok {
+		return nil
+	}
+	return path
+}
@@ -0,0 +1,38 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestNavigation(t *testing.T) {
+	js := mustJSON(`{"a":{"b":[{"c":1},2]}}`)
+
+	// containers are located without tracking
+	c := js.Get("a", "b", 0)
+	assert.Equal(t, []interface{}{"a", "b", 0}, c.Path())
+	assert.Equal(t, []interface{}{"a", "b"}, c.Parent().Path())
+	assert.Equal(t, []interface{}{"a"}, c.Parent().Parent().Path())
+	assert.Equal(t, js, c.Root())
+	assert.Equal(t, (*JSON)(nil), js.Parent())
+	assert.Equal(t, []interface{}{}, js.Path())
+
+	// scalars are located from the lookup which returned them
+	leaf := js.Get("a", "b", 1)
+	assert.Equal(t, []interface{}{"a", "b", 1}, leaf.Path())
+	assert.Equal(t, 2, len(leaf.Parent().Array()))
+	assert.Equal(t, []interface{}{"a", "b", 1}, js.Get("a").Get("b", -1).Path())
+	assert.Equal(t, []interface{}(nil), js.wrap(2).Path())
+
+	js.TrackParents()
+	leaf = js.Get("a", "b", -1)
+	assert.Equal(t, []interface{}{"a", "b", 1}, leaf.Path())
+	assert.Equal(t, 2, len(leaf.Parent().Array()))
+	assert.Equal(t, []interface{}{"a", "b", 0, "c"}, js.Get("a").Get("b", 0).Get("c").Path())
+	assert.Equal(t, "a.b[0].c", formatPath(js.Get("a", "b", 0, "c").Path()))
+
+	var missing *JSON
+	assert.Equal(t, (*JSON)(nil), missing.Root())
+	assert.Equal(t, []interface{}(nil), missing.Path())
+}
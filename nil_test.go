@@ -0,0 +1,173 @@
+package simplejson
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+var (
+	writerType = reflect.TypeOf((*io.Writer)(nil)).Elem()
+	readerType = reflect.TypeOf((*io.Reader)(nil)).Elem()
+	jsonType_  = reflect.TypeOf((*JSON)(nil))
+	bytesType  = reflect.TypeOf([]byte(nil))
+)
+
+// nilSamples are the arguments of the methods which return early
+// with the values of sampleValue
+var nilSamples = map[string][]interface{}{
+	"Bind":            {&struct{ A []int }{}},
+	"Search":          {"a[0]"},
+	"Transform":       {".a[0]"},
+	"Eval":            {"a[0] + 1"},
+	"EncryptPaths":    {make([]byte, 32), "a"},
+	"DecryptPaths":    {make([]byte, 32), "a"},
+	"UnmarshalBinary": {mustMarshalBinary(mustJSON(`{"a":[1]}`))},
+}
+
+func mustMarshalBinary(js *JSON) []byte {
+	b, err := js.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// TestNilReceiver calls every method on a nil *JSON, once with zero values
+// and once with representative arguments
+func TestNilReceiver(t *testing.T) {
+	var js *JSON
+	typ := reflect.TypeOf(js)
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+		for _, sample := range []bool{false, true} {
+			args := []reflect.Value{reflect.ValueOf(js)}
+			if vals, ok := nilSamples[m.Name]; ok && sample {
+				for _, v := range vals {
+					args = append(args, reflect.ValueOf(v))
+				}
+				callNil(t, m, args, sample)
+				continue
+			}
+			for k := 1; k < m.Type.NumIn(); k++ {
+				in := m.Type.In(k)
+				if m.Type.IsVariadic() && k == m.Type.NumIn()-1 {
+					if sample {
+						args = append(args, sampleValue(in.Elem()))
+					}
+					break
+				}
+				switch {
+				case in == writerType:
+					// writing to a nil writer panics whatever the receiver
+					args = append(args, reflect.ValueOf(io.Discard))
+				case sample:
+					args = append(args, sampleValue(in))
+				default:
+					args = append(args, reflect.Zero(in))
+				}
+			}
+			callNil(t, m, args, sample)
+		}
+	}
+
+	SetArgsPolicy(ErrorOnExtraArgs)
+	defer SetArgsPolicy(PanicOnExtraArgs)
+	assert.Equal(t, "a", js.String("a", "b"))
+	assert.Equal(t, nil, js.Err())
+}
+
+func callNil(t *testing.T, m reflect.Method, args []reflect.Value, sample bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("%s panics on a nil receiver (sample arguments: %v): %v", m.Name, sample, r)
+		}
+	}()
+	m.Func.Call(args)
+}
+
+// sampleValue returns a representative value of type `typ`
+func sampleValue(typ reflect.Type) reflect.Value {
+	switch typ {
+	case writerType:
+		return reflect.ValueOf(io.Discard)
+	case readerType:
+		return reflect.ValueOf(strings.NewReader(`{"a":[1,"x"]}`))
+	case jsonType_:
+		return reflect.ValueOf(mustJSON(`{"a":[1,"x"],"b":{"c":true}}`))
+	case bytesType:
+		return reflect.ValueOf([]byte(`{"a":[1,"x"]}`))
+	}
+
+	v := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.String:
+		v.SetString("a")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Interface:
+		if reflect.TypeOf("").Implements(typ) {
+			v.Set(reflect.ValueOf("a"))
+		}
+	case reflect.Slice:
+		v = reflect.Append(v, sampleValue(typ.Elem()))
+	case reflect.Map:
+		v = reflect.MakeMap(typ)
+		v.SetMapIndex(sampleValue(typ.Key()), sampleValue(typ.Elem()))
+	case reflect.Ptr:
+		v = reflect.New(typ.Elem())
+	case reflect.Func:
+		v = reflect.MakeFunc(typ, func(args []reflect.Value) []reflect.Value {
+			out := make([]reflect.Value, typ.NumOut())
+			for i := range out {
+				out[i] = reflect.Zero(typ.Out(i))
+			}
+			return out
+		})
+	}
+	return v
+}
+
+func TestNilReceiverChains(t *testing.T) {
+	var js *JSON
+	_, ok := js.CheckGet("a")
+	assert.Equal(t, false, ok)
+	assert.Equal(t, "def", js.Get("a").Get("b").String("def"))
+	assert.Equal(t, 0, js.Get("a", 1).Int())
+	assert.Equal(t, []string(nil), js.Keys())
+	assert.Equal(t, nil, js.Interface())
+
+	b, err := js.Encode()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "null", string(b))
+	assert.Equal(t, "<nil>", fmt.Sprint(js.Interface()))
+
+	js.Set("a", 1)
+	js.Get("a").Append(1)
+	assert.Equal(t, false, js.IsFrozen())
+	assert.NotEqual(t, nil, js.UnmarshalJSON([]byte(`{}`)))
+
+	tx := js.Begin()
+	tx.Set("a", 1)
+	assert.Equal(t, nil, tx.Commit())
+
+	// missing values from a stored CheckGet result
+	doc := mustJSON(`{"a":{"b":"c"}}`)
+	missing, _ := doc.CheckGet("x")
+	assert.Equal(t, "", missing.Get("b").String())
+	assert.Equal(t, "fallback", missing.Or("fallback").String())
+	assert.Equal(t, nil, doc.Graft([]interface{}{"d"}, missing, true))
+	d, ok := doc.CheckGet("d")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, nil, d.Interface())
+}
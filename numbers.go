@@ -0,0 +1,68 @@
+package simplejson
+
+import "encoding/json"
+
+// NumberPolicy is the representation NormalizeNumbers converts numbers to
+type NumberPolicy int
+
+// Number normalization policies
+const (
+	// WholeNumbersAsInt64 converts numbers with an integer value fitting
+	// an int64, such as 2.0 or 1e3, to int64, leaving other numbers untouched
+	WholeNumbersAsInt64 NumberPolicy = iota
+	// NumbersAsJSONNumber converts every number to a json.Number, written as
+	// by the NormalizedNumbers encoding option
+	NumbersAsJSONNumber
+	// NumbersAsStrings is like NumbersAsJSONNumber, converting numbers to strings
+	NumbersAsStrings
+)
+
+// NormalizeNumbers converts every number of the document to the
+// representation chosen by `policy`, so equal values of a field are
+// represented the same way whatever the producer of the document
+func (j *JSON) NormalizeNumbers(policy NumberPolicy) {
+	if j == nil {
+		return
+	}
+	j.checkWritable()
+	c := newEncodeConfig([]EncodeOption{NormalizedNumbers()})
+	j.replace(normalizeNumbers(j.data, policy, c))
+}
+
+// normalizeNumbers returns a copy of `data` with numbers converted
+// according to `policy`
+func normalizeNumbers(data interface{}, policy NumberPolicy, c *encodeConfig) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[key] = normalizeNumbers(val, policy, c)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, val := range v {
+			a[i] = normalizeNumbers(val, policy, c)
+		}
+		return a
+	}
+	if !isNumber(data) {
+		return data
+	}
+
+	if policy == WholeNumbersAsInt64 {
+		if r, ok := numberRat(data); ok && r.IsInt() && r.Num().IsInt64() {
+			return r.Num().Int64()
+		}
+		return data
+	}
+	b, err := c.encode(data)
+	if err != nil {
+		// not a finite number
+		return data
+	}
+	if policy == NumbersAsStrings {
+		return string(b)
+	}
+	return json.Number(b)
+}
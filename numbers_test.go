@@ -0,0 +1,40 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestNormalizeNumbers(t *testing.T) {
+	body := `{"a":1.0,"b":[2,1e3,2.5,-0.0],"c":"1.0","d":123456789012345678901234567890}`
+
+	js := mustJSON(body)
+	js.Set("e", 3.0)
+	js.Set("f", float32(0.5))
+	js.NormalizeNumbers(WholeNumbersAsInt64)
+	assert.Equal(t, int64(1), js.Get("a").Interface())
+	assert.Equal(t, []interface{}{int64(2), int64(1000), json.Number("2.5"), int64(0)}, js.Get("b").Interface())
+	assert.Equal(t, "1.0", js.Get("c").Interface())
+	assert.Equal(t, json.Number("123456789012345678901234567890"), js.Get("d").Interface())
+	assert.Equal(t, int64(3), js.Get("e").Interface())
+	assert.Equal(t, float32(0.5), js.Get("f").Interface())
+
+	js = mustJSON(body)
+	js.Set("e", 3.0)
+	js.NormalizeNumbers(NumbersAsJSONNumber)
+	b, _ := js.Encode()
+	assert.Equal(t, `{"a":1,"b":[2,1000,2.5,0],"c":"1.0","d":123456789012345678901234567890,"e":3}`, string(b))
+	assert.Equal(t, json.Number("3"), js.Get("e").Interface())
+
+	js = mustJSON(body)
+	js.Get("b").NormalizeNumbers(NumbersAsStrings)
+	b, _ = js.Encode()
+	assert.Equal(t, `{"a":1.0,"b":["2","1000","2.5","0"],"c":"1.0","d":123456789012345678901234567890}`, string(b))
+
+	snap := js.Snapshot()
+	js.NormalizeNumbers(NumbersAsStrings)
+	assert.Equal(t, "1", js.Get("a").Interface())
+	assert.Equal(t, json.Number("1.0"), snap.Get("a").Interface())
+}
@@ -0,0 +1,79 @@
+package simplejson
+
+// Object returns the object at `branch`, creating it and any missing
+// intermediate object if needed, so that values can be set on it directly:
+//
+//	js.Object("server", "tls").Set("enabled", true)
+//
+// Values of other types found along the branch are replaced by objects.
+// Array indexes must address existing elements, when one does not the
+// returned `JSON` holds no value and modifications to it are ignored.
+func (j *JSON) Object(branch ...interface{}) *JSON {
+	return j.container(branch, func() interface{} {
+		return map[string]interface{}{}
+	})
+}
+
+// ArrayAt is like Object, but the value at the end of `branch` is an array,
+// to which elements can be added with Append
+func (j *JSON) ArrayAt(branch ...interface{}) *JSON {
+	return j.container(branch, func() interface{} {
+		return []interface{}{}
+	})
+}
+
+// container returns the value at `branch`, replacing it by `empty()` if it
+// is not of the same type and creating intermediate objects for missing keys
+func (j *JSON) container(branch []interface{}, empty func() interface{}) *JSON {
+	j = j.orMissing()
+	j.checkWritable()
+	newObject := func() interface{} { return map[string]interface{}{} }
+	fits := func(data interface{}, last bool) bool {
+		switch data.(type) {
+		case map[string]interface{}:
+			_, want := empty().(map[string]interface{})
+			return !last || want
+		case []interface{}:
+			_, want := empty().([]interface{})
+			return !last || want
+		}
+		return false
+	}
+
+	curr := j
+	for i, p := range branch {
+		last := i == len(branch)-1
+		create := newObject
+		if last {
+			create = empty
+		}
+		switch k := p.(type) {
+		case string:
+			if _, ok := curr.data.(map[string]interface{}); !ok {
+				curr.replace(newObject())
+			}
+			next, ok := curr.walk([]interface{}{k})
+			if !ok || !fits(next.data, last) {
+				curr.Set(k, create())
+				next, _ = curr.walk([]interface{}{k})
+			}
+			curr = next
+		case int:
+			next, ok := curr.walk([]interface{}{k})
+			if !ok {
+				return j.wrap(nil)
+			}
+			if !fits(next.data, last) {
+				curr.setChild(k, create())
+				next, _ = curr.walk([]interface{}{k})
+			}
+			curr = next
+		default:
+			return j.wrap(nil)
+		}
+	}
+	if len(branch) == 0 && !fits(j.data, true) {
+		j.replace(empty())
+	}
+	return curr
+}
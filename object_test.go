@@ -0,0 +1,45 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestObject(t *testing.T) {
+	js := New()
+	js.Object("server", "tls").Set("enabled", true)
+	js.Object("server").Set("port", 80)
+	assert.Equal(t, `{"server":{"port":80,"tls":{"enabled":true}}}`, string(mustEncode(t, js)))
+
+	js.Set("name", "x")
+	js.Object("name", "first").Set("value", "y")
+	assert.Equal(t, "y", js.Get("name", "first", "value").String())
+
+	js.ArrayAt("server", "hosts").Append("a", "b")
+	js.ArrayAt("server", "hosts").Append("c")
+	assert.Equal(t, []interface{}{"a", "b", "c"}, js.Get("server", "hosts").Array())
+
+	js.ArrayAt("list").Append(map[string]interface{}{})
+	js.Object("list", 0, "nested").Set("ok", true)
+	js.Object("list", -1).Set("id", 1)
+	assert.Equal(t, `[{"id":1,"nested":{"ok":true}}]`, string(mustEncode(t, js.Get("list"))))
+
+	missing := js.Object("list", 5)
+	assert.Equal(t, nil, missing.Interface())
+	missing.Set("id", 2)
+	_, ok := js.CheckGet("list", 5)
+	assert.Equal(t, false, ok)
+
+	arr := &JSON{}
+	arr.ArrayAt().Append(1)
+	assert.Equal(t, []interface{}{1}, arr.Interface())
+
+	var changes []Change
+	js.OnChange(nil, func(c Change) { changes = append(changes, c) })
+	js.ArrayAt("server", "hosts").Append("d")
+	assert.Equal(t, []interface{}{"server", "hosts", 3}, changes[0].Path)
+
+	js.Freeze()
+	assertPanics(t, ErrFrozen, func() { js.Object("other") })
+}
@@ -0,0 +1,87 @@
+package simplejson
+
+// ToOpenAPISchema returns the OpenAPI 3.0 schema object describing the
+// document, as inferred by InferSchema, to embed in generated specs
+func (j *JSON) ToOpenAPISchema() *JSON {
+	return j.InferSchema().ToOpenAPISchema()
+}
+
+// ToOpenAPISchema returns `s` as an OpenAPI 3.0 schema object. OpenAPI 3.0
+// has no null type, nullable values are marked with `nullable: true`, and
+// null is added to their enum if any.
+func (s *Schema) ToOpenAPISchema() *JSON {
+	j := &JSON{data: openAPISchema(s)}
+	j.document()
+	return j
+}
+
+func openAPISchema(s *Schema) map[string]interface{} {
+	m := make(map[string]interface{})
+	if s == nil {
+		return m
+	}
+	switch s.Type {
+	case "":
+	case "null":
+		m["nullable"] = true
+	default:
+		m["type"] = s.Type
+	}
+	if s.Nullable && s.Type != "" {
+		m["nullable"] = true
+	}
+	if s.Format != "" {
+		m["format"] = s.Format
+	}
+	if len(s.Enum) > 0 {
+		enum := deepCopy(s.Enum).([]interface{})
+		if m["nullable"] == true && !containsNull(enum) {
+			enum = append(enum, nil)
+		}
+		m["enum"] = enum
+	}
+	setInt := func(key string, v *int) {
+		if v != nil {
+			m[key] = *v
+		}
+	}
+	if s.Minimum != nil {
+		m["minimum"] = *s.Minimum
+	}
+	if s.Maximum != nil {
+		m["maximum"] = *s.Maximum
+	}
+	setInt("minLength", s.MinLength)
+	setInt("maxLength", s.MaxLength)
+	setInt("minItems", s.MinItems)
+	setInt("maxItems", s.MaxItems)
+
+	if s.Type == "object" || len(s.Properties) > 0 {
+		props := make(map[string]interface{}, len(s.Properties))
+		for key, p := range s.Properties {
+			props[key] = openAPISchema(p)
+		}
+		m["properties"] = props
+	}
+	if len(s.Required) > 0 {
+		required := make([]interface{}, len(s.Required))
+		for i, key := range s.Required {
+			required[i] = key
+		}
+		m["required"] = required
+	}
+	if s.Type == "array" {
+		// items is required for arrays, an empty schema allows any value
+		m["items"] = openAPISchema(s.Items)
+	}
+	return m
+}
+
+func containsNull(a []interface{}) bool {
+	for _, v := range a {
+		if v == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestToOpenAPISchema(t *testing.T) {
+	js := mustJSON(`{"id": "4f8f2e4c-1b1e-4c7b-9a55-0a6f1c2d3e4f", "tags": [], "parent": null, "scores": [1, null]}`)
+	b, err := js.ToOpenAPISchema().EncodeWith(SortedKeys())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"properties":{"id":{"format":"uuid","type":"string"},"parent":{"nullable":true},`+
+		`"scores":{"items":{"nullable":true,"type":"integer"},"type":"array"},"tags":{"items":{},"type":"array"}},`+
+		`"required":["id","parent","scores","tags"],"type":"object"}`, string(b))
+
+	min, max := 1, 3
+	s := &Schema{Type: "string", Nullable: true, Enum: []interface{}{"a", "b"}, MinLength: &min, MaxLength: &max}
+	b, err = s.ToOpenAPISchema().EncodeWith(SortedKeys())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"enum":["a","b",null],"maxLength":3,"minLength":1,"nullable":true,"type":"string"}`, string(b))
+}
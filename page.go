@@ -0,0 +1,60 @@
+package simplejson
+
+// Page returns a new document holding the `n`th page, numbered from 1, of the
+// array `j` split in pages of `size` elements, along with pagination metadata:
+//
+//	{"items": [...], "page": 2, "size": 20, "total": 45, "pages": 3}
+//
+// Pages past the end, or of values other than arrays, have no items.
+// A non positive `size` makes a single page of every element.
+func (j *JSON) Page(n, size int) *JSON {
+	a, _ := j.CheckArray()
+	total := len(a)
+	if size <= 0 {
+		size = total
+	}
+	pages := 0
+	if size > 0 {
+		pages = (total + size - 1) / size
+	}
+
+	items := []interface{}{}
+	if n >= 1 && n <= pages {
+		start := (n - 1) * size
+		end := start + size
+		if end > total {
+			end = total
+		}
+		items = deepCopy(a[start:end]).([]interface{})
+	}
+
+	page := &JSON{data: map[string]interface{}{
+		"items": items,
+		"page":  n,
+		"size":  size,
+		"total": total,
+		"pages": pages,
+	}}
+	page.document()
+	return page
+}
+
+// Chunk splits the array `j` into new documents of at most `size` elements,
+// it returns nil for values other than arrays or a non positive `size`
+func (j *JSON) Chunk(size int) []*JSON {
+	a, ok := j.CheckArray()
+	if !ok || size <= 0 {
+		return nil
+	}
+	chunks := make([]*JSON, 0, (len(a)+size-1)/size)
+	for start := 0; start < len(a); start += size {
+		end := start + size
+		if end > len(a) {
+			end = len(a)
+		}
+		c := &JSON{data: deepCopy(a[start:end])}
+		c.document()
+		chunks = append(chunks, c)
+	}
+	return chunks
+}
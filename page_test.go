@@ -0,0 +1,38 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestPage(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"list":[1,2,3,4,5,{"a":1}]}`))
+
+	p := js.Get("list").Page(2, 4)
+	b, _ := p.Encode()
+	assert.Equal(t, `{"items":[5,{"a":1}],"page":2,"pages":2,"size":4,"total":6}`, string(b))
+
+	p.Get("items", 1).Set("a", 2)
+	assert.Equal(t, 1, js.Get("list", 5, "a").Int())
+
+	b, _ = js.Get("list").Page(3, 4).Encode()
+	assert.Equal(t, `{"items":[],"page":3,"pages":2,"size":4,"total":6}`, string(b))
+	b, _ = js.Get("list").Page(1, 0).Encode()
+	assert.Equal(t, `{"items":[1,2,3,4,5,{"a":1}],"page":1,"pages":1,"size":6,"total":6}`, string(b))
+	b, _ = js.Page(1, 10).Encode()
+	assert.Equal(t, `{"items":[],"page":1,"pages":0,"size":10,"total":0}`, string(b))
+}
+
+func TestChunk(t *testing.T) {
+	js, _ := NewJSON([]byte(`[1,2,3,4,5]`))
+	chunks := js.Chunk(2)
+	assert.Equal(t, 3, len(chunks))
+	b, _ := chunks[2].Encode()
+	assert.Equal(t, `[5]`, string(b))
+	chunks[0].SetIndex(0, 9)
+	assert.Equal(t, 1, js.Get(0).Int())
+
+	assert.Equal(t, 0, len(New().Chunk(2)))
+	assert.Equal(t, 0, len(js.Chunk(0)))
+}
@@ -0,0 +1,167 @@
+package simplejson
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// formatPath returns a human readable representation of a branch,
+// such as `items[3].name`
+func formatPath(branch []interface{}) string {
+	if len(branch) == 0 {
+		return "(root)"
+	}
+	var b strings.Builder
+	for _, p := range branch {
+		switch k := p.(type) {
+		case string:
+			if isIdentifier(k) {
+				if b.Len() > 0 {
+					b.WriteByte('.')
+				}
+				b.WriteString(k)
+			} else {
+				b.WriteString("[" + strconv.Quote(k) + "]")
+			}
+		case int:
+			b.WriteString("[" + strconv.Itoa(k) + "]")
+		default:
+			fmt.Fprintf(&b, "[%v]", k)
+		}
+	}
+	return b.String()
+}
+
+func isIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// formatPointer returns the RFC 6901 JSON Pointer for a branch
+func formatPointer(branch []interface{}) string {
+	var b strings.Builder
+	for _, p := range branch {
+		b.WriteByte('/')
+		switch k := p.(type) {
+		case string:
+			b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(k))
+		case int:
+			b.WriteString(strconv.Itoa(k))
+		}
+	}
+	return b.String()
+}
+
+// parsePointer parses a RFC 6901 JSON Pointer into a branch.
+// Segments are returned as strings, arrays are indexed by lookupPointer.
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("simplejson: invalid JSON pointer %q", ptr)
+	}
+	segments := strings.Split(ptr[1:], "/")
+	for i, s := range segments {
+		segments[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+	}
+	return segments, nil
+}
+
+// lookupPointer returns the value addressed by the parsed pointer within `data`
+// and its branch, with array indexes converted to ints
+func lookupPointer(data interface{}, segments []string) (interface{}, []interface{}, bool) {
+	branch := make([]interface{}, 0, len(segments))
+	for _, s := range segments {
+		switch v := data.(type) {
+		case map[string]interface{}:
+			val, ok := v[s]
+			if !ok {
+				return nil, nil, false
+			}
+			data = val
+			branch = append(branch, s)
+		case []interface{}:
+			i, err := strconv.Atoi(s)
+			if err != nil || i < 0 || i >= len(v) || (len(s) > 1 && s[0] == '0') {
+				return nil, nil, false
+			}
+			data = v[i]
+			branch = append(branch, i)
+		default:
+			return nil, nil, false
+		}
+	}
+	return data, branch, true
+}
+
+// dottedBranch converts a dotted path into a branch, numeric segments
+// becoming indexes where they address existing arrays of `data`
+func dottedBranch(data interface{}, path string) []interface{} {
+	segments := splitPath(path)
+	branch := make([]interface{}, len(segments))
+	for i, s := range segments {
+		branch[i] = s
+		if a, ok := data.([]interface{}); ok {
+			if n, err := strconv.Atoi(s); err == nil {
+				branch[i] = n
+				data, _ = getIndex(a, n)
+				continue
+			}
+		}
+		data, _ = getKey(data, s)
+	}
+	return branch
+}
+
+// globBranches returns the branches of the values matching the dotted `path`
+// within `data`, a `*` segment matching every key of an object or element of
+// an array
+func globBranches(data interface{}, path string) [][]interface{} {
+	var out [][]interface{}
+	var walk func(data interface{}, segments []string, branch []interface{})
+	walk = func(data interface{}, segments []string, branch []interface{}) {
+		if len(segments) == 0 {
+			out = append(out, branch)
+			return
+		}
+		s, rest := segments[0], segments[1:]
+		next := func(key interface{}, val interface{}) {
+			walk(val, rest, append(branch[:len(branch):len(branch)], key))
+		}
+		switch v := data.(type) {
+		case map[string]interface{}:
+			if s == "*" {
+				keys := make([]string, 0, len(v))
+				for key := range v {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					next(key, v[key])
+				}
+			} else if val, ok := v[s]; ok {
+				next(s, val)
+			}
+		case []interface{}:
+			if s == "*" {
+				for i, val := range v {
+					next(i, val)
+				}
+			} else if i, err := strconv.Atoi(s); err == nil && normalizeIndex(&i, len(v)) {
+				next(i, v[i])
+			}
+		}
+	}
+	walk(data, splitPath(path), nil)
+	return out
+}
@@ -0,0 +1,18 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestGlobBranches(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"users":[{"email":"a","b":{"x":1,"y":2}},{"name":"n"}],"n":1}`))
+
+	assert.Equal(t, [][]interface{}{{"users", 0, "email"}}, globBranches(js.data, "users.*.email"))
+	assert.Equal(t, [][]interface{}{{"users", 0, "b", "x"}, {"users", 0, "b", "y"}}, globBranches(js.data, "users.0.b.*"))
+	assert.Equal(t, [][]interface{}{{"users", 1, "name"}}, globBranches(js.data, "users.-1.name"))
+	assert.Equal(t, [][]interface{}{{"n"}, {"users"}}, globBranches(js.data, "*"))
+	assert.Equal(t, 0, len(globBranches(js.data, "n.x")))
+	assert.Equal(t, [][]interface{}{nil}, globBranches(js.data, ""))
+}
@@ -0,0 +1,145 @@
+package simplejson
+
+import "sync"
+
+// arrayClasses are the capacities of pooled arrays, larger arrays are left
+// to the garbage collector
+var arrayClasses = [...]int{4, 8, 16, 32, 64}
+
+var (
+	jsonPool  = sync.Pool{New: func() interface{} { return new(JSON) }}
+	mapPool   = sync.Pool{New: func() interface{} { return make(map[string]interface{}) }}
+	arrayPool [len(arrayClasses)]sync.Pool
+)
+
+func init() {
+	for i := range arrayPool {
+		size := arrayClasses[i]
+		arrayPool[i].New = func() interface{} { return make([]interface{}, 0, size) }
+	}
+}
+
+// Acquire returns a pointer to an empty `JSON` object taken from a pool.
+// It behaves like New, but the wrapper, its document state and the maps
+// created by SetPath may be reused from documents previously handed back
+// through Release.
+func Acquire() *JSON {
+	j := jsonPool.Get().(*JSON)
+	j.document().pooling = true
+	j.data = j.acquireMap()
+	return j
+}
+
+// AcquireArray returns an empty `[]interface{}` with at least `capacity`
+// taken from a pool, suitable for building array values passed to Set.
+// Arrays stay owned by the caller, Reset does not recycle them,
+// they can be handed back with ReleaseArray.
+func AcquireArray(capacity int) []interface{} {
+	for i, size := range arrayClasses {
+		if capacity <= size {
+			return arrayPool[i].Get().([]interface{})[:0]
+		}
+	}
+	return make([]interface{}, 0, capacity)
+}
+
+// Release recycles the maps of the document as Reset does and hands it back
+// to the pool. The caller must not use `j`, nor any value or `JSON` obtained
+// from it, after calling Release.
+func (j *JSON) Release() {
+	if j == nil {
+		return
+	}
+	j.recycle()
+	d := j.doc
+	*j = JSON{}
+	if d != nil && d.root == j && d.pooling {
+		// keep the state of the document, the registry of pooled maps
+		// is emptied, maps removed from the document are left to the
+		// garbage collector
+		pooled := d.pooled
+		for id := range pooled {
+			delete(pooled, id)
+		}
+		*d = document{root: j, pooled: pooled}
+		j.doc = d
+	}
+	jsonPool.Put(j)
+}
+
+// ReleaseArray clears `a` and hands it back to the pool of AcquireArray,
+// the caller must not use it afterwards
+func ReleaseArray(a []interface{}) {
+	a = a[:cap(a)]
+	for i := range a {
+		a[i] = nil
+	}
+	for i, size := range arrayClasses {
+		if cap(a) == size {
+			arrayPool[i].Put(a[:0])
+			return
+		}
+	}
+}
+
+// Reset leaves the document holding an empty object, ready for reuse as if
+// returned by New. Documents returned by Acquire recycle the maps they took
+// from the pool, through Acquire, Reset and SetPath. Containers decoded or
+// passed in by the caller are left untouched. Values previously obtained from
+// the document must not be used afterwards, since their containers may be
+// handed out again by Acquire.
+func (j *JSON) Reset() {
+	if j == nil {
+		return
+	}
+	j.recycle()
+	j.setData(j.acquireMap())
+}
+
+// recycle returns the maps of `j` taken from the pool to it
+func (j *JSON) recycle() {
+	j.checkWritable()
+	// containers referenced by snapshots are left to the garbage collector
+	if j.doc != nil && !j.doc.shared {
+		j.doc.release(j.data)
+	}
+}
+
+func acquireMap() map[string]interface{} {
+	return mapPool.Get().(map[string]interface{})
+}
+
+// acquireMap returns a new map, taken from the pool and recycled by Reset
+// if `j` belongs to a document returned by Acquire
+func (j *JSON) acquireMap() map[string]interface{} {
+	d := j.doc
+	if d == nil || !d.pooling {
+		return make(map[string]interface{})
+	}
+	m := acquireMap()
+	if d.pooled == nil {
+		d.pooled = make(map[uintptr]interface{})
+	}
+	d.pooled[identity(m)] = m
+	return m
+}
+
+// release clears the maps of `data` taken from the pool by the document and
+// returns them to it. Maps are released once, as they are removed from the
+// pooled set, and the values of other containers are left untouched.
+func (d *document) release(data interface{}) {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return
+	}
+	id := identity(m)
+	if _, pooled := d.pooled[id]; !pooled {
+		return
+	}
+	delete(d.pooled, id)
+	for key, val := range m {
+		d.release(val)
+		delete(m, key)
+	}
+	mapPool.Put(m)
+}
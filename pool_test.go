@@ -0,0 +1,95 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestAcquireRelease(t *testing.T) {
+	js := Acquire()
+	assert.Equal(t, map[string]interface{}{}, js.Interface())
+
+	arr := AcquireArray(3)
+	assert.Equal(t, 0, len(arr))
+	assert.Equal(t, 4, cap(arr))
+	js.Set("list", append(arr, "a", "b"))
+	js.SetPath([]string{"a", "b"}, 1)
+	assert.Equal(t, "b", js.Get("list", 1).String())
+
+	js.Reset()
+	assert.Equal(t, map[string]interface{}{}, js.Interface())
+	js.Release()
+
+	big := AcquireArray(1000)
+	assert.Equal(t, 1000, cap(big))
+}
+
+func TestResetDecoded(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":[{"b":1},[1,2,3]],"c":{"d":"e"}}`))
+	assert.Equal(t, nil, err)
+
+	// decoded containers are not recycled
+	m := js.Get("c").Map()
+	js.Reset()
+	assert.Equal(t, map[string]interface{}{"d": "e"}, m)
+	assert.Equal(t, map[string]interface{}{}, js.Interface())
+}
+
+func TestResetOwnership(t *testing.T) {
+	// containers of the caller are left untouched
+	mine := map[string]interface{}{"k": "v"}
+	js := Acquire()
+	js.Set("a", mine)
+	js.Set("b", mine)
+	js.SetPath([]string{"p", "q"}, 1)
+	pooled := js.Get("p").Map()
+	js.Reset()
+	assert.Equal(t, map[string]interface{}{"k": "v"}, mine)
+	assert.Equal(t, 0, len(pooled))
+
+	// aliased pooled maps are released once
+	js.SetPath([]string{"x", "y"}, 1)
+	x := js.Get("x").Map()
+	js.Set("alias", x)
+	js.Reset()
+	a, b := acquireMap(), acquireMap()
+	assert.NotEqual(t, identity(a), identity(b))
+
+	// resetting a view empties it within its document
+	js = mustJSON(`{"a":{"b":1},"c":2}`)
+	js.Get("a").Reset()
+	assert.Equal(t, `{"a":{},"c":2}`, string(mustEncode(t, js)))
+}
+
+func TestReleaseArray(t *testing.T) {
+	a := append(AcquireArray(8), "x", "y")
+	ReleaseArray(a)
+	assert.Equal(t, nil, a[:2][0])
+	ReleaseArray(make([]interface{}, 3))
+}
+
+func TestPoolOnlyAcquired(t *testing.T) {
+	js := New()
+	for i := 0; i < 100; i++ {
+		js.SetPath([]string{"a", "b"}, i)
+		js.Del("a")
+	}
+	assert.Equal(t, 0, len(js.doc.pooled))
+}
+
+func TestPoolAllocations(t *testing.T) {
+	fill := func(js *JSON) {
+		js.SetPath([]string{"a", "b"}, 1)
+		js.SetPath([]string{"c", "d"}, 2)
+	}
+	plain := testing.AllocsPerRun(100, func() {
+		fill(New())
+	})
+	pooled := testing.AllocsPerRun(100, func() {
+		js := Acquire()
+		fill(js)
+		js.Release()
+	})
+	assert.T(t, pooled < plain, pooled, plain)
+}
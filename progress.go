@@ -0,0 +1,70 @@
+package simplejson
+
+import "io"
+
+// WithParseProgress calls `fn` with the number of bytes read so far as
+// NewFromReaderWith and Decoder read their input, to drive progress bars and
+// watchdogs on long decodings. NewJSONWith, which parses a buffer at once,
+// calls it once with the length of the buffer when parsed.
+func WithParseProgress(fn func(bytesRead int64)) DecodeOption {
+	return func(c *decodeConfig) {
+		c.progress = fn
+	}
+}
+
+// WithEncodeProgress calls `fn` with the number of bytes written so far as
+// documents are written by EncodePrettyTo, which streams its output, or by
+// an Encoder, after each document
+func WithEncodeProgress(fn func(bytesWritten int64)) EncodeOption {
+	return func(c *encodeConfig) {
+		c.progress = fn
+	}
+}
+
+// input returns `r` limited and reporting progress as configured
+func (c *decodeConfig) input(r io.Reader) io.Reader {
+	if c.progress != nil {
+		r = &progressReader{r: r, fn: c.progress}
+	}
+	return c.limit(r)
+}
+
+// output returns `w` reporting progress as configured
+func (c *encodeConfig) output(w io.Writer) io.Writer {
+	if c.progress == nil {
+		return w
+	}
+	return &progressWriter{w: w, fn: c.progress}
+}
+
+// progressReader calls `fn` with the bytes read from `r` after each read
+type progressReader struct {
+	r  io.Reader
+	n  int64
+	fn func(int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.n += int64(n)
+		p.fn(p.n)
+	}
+	return n, err
+}
+
+// progressWriter calls `fn` with the bytes written to `w` after each write
+type progressWriter struct {
+	w  io.Writer
+	n  int64
+	fn func(int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if n > 0 {
+		p.n += int64(n)
+		p.fn(p.n)
+	}
+	return n, err
+}
@@ -0,0 +1,57 @@
+package simplejson
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestParseProgress(t *testing.T) {
+	body := `{"a":[1,2,3],"b":"` + strings.Repeat("x", 10000) + `"}`
+	var calls int
+	var read int64
+	progress := WithParseProgress(func(n int64) {
+		assert.T(t, n > read)
+		calls++
+		read = n
+	})
+
+	_, err := NewFromReaderWith(strings.NewReader(body), progress)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(len(body)), read)
+	assert.T(t, calls > 1)
+
+	calls, read = 0, 0
+	_, err = NewJSONWith([]byte(body), progress)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, int64(len(body)), read)
+
+	calls, read = 0, 0
+	dec := NewDecoder(strings.NewReader("1 2 3"), progress)
+	for dec.More() {
+		_, err = dec.Decode()
+		assert.Equal(t, nil, err)
+	}
+	assert.Equal(t, int64(5), read)
+}
+
+func TestEncodeProgress(t *testing.T) {
+	js := mustJSON(`{"a":[1,2,3],"b":"` + strings.Repeat("x", 10000) + `"}`)
+	var written int64
+	progress := WithEncodeProgress(func(n int64) {
+		written = n
+	})
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, js.EncodePrettyTo(&buf, progress))
+	assert.Equal(t, int64(buf.Len()), written)
+
+	buf.Reset()
+	enc := NewEncoder(&buf, progress)
+	assert.Equal(t, nil, enc.Encode(js))
+	assert.Equal(t, nil, enc.Encode(js))
+	assert.Equal(t, int64(buf.Len()), written)
+}
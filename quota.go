@@ -0,0 +1,99 @@
+package simplejson
+
+import "errors"
+
+// ErrQuotaExceeded is the error of modifications that would make a document
+// exceed its quota, recorded by the document and returned by Err, and by the
+// methods returning errors, such as Merge and Tx.Commit
+var ErrQuotaExceeded = errors.New("simplejson: document quota exceeded")
+
+// quota bounds the size of a document as it is modified
+type quota struct {
+	maxNodes, maxBytes int
+	nodes, bytes       int
+	// reverting is set while a rejected modification is undone
+	reverting bool
+}
+
+// SetQuota bounds the size of the whole document `j` belongs to, to `maxNodes`
+// values, containers included, and `maxBytes` bytes, an estimate of the size of
+// its compact encoding. Zero values mean no limit, SetQuota(0, 0) removes the quota.
+//
+// Once set, modifications made through Set, SetPath, Append, Merge and the
+// other mutators that would exceed the quota are reverted and not reported to
+// watchers. ErrQuotaExceeded is then returned by the methods returning errors,
+// and recorded by the document, returned by Err. Append keeps the values
+// preceding the rejected one. Modifications shrinking the document are always
+// allowed.
+func (j *JSON) SetQuota(maxNodes, maxBytes int) {
+	if j == nil {
+		return
+	}
+	d := j.document()
+	if maxNodes <= 0 && maxBytes <= 0 {
+		d.quota = nil
+		return
+	}
+	d.track = true
+	data := d.root.data
+	d.quota = &quota{
+		maxNodes: maxNodes,
+		maxBytes: maxBytes,
+		nodes:    countNodes(data),
+		bytes:    encodedSize(data),
+	}
+}
+
+// Usage returns the number of values of the document `j` belongs to and
+// the estimated size of its encoding, as accounted by SetQuota
+func (j *JSON) Usage() (nodes, bytes int) {
+	j = j.orMissing()
+	d := j.document()
+	if q := d.quota; q != nil {
+		return q.nodes, q.bytes
+	}
+	return countNodes(d.root.data), encodedSize(d.root.data)
+}
+
+// charge accounts for the modification `c` made to the document, reverting it
+// and returning ErrQuotaExceeded when it makes the document exceed its quota
+func (d *document) charge(c Change) error {
+	q := d.quota
+	nodes, bytes := 0, 0
+	if c.Op != ChangeRemove {
+		nodes, bytes = countNodes(c.New), encodedSize(c.New)
+	}
+	if c.Op != ChangeAdd {
+		nodes, bytes = nodes-countNodes(c.Old), bytes-encodedSize(c.Old)
+	}
+	if len(c.Path) > 0 && c.Op != ChangeReplace {
+		// account for the key, or the separator of array elements
+		member := 1
+		if key, ok := c.Path[len(c.Path)-1].(string); ok {
+			member += quotedLen(key) + 1
+		}
+		if c.Op == ChangeAdd {
+			bytes += member
+		} else {
+			bytes -= member
+		}
+	}
+
+	if nodes > 0 && q.maxNodes > 0 && q.nodes+nodes > q.maxNodes ||
+		bytes > 0 && q.maxBytes > 0 && q.bytes+bytes > q.maxBytes {
+		q.reverting = true
+		d.apply(Change{Op: inverse(c.Op), Path: c.Path, Old: c.New, New: c.Old})
+		q.reverting = false
+		return ErrQuotaExceeded
+	}
+	q.nodes += nodes
+	q.bytes += bytes
+	return nil
+}
+
+// encodedSize returns the estimated length of the compact encoding of `data`
+func encodedSize(data interface{}) int {
+	var s DocumentStats
+	s.walk(data, 0)
+	return s.EncodedSize
+}
@@ -0,0 +1,87 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestQuota(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"a":[1,2],"b":"x"}`))
+	nodes, bytes := js.Usage()
+	assert.Equal(t, 5, nodes)
+	assert.Equal(t, len(`{"a":[1,2],"b":"x"}`), bytes)
+
+	js.SetQuota(8, 0)
+	js.Get("a").Append(3)
+	js.Set("c", true)
+	js.SetPath([]string{"d"}, nil)
+	nodes, _ = js.Usage()
+	assert.Equal(t, 8, nodes)
+
+	assertQuota := func(fn func()) {
+		js.doc.err.Store(errBox{})
+		fn()
+		assert.Equal(t, ErrQuotaExceeded, js.Err())
+	}
+	var changes []Change
+	js.OnChange(nil, func(c Change) { changes = append(changes, c) })
+	assertQuota(func() { js.Set("e", 1) })
+	assertQuota(func() { js.Get("a").Append(4) })
+	assertQuota(func() { js.SetPath([]string{"d", "e"}, 1) })
+	assertQuota(func() { js.Get("a").SetIndex(0, []interface{}{1}) })
+	assertQuota(func() { assert.Equal(t, ErrQuotaExceeded, js.Graft([]interface{}{"a", 0}, mustJSON(`[1]`), false)) })
+	assert.Equal(t, 0, len(changes))
+
+	assert.Equal(t, ErrQuotaExceeded, js.Merge(mustJSON(`{"f":{"g":1}}`)))
+	tx := js.Begin()
+	tx.Set("h", 1)
+	assert.Equal(t, ErrQuotaExceeded, tx.Commit())
+	assert.Equal(t, nil, tx.Rollback())
+
+	b, _ := js.Encode()
+	assert.Equal(t, `{"a":[1,2,3],"b":"x","c":true,"d":null}`, string(b))
+	nodes, bytes = js.Usage()
+	assert.Equal(t, 8, nodes)
+	assert.Equal(t, len(b), bytes)
+
+	// removals free space, replacements are accounted for
+	js.Del("c")
+	js.Set("b", "longer")
+	js.Get("a").RemoveIndex(0)
+	nodes, bytes = js.Usage()
+	b, _ = js.Encode()
+	assert.Equal(t, 6, nodes)
+	assert.Equal(t, len(b), bytes)
+
+	js.SetQuota(0, len(b))
+	assertQuota(func() { js.Set("b", "longest") })
+	js.Set("b", "short")
+	assert.Equal(t, "short", js.Get("b").String())
+
+	js.SetQuota(0, 0)
+	js.Set("b", "unbounded now")
+	assert.Equal(t, "unbounded now", js.Get("b").String())
+}
+
+func TestQuotaAppend(t *testing.T) {
+	js := mustJSON(`{"a":[1]}`)
+	js.SetQuota(5, 0)
+	a := js.Get("a")
+	a.Append(2, 3, 4)
+	assert.Equal(t, ErrQuotaExceeded, js.Err())
+	// the values preceding the rejected one are kept
+	assert.Equal(t, 3, len(a.Array()))
+	assert.Equal(t, `{"a":[1,2,3]}`, string(mustEncode(t, js)))
+	nodes, bytes := js.Usage()
+	assert.Equal(t, 5, nodes)
+	assert.Equal(t, len(`{"a":[1,2,3]}`), bytes)
+}
+
+func mustJSON(s string) *JSON {
+	js, err := NewJSON([]byte(s))
+	if err != nil {
+		panic(err)
+	}
+	return js
+}
@@ -0,0 +1,111 @@
+package simplejson
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RefOptions configures ResolveRefs
+type RefOptions struct {
+	// Load returns the document identified by the URI part of references
+	// that don't point within the document itself. When nil, such references
+	// are left unresolved.
+	Load func(uri string) (*JSON, error)
+}
+
+// ResolveRefs returns a copy of the document where every JSON Reference
+// object, such as {"$ref": "#/definitions/x"}, is replaced by the value it
+// points to. Members besides "$ref" in reference objects are ignored.
+// Circular references are reported as errors.
+func (j *JSON) ResolveRefs(opts RefOptions) (*JSON, error) {
+	j = j.orMissing()
+	r := &refResolver{opts: opts, docs: map[string]interface{}{"": j.data}}
+	data, err := r.resolve(j.data, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	d := &JSON{data: data}
+	d.document()
+	return d, nil
+}
+
+type refResolver struct {
+	opts  RefOptions
+	docs  map[string]interface{}
+	stack []string
+}
+
+// resolve returns a copy of `data` with references replaced,
+// `uri` identifies the document `data` belongs to
+func (r *refResolver) resolve(data interface{}, uri string, path []interface{}) (interface{}, error) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			return r.follow(ref, uri, path)
+		}
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			res, err := r.resolve(val, uri, append(path, key))
+			if err != nil {
+				return nil, err
+			}
+			m[key] = res
+		}
+		return m, nil
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, val := range v {
+			res, err := r.resolve(val, uri, append(path, i))
+			if err != nil {
+				return nil, err
+			}
+			a[i] = res
+		}
+		return a, nil
+	}
+	return data, nil
+}
+
+func (r *refResolver) follow(ref, uri string, path []interface{}) (interface{}, error) {
+	target, fragment := ref, ""
+	if i := strings.IndexByte(ref, '#'); i >= 0 {
+		target, fragment = ref[:i], ref[i+1:]
+	}
+	if target == "" {
+		target = uri
+	}
+
+	doc, ok := r.docs[target]
+	if !ok {
+		if r.opts.Load == nil {
+			return map[string]interface{}{"$ref": ref}, nil
+		}
+		loaded, err := r.opts.Load(target)
+		if err != nil {
+			return nil, fmt.Errorf("simplejson: loading %q referenced at %s: %w", target, formatPath(path), err)
+		}
+		doc = loaded.data
+		r.docs[target] = doc
+	}
+
+	key := target + "#" + fragment
+	for _, active := range r.stack {
+		if active == key {
+			return nil, fmt.Errorf("simplejson: circular reference %q at %s", ref, formatPath(path))
+		}
+	}
+
+	segments, err := parsePointer(fragment)
+	if err != nil {
+		return nil, err
+	}
+	val, _, ok := lookupPointer(doc, segments)
+	if !ok {
+		return nil, fmt.Errorf("simplejson: unresolved reference %q at %s", ref, formatPath(path))
+	}
+
+	r.stack = append(r.stack, key)
+	res, err := r.resolve(val, target, path)
+	r.stack = r.stack[:len(r.stack)-1]
+	return res, err
+}
@@ -0,0 +1,57 @@
+package simplejson
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestResolveRefs(t *testing.T) {
+	js, _ := NewJSON([]byte(`{
+		"definitions": {
+			"id": {"type": "integer"},
+			"user": {"type": "object", "properties": {"id": {"$ref": "#/definitions/id"}}},
+			"a~b/c": {"type": "string"}
+		},
+		"items": [{"$ref": "#/definitions/user"}, {"$ref": "#/definitions/a~0b~1c"}],
+		"remote": {"$ref": "common.json#/name"},
+		"unresolved": {"$ref": "other.json"}
+	}`))
+
+	notFound := errors.New("not found")
+	res, err := js.ResolveRefs(RefOptions{Load: func(uri string) (*JSON, error) {
+		if uri == "common.json" {
+			return NewJSON([]byte(`{"name": {"$ref": "#/base"}, "base": {"type": "string", "maxLength": 10}}`))
+		}
+		return nil, notFound
+	}})
+	assert.Equal(t, "loading \"other.json\"", err.Error()[12:32])
+	assert.Equal(t, true, errors.Is(err, notFound))
+
+	res, err = js.ResolveRefs(RefOptions{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "integer", res.Get("items", 0, "properties", "id", "type").String())
+	assert.Equal(t, "string", res.Get("items", 1, "type").String())
+	assert.Equal(t, "common.json#/name", res.Get("remote", "$ref").String())
+
+	// the original document is untouched
+	assert.Equal(t, "#/definitions/user", js.Get("items", 0, "$ref").String())
+
+	js.Del("unresolved")
+	res, err = js.ResolveRefs(RefOptions{Load: func(uri string) (*JSON, error) {
+		return NewJSON([]byte(`{"name": {"$ref": "#/base"}, "base": {"maxLength": 10}}`))
+	}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 10, res.Get("remote", "maxLength").Int())
+}
+
+func TestResolveRefsErrors(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"a": {"b": {"$ref": "#/a"}}}`))
+	_, err := js.ResolveRefs(RefOptions{})
+	assert.Equal(t, `simplejson: circular reference "#/a" at a.b.b`, err.Error())
+
+	js, _ = NewJSON([]byte(`{"a": {"$ref": "#/missing"}}`))
+	_, err = js.ResolveRefs(RefOptions{})
+	assert.Equal(t, `simplejson: unresolved reference "#/missing" at a`, err.Error())
+}
@@ -0,0 +1,114 @@
+package simplejson
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// GetRelative resolves the Relative JSON Pointer `ptr` against the position
+// of `j` within its document. The pointer starts with the number of levels
+// to go up, optionally followed by an offset moving to a sibling array element,
+// then either a JSON pointer from there or `#`, which returns the key or index
+// of the reached value:
+//
+//	js.Get("items", 1, "name").GetRelative("1/price") // items[1].price
+//	js.Get("items", 1, "name").GetRelative("1-1/name") // items[0].name
+//	js.Get("items", 1, "name").GetRelative("1#")       // 1
+//
+// `j` must be located within its document, which is always the case of the
+// views of objects and arrays, and of scalars once TrackParents was called.
+func (j *JSON) GetRelative(ptr string) (*JSON, error) {
+	j = j.orMissing()
+	up, offset, rest, err := parseRelativePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	base, ok := j.location()
+	if !ok {
+		return nil, fmt.Errorf("simplejson: relative pointer %q: value is not located within its document", ptr)
+	}
+	if up > len(base) {
+		return nil, fmt.Errorf("simplejson: relative pointer %q: goes above the root of the document", ptr)
+	}
+	branch := append([]interface{}{}, base[:len(base)-up]...)
+	root := j
+	if j.doc != nil {
+		root = j.doc.root
+	}
+
+	if offset != 0 {
+		n := len(branch)
+		index, isIndex := 0, false
+		if n > 0 {
+			index, isIndex = branch[n-1].(int)
+		}
+		if isIndex {
+			parent, _ := lookup(root.data, branch[:n-1])
+			a, _ := parent.([]interface{})
+			index += offset
+			isIndex = index >= 0 && index < len(a)
+		}
+		if !isIndex {
+			return nil, fmt.Errorf("simplejson: relative pointer %q: no array element at offset %d", ptr, offset)
+		}
+		branch[n-1] = index
+	}
+
+	if rest == "#" {
+		if len(branch) == 0 {
+			return nil, fmt.Errorf("simplejson: relative pointer %q: the root of the document has no key", ptr)
+		}
+		return j.wrap(branch[len(branch)-1]), nil
+	}
+
+	data, _ := lookup(root.data, branch)
+	segments, err := parsePointer(rest)
+	if err != nil {
+		return nil, err
+	}
+	_, tail, ok := lookupPointer(data, segments)
+	if !ok {
+		return nil, fmt.Errorf("simplejson: relative pointer %q not found", ptr)
+	}
+	jin, _ := root.CheckGet(append(branch, tail...)...)
+	return jin, nil
+}
+
+// parseRelativePointer splits a Relative JSON Pointer into the number of levels
+// to go up, the array index offset and the remaining JSON pointer or `#`
+func parseRelativePointer(ptr string) (up, offset int, rest string, err error) {
+	invalid := fmt.Errorf("simplejson: invalid relative JSON pointer %q", ptr)
+	digits := func(s string) int {
+		i := 0
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i > 1 && s[0] == '0' {
+			return 0
+		}
+		return i
+	}
+
+	n := digits(ptr)
+	if n == 0 {
+		return 0, 0, "", invalid
+	}
+	if up, err = strconv.Atoi(ptr[:n]); err != nil {
+		return 0, 0, "", invalid
+	}
+	rest = ptr[n:]
+	if rest != "" && (rest[0] == '+' || rest[0] == '-') {
+		n = digits(rest[1:])
+		if n == 0 {
+			return 0, 0, "", invalid
+		}
+		if offset, err = strconv.Atoi(rest[:n+1]); err != nil {
+			return 0, 0, "", invalid
+		}
+		rest = rest[n+1:]
+	}
+	if rest != "" && rest != "#" && rest[0] != '/' {
+		return 0, 0, "", invalid
+	}
+	return up, offset, rest, nil
+}
@@ -0,0 +1,49 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestGetRelative(t *testing.T) {
+	js := mustJSON(`{"items":[{"name":"a","price":1},{"name":"b","price":2,"tags":{"x/y":true}}],"total":3}`)
+	js.StartRecording()
+	name := js.Get("items", 1, "name")
+	tags := js.Get("items", 1, "tags")
+
+	cases := map[string]interface{}{
+		"0":           "b",
+		"1/price":     2,
+		"1-1/name":    "a",
+		"1#":          1,
+		"0#":          "name",
+		"2#":          "items",
+		"3/total":     3,
+		"2/0/name":    "a",
+		"1/tags/x~1y": true,
+	}
+	for ptr, want := range cases {
+		jin, err := name.GetRelative(ptr)
+		assert.Equal(t, nil, err, ptr)
+		b, _ := jin.Encode()
+		w, _ := New().wrap(want).Encode()
+		assert.Equal(t, string(w), string(b), ptr)
+	}
+
+	// objects and arrays are located without tracking
+	plain := mustJSON(`{"a":[{"b":{}},{"c":1}]}`)
+	jin, err := plain.Get("a", 0, "b").GetRelative("1+1/c")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, jin.Int())
+
+	// the result can be modified in place
+	jin, _ = tags.GetRelative("1/price")
+	jin.replace(5)
+	assert.Equal(t, 5, js.Get("items", 1, "price").Int())
+
+	for _, ptr := range []string{"", "01", "x", "1+", "1/price#", "1-01", "4", "1+1/name", "0#/x", "1/missing", "3#"} {
+		_, err := name.GetRelative(ptr)
+		assert.NotEqual(t, nil, err, ptr)
+	}
+}
@@ -0,0 +1,200 @@
+package simplejson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrPermission is wrapped by the errors of RestrictedJSON
+// for paths its policy does not allow
+var ErrPermission = errors.New("permission denied")
+
+// AccessPolicy lists the dotted path prefixes a RestrictedJSON allows,
+// "a.b" covering "a.b" and every path below it, the empty path the whole
+// document
+type AccessPolicy struct {
+	// Read lists the prefixes of the paths which can be read
+	Read []string
+	// Write lists the prefixes of the paths which can be written,
+	// and so read
+	Write []string
+	// Deny lists the prefixes of the paths which can neither be read nor
+	// written, overriding Read and Write
+	Deny []string
+}
+
+// RestrictedJSON gives access to the sections of a document allowed
+// by a policy, to expose it to untrusted code
+type RestrictedJSON struct {
+	j      *JSON
+	policy AccessPolicy
+}
+
+// Restricted returns a RestrictedJSON over `j` enforcing `policy`:
+//
+//	r := Restricted(js, AccessPolicy{Read: []string{""}, Write: []string{"plugins.acme"}, Deny: []string{"secrets"}})
+//	r.Set("plugins.acme.enabled", true) // ok
+//	r.Set("server.port", 80)            // simplejson: cannot write server.port: permission denied
+func Restricted(j *JSON, policy AccessPolicy) *RestrictedJSON {
+	return &RestrictedJSON{j: j.orMissing(), policy: policy}
+}
+
+// Get returns a copy of the value at the dotted `path`, without the values
+// below it denied by the policy, or a missing value if there is none
+func (r *RestrictedJSON) Get(path string) (*JSON, error) {
+	segments, branch, err := r.resolve("read", path)
+	if err != nil {
+		return nil, err
+	}
+	if !r.allowed(segments, r.policy.Read) && !r.allowed(segments, r.policy.Write) {
+		return nil, permissionError("read", path)
+	}
+	val, ok := lookup(r.j.data, branch)
+	if !ok {
+		return &JSON{}, nil
+	}
+	c := r.j.wrap(val).Clone()
+	c.data = prune(c.data, r.below(segments))
+	return c, nil
+}
+
+// Set sets the value at the dotted `path`, creating the objects leading
+// to it, numeric segments indexing existing arrays
+func (r *RestrictedJSON) Set(path string, val interface{}) error {
+	segments, branch, err := r.resolve("write", path)
+	if err != nil {
+		return err
+	}
+	if !r.allowed(segments, r.policy.Write) || len(r.below(segments)) > 0 {
+		return permissionError("write", path)
+	}
+	return r.j.setAtWith(branch, val, false)
+}
+
+// Del deletes the key at the dotted `path`, if present
+func (r *RestrictedJSON) Del(path string) error {
+	segments, branch, err := r.resolve("delete", path)
+	if err != nil {
+		return err
+	}
+	if len(segments) == 0 || !r.allowed(segments, r.policy.Write) || len(r.below(segments)) > 0 {
+		return permissionError("delete", path)
+	}
+	if r.j.IsFrozen() {
+		return ErrFrozen
+	}
+	if parent, ok := r.j.walkWith(branch[:len(branch)-1], false); ok {
+		parent.Del(segments[len(segments)-1])
+	}
+	return nil
+}
+
+// resolve converts the dotted `path` into the branch it addresses, keys
+// matching exactly, so the policy is checked against the values actually
+// accessed. Segments addressing array elements must be canonical indexes,
+// other spellings of an index are rejected
+func (r *RestrictedJSON) resolve(op, path string) ([]string, []interface{}, error) {
+	segments := splitPath(path)
+	branch := make([]interface{}, len(segments))
+	data := r.j.data
+	for i, s := range segments {
+		a, ok := data.([]interface{})
+		if !ok {
+			branch[i] = s
+			data, _ = getKey(data, s)
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 || strconv.Itoa(n) != s {
+			return nil, nil, fmt.Errorf("simplejson: cannot %s %s: invalid index %q", op, formatPath(stringsBranch(segments)), s)
+		}
+		branch[i] = n
+		data, _ = getIndex(a, n)
+	}
+	return segments, branch, nil
+}
+
+// allowed reports whether `segments` is below one of `prefixes`
+// and none of the denied ones
+func (r *RestrictedJSON) allowed(segments []string, prefixes []string) bool {
+	for _, deny := range r.policy.Deny {
+		if hasSegments(segments, splitPath(deny)) {
+			return false
+		}
+	}
+	for _, prefix := range prefixes {
+		if hasSegments(segments, splitPath(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// below returns the denied paths strictly below `segments`,
+// relative to it
+func (r *RestrictedJSON) below(segments []string) [][]string {
+	var out [][]string
+	for _, deny := range r.policy.Deny {
+		d := splitPath(deny)
+		if len(d) > len(segments) && hasSegments(d, segments) {
+			out = append(out, d[len(segments):])
+		}
+	}
+	return out
+}
+
+// hasSegments reports whether `path` starts with `prefix`
+func hasSegments(path, prefix []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i, s := range prefix {
+		if path[i] != s {
+			return false
+		}
+	}
+	return true
+}
+
+// prune removes the values at the relative paths `denied` from `data`,
+// numeric segments addressing elements of arrays
+func prune(data interface{}, denied [][]string) interface{} {
+	if len(denied) == 0 {
+		return data
+	}
+	drop := make(map[string]bool)
+	rest := make(map[string][][]string)
+	for _, d := range denied {
+		if len(d) == 1 {
+			drop[d[0]] = true
+		} else {
+			rest[d[0]] = append(rest[d[0]], d[1:])
+		}
+	}
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key := range drop {
+			delete(v, key)
+		}
+		for key, d := range rest {
+			if val, ok := v[key]; ok {
+				v[key] = prune(val, d)
+			}
+		}
+	case []interface{}:
+		a := v[:0]
+		for i, val := range v {
+			s := strconv.Itoa(i)
+			if !drop[s] {
+				a = append(a, prune(val, rest[s]))
+			}
+		}
+		return a
+	}
+	return data
+}
+
+func permissionError(op, path string) error {
+	return fmt.Errorf("simplejson: cannot %s %s: %w", op, formatPath(stringsBranch(splitPath(path))), ErrPermission)
+}
@@ -0,0 +1,98 @@
+package simplejson
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestRestricted(t *testing.T) {
+	js := mustJSON(`{"server":{"port":80,"tls":{"key":"k","cert":"c"}},"plugins":{"acme":{"on":false},"other":{}},"users":[{"name":"a","pw":"x"},{"name":"b"}]}`)
+	r := Restricted(js, AccessPolicy{
+		Read:  []string{"server", "users"},
+		Write: []string{"plugins.acme"},
+		Deny:  []string{"server.tls.key", "users.0"},
+	})
+
+	v, err := r.Get("server")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"port":80,"tls":{"cert":"c"}}`, string(mustEncode(t, v)))
+	v, err = r.Get("users")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `[{"name":"b"}]`, string(mustEncode(t, v)))
+	v, err = r.Get("plugins.acme.on")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, v.Bool(true))
+	v, err = r.Get("server.missing")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, v.Interface())
+
+	// copies are returned
+	v, _ = r.Get("server")
+	v.Set("port", 1)
+	assert.Equal(t, 80, js.Get("server", "port").Int())
+	assert.Equal(t, "k", js.Get("server", "tls", "key").String())
+
+	for _, path := range []string{"", "plugins", "plugins.other", "server.tls.key", "users.0.name"} {
+		_, err := r.Get(path)
+		assert.Equal(t, true, errors.Is(err, ErrPermission), path)
+	}
+	_, err = r.Get("server.tls.key")
+	assert.Equal(t, "simplejson: cannot read server.tls.key: permission denied", err.Error())
+
+	assert.Equal(t, nil, r.Set("plugins.acme.on", true))
+	assert.Equal(t, nil, r.Set("plugins.acme.opts.level", 2))
+	assert.Equal(t, nil, r.Del("plugins.acme.on"))
+	assert.Equal(t, `{"opts":{"level":2}}`, string(mustEncode(t, js.Get("plugins", "acme"))))
+
+	err = r.Set("server.port", 1)
+	assert.Equal(t, "simplejson: cannot write server.port: permission denied", err.Error())
+	assert.Equal(t, true, errors.Is(r.Set("plugins", 1), ErrPermission))
+	assert.Equal(t, true, errors.Is(r.Del("users.1"), ErrPermission))
+
+	// writes must not overwrite denied values
+	r = Restricted(js, AccessPolicy{Write: []string{""}, Deny: []string{"server.tls"}})
+	assert.Equal(t, true, errors.Is(r.Set("server", 1), ErrPermission))
+	assert.Equal(t, nil, r.Set("server.port", 443))
+	assert.Equal(t, 443, js.Get("server", "port").Int())
+
+	js.Freeze()
+	assert.Equal(t, ErrFrozen, r.Set("server.port", 1))
+	assert.Equal(t, ErrFrozen, r.Del("server.port"))
+}
+
+func TestRestrictedCanonicalPaths(t *testing.T) {
+	js := mustJSON(`{"users":[{"secret":"s0"},{"secret":"s1"}],"secrets":{"k":"v"},"open":{}}`)
+	r := Restricted(js, AccessPolicy{Read: []string{""}, Write: []string{""}, Deny: []string{"users.0", "secrets"}})
+
+	// other spellings of a denied index are rejected
+	for _, path := range []string{"users.00", "users.+0", "users.-2", "users. 0", "users.x"} {
+		v, err := r.Get(path)
+		assert.NotEqual(t, nil, err, path)
+		assert.Equal(t, (*JSON)(nil), v, path)
+		assert.NotEqual(t, nil, r.Set(path+".secret", "pwned"), path)
+		assert.NotEqual(t, nil, r.Del(path), path)
+	}
+	_, err := r.Get("users.00")
+	assert.Equal(t, `simplejson: cannot read users.00: invalid index "00"`, err.Error())
+	assert.Equal(t, "s0", js.Get("users", 0, "secret").String())
+	assert.Equal(t, 2, len(js.Get("users").Array()))
+
+	v, err := r.Get("users.1.secret")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "s1", v.String())
+
+	// keys are matched exactly, whatever the settings of the document
+	js.SetCaseInsensitive(true)
+	v, err = r.Get("SECRETS")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, v.Interface())
+	v, err = r.Get("SECRETS.k")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, v.Interface())
+	assert.Equal(t, nil, r.Set("SECRETS.k", "pwned"))
+	assert.Equal(t, "v", js.Get("secrets", "k").String())
+	assert.Equal(t, nil, r.Del("SECRETS.k"))
+	assert.Equal(t, "v", js.Get("secrets", "k").String())
+}
@@ -0,0 +1,57 @@
+package simplejson
+
+import "math/rand"
+
+// ReverseArray reverses the order of the elements of the `JSON` array in place
+func (j *JSON) ReverseArray() {
+	j.permute(func(a []interface{}) {
+		for i, k := 0, len(a)-1; i < k; i, k = i+1, k-1 {
+			a[i], a[k] = a[k], a[i]
+		}
+	})
+}
+
+// ReverseArrayAt is like ReverseArray, for the array found at `branch`
+func (j *JSON) ReverseArrayAt(branch ...interface{}) {
+	if jin, ok := j.CheckGet(branch...); ok {
+		jin.ReverseArray()
+	}
+}
+
+// ShuffleArray randomly reorders the elements of the `JSON` array in place,
+// using `r` as the source of randomness, or the default source if nil
+func (j *JSON) ShuffleArray(r *rand.Rand) {
+	shuffle := rand.Shuffle
+	if r != nil {
+		shuffle = r.Shuffle
+	}
+	j.permute(func(a []interface{}) {
+		shuffle(len(a), func(x, y int) {
+			a[x], a[y] = a[y], a[x]
+		})
+	})
+}
+
+// ShuffleArrayAt is like ShuffleArray, for the array found at `branch`
+func (j *JSON) ShuffleArrayAt(r *rand.Rand, branch ...interface{}) {
+	if jin, ok := j.CheckGet(branch...); ok {
+		jin.ShuffleArray(r)
+	}
+}
+
+// permute reorders the elements of the array `j` in place with `fn`
+func (j *JSON) permute(fn func(a []interface{})) {
+	j.beforeWrite()
+	a, ok := j.CheckArray()
+	if !ok {
+		return
+	}
+	var old []interface{}
+	if j.doc.observed() {
+		old = append([]interface{}{}, a...)
+	}
+	fn(a)
+	if old != nil {
+		j.notify(ChangeReplace, nil, old, a)
+	}
+}
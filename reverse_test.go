@@ -0,0 +1,48 @@
+package simplejson
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestReverseArray(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"a":[1,2,3,4],"b":{"c":["x","y","z"]}}`))
+	js.Get("a").ReverseArray()
+	js.ReverseArrayAt("b", "c")
+	js.ReverseArrayAt("missing")
+	b, _ := js.Encode()
+	assert.Equal(t, `{"a":[4,3,2,1],"b":{"c":["z","y","x"]}}`, string(b))
+
+	snap := js.Snapshot()
+	js.ReverseArrayAt("a")
+	assert.Equal(t, 4, snap.Get("a", 0).Int())
+	assert.Equal(t, 1, js.Get("a", 0).Int())
+
+	var changes []Change
+	js.OnChange(nil, func(c Change) { changes = append(changes, c) })
+	js.ReverseArrayAt("b", "c")
+	assert.Equal(t, 1, len(changes))
+	assert.Equal(t, []interface{}{"b", "c"}, changes[0].Path)
+	assert.Equal(t, "z", changes[0].Old.([]interface{})[0])
+}
+
+func TestShuffleArray(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"a":[1,2,3,4,5,6,7,8]}`))
+	js.ShuffleArrayAt(rand.New(rand.NewSource(1)), "a")
+	first := js.Get("a").Array()
+
+	js2, _ := NewJSON([]byte(`{"a":[1,2,3,4,5,6,7,8]}`))
+	js2.Get("a").ShuffleArray(rand.New(rand.NewSource(1)))
+	assert.Equal(t, first, js2.Get("a").Array())
+
+	js2.Get("a").ShuffleArray(nil)
+	ints := make([]int, 8)
+	for i := range ints {
+		ints[i] = js2.Get("a", i).Int()
+	}
+	sort.Ints(ints)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, ints)
+}
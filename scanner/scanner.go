@@ -0,0 +1,420 @@
+// Package scanner implements a low level, event driven reader of raw JSON bytes.
+//
+// Values are reported to a Handler as they are scanned, without ever building
+// a tree, which allows streaming aggregation over large documents:
+//
+//	type counter struct{ scanner.NopHandler; n int }
+//	func (c *counter) OnKey(key string) error { c.n++; return nil }
+//
+//	err := scanner.Scan(data, &c)
+//
+// The Scanner type also exposes the primitives used to skip over parts of a
+// document cheaply.
+//
+// Within simplejson the trees of documents are built by a Handler, for
+// NewJSON and UnmarshalJSON unless another codec is configured, and for the
+// partial decoders, Extract and ExtractRange. The Limits checks and type
+// sniffing are built on it too. Streams, as read by NewFromReader and the
+// decoders, go through the codec.
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ErrUnexpectedEnd is returned when the input ends in the middle of a value
+var ErrUnexpectedEnd = errors.New("scanner: unexpected end of JSON input")
+
+// Kind identifies the type of a scalar value
+type Kind int
+
+// Scalar value kinds
+const (
+	String Kind = iota
+	Number
+	Bool
+	Null
+)
+
+func (k Kind) String() string {
+	switch k {
+	case String:
+		return "string"
+	case Number:
+		return "number"
+	case Bool:
+		return "bool"
+	case Null:
+		return "null"
+	}
+	return "unknown"
+}
+
+// Handler receives scanning events.
+// Returning an error from any method stops the scan and is returned by it.
+type Handler interface {
+	OnObjectStart() error
+	OnObjectEnd() error
+	OnArrayStart() error
+	OnArrayEnd() error
+	OnKey(key string) error
+	// OnValue receives a scalar value, `raw` holds its JSON text and is only
+	// valid during the call. Unquote decodes strings.
+	OnValue(kind Kind, raw []byte) error
+}
+
+// NopHandler implements Handler ignoring every event,
+// it can be embedded to implement only the events of interest
+type NopHandler struct{}
+
+func (NopHandler) OnObjectStart() error                { return nil }
+func (NopHandler) OnObjectEnd() error                  { return nil }
+func (NopHandler) OnArrayStart() error                 { return nil }
+func (NopHandler) OnArrayEnd() error                   { return nil }
+func (NopHandler) OnKey(key string) error              { return nil }
+func (NopHandler) OnValue(kind Kind, raw []byte) error { return nil }
+
+// Scan reads the single JSON value in `data`, reporting it to `h`
+func Scan(data []byte, h Handler) error {
+	s := New(data)
+	if err := s.Walk(h); err != nil {
+		return err
+	}
+	return s.End()
+}
+
+// Unquote decodes the raw bytes of a JSON string
+func Unquote(raw []byte) (string, error) {
+	s := New(raw)
+	if err := s.skipString(); err != nil {
+		return "", err
+	}
+	if s.pos != len(raw) {
+		return "", fmt.Errorf("scanner: invalid string %q", raw)
+	}
+	return unquote(raw)
+}
+
+// unquote decodes the raw bytes of a valid JSON string, invalid UTF-8
+// being replaced by U+FFFD as done by encoding/json
+func unquote(raw []byte) (string, error) {
+	if bytes.IndexByte(raw, '\\') < 0 && utf8.Valid(raw) {
+		return string(raw[1 : len(raw)-1]), nil
+	}
+	var str string
+	err := json.Unmarshal(raw, &str)
+	return str, err
+}
+
+// Scanner walks raw JSON bytes without building values
+type Scanner struct {
+	data []byte
+	pos  int
+}
+
+// New returns a Scanner positioned at the start of `data`
+func New(data []byte) *Scanner {
+	return &Scanner{data: data}
+}
+
+// Pos returns the current offset within the input
+func (s *Scanner) Pos() int {
+	return s.pos
+}
+
+// End checks that only whitespace remains in the input
+func (s *Scanner) End() error {
+	if s.skipWS(); s.pos < len(s.data) {
+		return s.syntaxError("after top-level value")
+	}
+	return nil
+}
+
+func (s *Scanner) syntaxError(what string) error {
+	return fmt.Errorf("scanner: invalid character %q %s at offset %d", s.data[s.pos], what, s.pos)
+}
+
+func (s *Scanner) skipWS() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// Peek returns the next non whitespace byte without consuming it
+func (s *Scanner) Peek() (byte, error) {
+	s.skipWS()
+	if s.pos >= len(s.data) {
+		return 0, ErrUnexpectedEnd
+	}
+	return s.data[s.pos], nil
+}
+
+// expect consumes the next non whitespace byte, which must be `c`
+func (s *Scanner) expect(c byte) error {
+	b, err := s.Peek()
+	if err != nil {
+		return err
+	}
+	if b != c {
+		return s.syntaxError(fmt.Sprintf("looking for %q", c))
+	}
+	s.pos++
+	return nil
+}
+
+// skipString consumes a string starting at the current position
+func (s *Scanner) skipString() error {
+	if err := s.expect('"'); err != nil {
+		return err
+	}
+	for s.pos < len(s.data) {
+		switch c := s.data[s.pos]; {
+		case c == '"':
+			s.pos++
+			return nil
+		case c == '\\':
+			if err := s.skipEscape(); err != nil {
+				return err
+			}
+		case c < 0x20:
+			return s.syntaxError("in string literal")
+		default:
+			s.pos++
+		}
+	}
+	return ErrUnexpectedEnd
+}
+
+// skipEscape consumes an escape sequence within a string, which must be one
+// of those listed by RFC 8259, \u being followed by 4 hexadecimal digits
+func (s *Scanner) skipEscape() error {
+	s.pos++
+	if s.pos >= len(s.data) {
+		return ErrUnexpectedEnd
+	}
+	switch s.data[s.pos] {
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		s.pos++
+		return nil
+	case 'u':
+		s.pos++
+		for i := 0; i < 4; i++ {
+			if s.pos >= len(s.data) {
+				return ErrUnexpectedEnd
+			}
+			if !isHex(s.data[s.pos]) {
+				return s.syntaxError("in \\u hexadecimal character escape")
+			}
+			s.pos++
+		}
+		return nil
+	}
+	return s.syntaxError("in string escape code")
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// readKey consumes an object key and its colon
+func (s *Scanner) readKey() (string, error) {
+	s.skipWS()
+	start := s.pos
+	if err := s.skipString(); err != nil {
+		return "", err
+	}
+	key, err := unquote(s.data[start:s.pos])
+	if err != nil {
+		return "", err
+	}
+	return key, s.expect(':')
+}
+
+// SkipValue consumes a whole value and returns its raw bytes
+func (s *Scanner) SkipValue() ([]byte, error) {
+	b, err := s.Peek()
+	if err != nil {
+		return nil, err
+	}
+	start := s.pos
+	switch b {
+	case '"':
+		err = s.skipString()
+	case '{', '[':
+		err = s.skipContainer()
+	default:
+		err = s.skipLiteral()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.data[start:s.pos], nil
+}
+
+// skipContainer consumes an object or array by tracking nesting depth
+func (s *Scanner) skipContainer() error {
+	depth := 0
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '"':
+			if err := s.skipString(); err != nil {
+				return err
+			}
+			continue
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				s.pos++
+				return nil
+			}
+		}
+		s.pos++
+	}
+	return ErrUnexpectedEnd
+}
+
+func (s *Scanner) skipLiteral() error {
+	start := s.pos
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ',', '}', ']', ':', ' ', '\t', '\n', '\r':
+			if s.pos == start {
+				return s.syntaxError("looking for beginning of value")
+			}
+			return nil
+		}
+		s.pos++
+	}
+	if s.pos == start {
+		return ErrUnexpectedEnd
+	}
+	return nil
+}
+
+// Members iterates over the members of the object or array starting at the
+// current position, calling fn positioned at the start of each value.
+// `key` is empty for array elements, `index` counts members.
+// fn must consume the value, either through SkipValue, Walk or Members.
+func (s *Scanner) Members(fn func(key string, index int) error) error {
+	b, err := s.Peek()
+	if err != nil {
+		return err
+	}
+	if b != '{' && b != '[' {
+		return s.syntaxError("looking for beginning of object or array")
+	}
+	object := b == '{'
+	end := byte(']')
+	if object {
+		end = '}'
+	}
+	s.pos++
+
+	if b, err = s.Peek(); err != nil {
+		return err
+	}
+	if b == end {
+		s.pos++
+		return nil
+	}
+
+	for index := 0; ; index++ {
+		var key string
+		if object {
+			if key, err = s.readKey(); err != nil {
+				return err
+			}
+		}
+		if err = fn(key, index); err != nil {
+			return err
+		}
+		if b, err = s.Peek(); err != nil {
+			return err
+		}
+		switch b {
+		case ',':
+			s.pos++
+		case end:
+			s.pos++
+			return nil
+		default:
+			return s.syntaxError("after member")
+		}
+	}
+}
+
+// Walk consumes the value at the current position, reporting it to `h`
+func (s *Scanner) Walk(h Handler) error {
+	b, err := s.Peek()
+	if err != nil {
+		return err
+	}
+
+	switch b {
+	case '{':
+		if err = h.OnObjectStart(); err != nil {
+			return err
+		}
+		err = s.Members(func(key string, _ int) error {
+			if err := h.OnKey(key); err != nil {
+				return err
+			}
+			return s.Walk(h)
+		})
+		if err != nil {
+			return err
+		}
+		return h.OnObjectEnd()
+
+	case '[':
+		if err = h.OnArrayStart(); err != nil {
+			return err
+		}
+		err = s.Members(func(string, int) error {
+			return s.Walk(h)
+		})
+		if err != nil {
+			return err
+		}
+		return h.OnArrayEnd()
+	}
+
+	start := s.pos
+	raw, err := s.SkipValue()
+	if err != nil {
+		return err
+	}
+	kind, ok := literalKind(raw)
+	if !ok {
+		s.pos = start
+		return s.syntaxError("looking for beginning of value")
+	}
+	return h.OnValue(kind, raw)
+}
+
+func literalKind(raw []byte) (Kind, bool) {
+	switch string(raw) {
+	case "true", "false":
+		return Bool, true
+	case "null":
+		return Null, true
+	}
+	switch raw[0] {
+	case '"':
+		return String, true
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return Number, json.Valid(raw)
+	}
+	return 0, false
+}
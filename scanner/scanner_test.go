@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) OnObjectStart() error { r.events = append(r.events, "{"); return nil }
+func (r *recorder) OnObjectEnd() error   { r.events = append(r.events, "}"); return nil }
+func (r *recorder) OnArrayStart() error  { r.events = append(r.events, "["); return nil }
+func (r *recorder) OnArrayEnd() error    { r.events = append(r.events, "]"); return nil }
+func (r *recorder) OnKey(key string) error {
+	r.events = append(r.events, "key:"+key)
+	return nil
+}
+func (r *recorder) OnValue(kind Kind, raw []byte) error {
+	r.events = append(r.events, kind.String()+":"+string(raw))
+	return nil
+}
+
+func TestScan(t *testing.T) {
+	r := &recorder{}
+	err := Scan([]byte(` {"a": [1, "x\"y", true, null], "b!": {}, "c": []} `), r)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{
+		"{", "key:a", "[", "number:1", `string:"x\"y"`, "bool:true", "null:null", "]",
+		"key:b!", "{", "}", "key:c", "[", "]", "}",
+	}, r.events)
+}
+
+type summer struct {
+	NopHandler
+	sum float64
+}
+
+func (s *summer) OnValue(kind Kind, raw []byte) error {
+	if kind == Number {
+		f, err := strconv.ParseFloat(string(raw), 64)
+		s.sum += f
+		return err
+	}
+	return nil
+}
+
+func TestScanAggregate(t *testing.T) {
+	s := &summer{}
+	assert.Equal(t, nil, Scan([]byte(`[{"v": 1.5}, {"v": 2}, {"v": -0.5, "w": "3"}]`), s))
+	assert.Equal(t, 3.0, s.sum)
+}
+
+func TestScanErrors(t *testing.T) {
+	for _, raw := range []string{``, `{"a" 1}`, `[1 2]`, `[1,`, `{"a":tru}`, `"abc`, `[1] 2`, `01x`} {
+		err := Scan([]byte(raw), NopHandler{})
+		assert.NotEqual(t, nil, err, raw)
+	}
+}
+
+func TestSkipAndMembers(t *testing.T) {
+	s := New([]byte(`{"skip": {"a": "]}"}, "list": [10, 20, 30]}`))
+	var got []string
+	err := s.Members(func(key string, index int) error {
+		if key != "list" {
+			_, err := s.SkipValue()
+			return err
+		}
+		return s.Members(func(_ string, i int) error {
+			raw, err := s.SkipValue()
+			got = append(got, strconv.Itoa(i)+"="+string(raw))
+			return err
+		})
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, s.End())
+	assert.Equal(t, "0=10,1=20,2=30", strings.Join(got, ","))
+}
+
+func TestScanInvalidStrings(t *testing.T) {
+	for _, raw := range []string{
+		"\"a\x01\"", "\"a\tb\"", "\"a\nb\"", `"\x"`, `"\u12"`, `"\u12g4"`, `"\'"`, `"\`, `"\u00`,
+		"{\"a\x1f\": 1}", "[\"\x00\"]",
+	} {
+		assert.Equal(t, false, json.Valid([]byte(raw)), raw)
+		err := Scan([]byte(raw), NopHandler{})
+		assert.NotEqual(t, nil, err, raw)
+		_, err = Unquote([]byte(raw))
+		assert.NotEqual(t, nil, err, raw)
+	}
+	for _, raw := range []string{`"é\/\b\f\n\r\t\"\\"`, `"😀"`, "\"\x7f\xff\""} {
+		assert.Equal(t, nil, Scan([]byte(raw), NopHandler{}), raw)
+		var want string
+		assert.Equal(t, nil, json.Unmarshal([]byte(raw), &want))
+		got, err := Unquote([]byte(raw))
+		assert.Equal(t, nil, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := Unquote([]byte(`"a" `))
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,161 @@
+package simplejson
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Schema describes the shape of documents with a subset of JSON Schema,
+// it can be decoded from a JSON Schema document with encoding/json
+type Schema struct {
+	// Type is one of "object", "array", "string", "number", "integer",
+	// "boolean" or "null", any type is allowed if empty
+	Type string `json:"type,omitempty"`
+	// Nullable allows null in addition to Type
+	Nullable bool `json:"nullable,omitempty"`
+	// Enum lists the allowed values, if not empty
+	Enum []interface{} `json:"enum,omitempty"`
+	// Format refines strings, such as "date-time", "email" or "uuid"
+	Format string `json:"format,omitempty"`
+
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Maximum   *float64 `json:"maximum,omitempty"`
+	MinLength *int     `json:"minLength,omitempty"`
+	MaxLength *int     `json:"maxLength,omitempty"`
+
+	// Properties describes the values of objects by key
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	// Required lists the keys objects must hold
+	Required []string `json:"required,omitempty"`
+
+	// Items describes the elements of arrays
+	Items    *Schema `json:"items,omitempty"`
+	MinItems *int    `json:"minItems,omitempty"`
+	MaxItems *int    `json:"maxItems,omitempty"`
+}
+
+// InferSchema returns a schema describing the document: the keys of objects
+// are required, the elements of arrays are described by a single schema
+// merging theirs, where keys missing from some elements are optional and
+// nulls make values nullable. Formats of strings are detected.
+func (j *JSON) InferSchema() *Schema {
+	j = j.orMissing()
+	return inferSchema(j.data)
+}
+
+func inferSchema(data interface{}) *Schema {
+	switch v := data.(type) {
+	case nil:
+		return &Schema{Type: "null"}
+	case map[string]interface{}:
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema, len(v))}
+		for key, val := range v {
+			s.Properties[key] = inferSchema(val)
+		}
+		s.Required = sortedKeys(v)
+		return s
+	case []interface{}:
+		s := &Schema{Type: "array"}
+		for _, val := range v {
+			s.Items = mergeSchema(s.Items, inferSchema(val))
+		}
+		return s
+	case string:
+		return &Schema{Type: "string", Format: stringFormat(v)}
+	case bool:
+		return &Schema{Type: "boolean"}
+	}
+	if r, ok := numberRat(data); ok && r.IsInt() {
+		return &Schema{Type: "integer"}
+	}
+	if isNumber(data) {
+		return &Schema{Type: "number"}
+	}
+	return &Schema{}
+}
+
+// mergeSchema returns a schema describing the values of both `a` and `b`
+func mergeSchema(a, b *Schema) *Schema {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.Type == "null" && b.Type == "null":
+		return a
+	case a.Type == "null" || b.Type == "null":
+		s := *a
+		if a.Type == "null" {
+			s = *b
+		}
+		s.Nullable = true
+		return &s
+	}
+
+	s := &Schema{Type: a.Type, Nullable: a.Nullable || b.Nullable}
+	switch {
+	case a.Type == "object" && b.Type == "object":
+		s.Properties = make(map[string]*Schema)
+		for key, p := range a.Properties {
+			s.Properties[key] = mergeSchema(p, b.Properties[key])
+		}
+		for key, p := range b.Properties {
+			if _, ok := a.Properties[key]; !ok {
+				s.Properties[key] = p
+			}
+		}
+		inB := make(map[string]bool, len(b.Required))
+		for _, key := range b.Required {
+			inB[key] = true
+		}
+		for _, key := range a.Required {
+			if inB[key] {
+				s.Required = append(s.Required, key)
+			}
+		}
+	case a.Type == "array" && b.Type == "array":
+		s.Items = mergeSchema(a.Items, b.Items)
+	case a.Type == "string" && b.Type == "string":
+		if a.Format == b.Format {
+			s.Format = a.Format
+		}
+	case a.Type == b.Type:
+	case a.Type == "integer" && b.Type == "number" || a.Type == "number" && b.Type == "integer":
+		s.Type = "number"
+	default:
+		s.Type = ""
+	}
+	return s
+}
+
+var (
+	uuidPattern  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// stringFormat returns the format of `s` among those generated by
+// GenerateFromSchema, or "" if it has none
+func stringFormat(s string) string {
+	if _, err := time.Parse(time.RFC3339, s); err == nil {
+		return "date-time"
+	}
+	if _, err := time.Parse("2006-01-02", s); err == nil {
+		return "date"
+	}
+	switch {
+	case uuidPattern.MatchString(s):
+		return "uuid"
+	case emailPattern.MatchString(s):
+		return "email"
+	}
+	if ip := net.ParseIP(s); ip != nil && ip.To4() != nil && strings.Contains(s, ".") {
+		return "ipv4"
+	}
+	if u, err := url.Parse(s); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+		return "uri"
+	}
+	return ""
+}
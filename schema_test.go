@@ -0,0 +1,35 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestInferSchema(t *testing.T) {
+	js := mustJSON(`{
+		"id": 1,
+		"email": "a@example.com",
+		"items": [
+			{"sku": "x", "qty": 1, "at": "2020-01-02T03:04:05Z", "note": null},
+			{"sku": "y", "qty": 1.5, "at": "2021-01-02T03:04:05Z", "note": "n", "gift": true}
+		],
+		"empty": []
+	}`)
+	s := js.InferSchema()
+
+	assert.Equal(t, "object", s.Type)
+	assert.Equal(t, []string{"email", "empty", "id", "items"}, s.Required)
+	assert.Equal(t, "integer", s.Properties["id"].Type)
+	assert.Equal(t, "email", s.Properties["email"].Format)
+	assert.Equal(t, (*Schema)(nil), s.Properties["empty"].Items)
+
+	item := s.Properties["items"].Items
+	assert.Equal(t, []string{"at", "note", "qty", "sku"}, item.Required)
+	assert.Equal(t, "number", item.Properties["qty"].Type)
+	assert.Equal(t, "date-time", item.Properties["at"].Format)
+	assert.Equal(t, &Schema{Type: "string", Nullable: true}, item.Properties["note"])
+	assert.Equal(t, "boolean", item.Properties["gift"].Type)
+
+	assert.Equal(t, "", mustJSON(`[1, "a"]`).InferSchema().Items.Type)
+}
@@ -0,0 +1,69 @@
+package simplejson
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SetAll sets the values of `values` at their paths, made of keys separated
+// by `sep`, creating objects along them like SetPath, so that a flat store
+// seeds a document in one call:
+//
+//	js.SetAll(map[string]interface{}{"db.host": "localhost", "db.port": 5432}, ".")
+//
+// Every value is checked before any is set: if one cannot be converted or
+// stored, or a path is within the value of another, an error is returned
+// and the document is left unchanged. The empty path sets the whole document.
+func (j *JSON) SetAll(values map[string]interface{}, sep string) (err error) {
+	j = j.orMissing()
+	if sep == "" {
+		return errors.New("simplejson: SetAll with an empty separator")
+	}
+	if j.IsFrozen() {
+		return ErrFrozen
+	}
+
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	branches := make([][]string, len(paths))
+	converted := make([]interface{}, len(paths))
+	for i, path := range paths {
+		if path != "" {
+			branches[i] = strings.Split(path, sep)
+		}
+		branch := stringsBranch(branches[i])
+		val, err := convertValue(values[path])
+		if err != nil {
+			ce := err.(*ConvertError)
+			base, _ := j.location()
+			ce.Path = append(append(base, branch...), ce.Path...)
+			return ce
+		}
+		if err := j.strictError(val, branch...); err != nil {
+			return err
+		}
+		converted[i] = val
+	}
+	for i, branch := range branches {
+		if len(branch) > 0 && len(branches) > 1 && paths[0] == "" {
+			return fmt.Errorf("simplejson: SetAll paths %q and %q conflict", "", paths[i])
+		}
+		for n := 1; n < len(branch); n++ {
+			prefix := strings.Join(branch[:n], sep)
+			if _, ok := values[prefix]; ok {
+				return fmt.Errorf("simplejson: SetAll paths %q and %q conflict", prefix, paths[i])
+			}
+		}
+	}
+
+	tx := j.Begin()
+	for i, branch := range branches {
+		tx.SetPath(branch, converted[i])
+	}
+	return tx.Commit()
+}
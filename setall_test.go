@@ -0,0 +1,58 @@
+package simplejson
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestSetAll(t *testing.T) {
+	js := mustJSON(`{"db":{"host":"db1","user":"app"},"debug":false}`)
+	err := js.SetAll(map[string]interface{}{
+		"db/host":      "localhost",
+		"db/port":      5432,
+		"cache/ttl":    "5m",
+		"debug":        true,
+		"features/new": []string{"a"},
+	}, "/")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"cache":{"ttl":"5m"},"db":{"host":"localhost","port":5432,"user":"app"},"debug":true,"features":{"new":["a"]}}`,
+		string(mustEncode(t, js)))
+
+	// nothing is set when one of the values is rejected
+	js = mustJSON(`{"a":1}`)
+	js.StrictSet()
+	err = js.SetAll(map[string]interface{}{"a": 2, "b.c": math.NaN()}, ".")
+	assert.Equal(t, true, errors.Is(err, ErrUnsupportedValue))
+	assert.Equal(t, `simplejson: unsupported value at b.c: float64 NaN`, err.Error())
+	assert.Equal(t, `{"a":1}`, string(mustEncode(t, js)))
+
+	moneyType := reflect.TypeOf(money{})
+	RegisterConverter(moneyType, func(v interface{}) (interface{}, error) {
+		return nil, errors.New("no currency")
+	})
+	defer RegisterConverter(moneyType, nil)
+	err = js.SetAll(map[string]interface{}{"a": 2, "b.c": []money{{1}}}, ".")
+	assert.Equal(t, "simplejson: cannot convert simplejson.money at b.c[0]: no currency", err.Error())
+	assert.Equal(t, `{"a":1}`, string(mustEncode(t, js)))
+
+	err = js.SetAll(map[string]interface{}{"x": 2, "x.y": 3}, ".")
+	assert.Equal(t, `simplejson: SetAll paths "x" and "x.y" conflict`, err.Error())
+	err = js.SetAll(map[string]interface{}{"": 2, "x": 3}, ".")
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, `{"a":1}`, string(mustEncode(t, js)))
+
+	assert.NotEqual(t, nil, js.SetAll(map[string]interface{}{"a": 2}, ""))
+	js.Freeze()
+	assert.Equal(t, ErrFrozen, js.SetAll(map[string]interface{}{"a": 2}, "."))
+
+	// views set within their document
+	js = mustJSON(`{"cfg":{"a":1}}`)
+	assert.Equal(t, nil, js.Get("cfg").SetAll(map[string]interface{}{"b.c": "x"}, "."))
+	assert.Equal(t, `{"cfg":{"a":1,"b":{"c":"x"}}}`, string(mustEncode(t, js)))
+	assert.Equal(t, nil, js.Get("cfg").SetAll(map[string]interface{}{"": 2}, "."))
+	assert.Equal(t, `{"cfg":2}`, string(mustEncode(t, js)))
+}
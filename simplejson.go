@@ -1,8 +1,9 @@
 package simplejson
 
 import (
-	"encoding/json"
-	"log"
+	"errors"
+	"io"
+	"sync/atomic"
 )
 
 // returns the current implementation version
@@ -10,15 +11,193 @@ func Version() string {
 	return "0.5.1"
 }
 
+// JSON wraps an arbitrary decoded JSON value.
+//
+// A JSON, and every JSON obtained from it through Get, shares the same
+// underlying maps and arrays. It is not safe for concurrent use when any
+// goroutine modifies the document, use SyncJSON to share a mutable document.
+//
+// Methods may be called on a nil *JSON, which behaves as a missing value.
 type JSON struct {
 	data interface{}
+	doc  *document
+	// parent and key locate the value within its document, they are only
+	// set on views obtained while the document tracks them
+	parent *JSON
+	key    interface{}
+	// base and rel locate views obtained by a single lookup otherwise, `rel`
+	// being the branch leading to the value from `base`, see locate
+	base *JSON
+	rel  []interface{}
+}
+
+// document holds the state shared by a document and the views obtained from it
+type document struct {
+	root   *JSON
+	frozen bool
+	// track makes views remember their parent and key within the document
+	track bool
+	// shared is set once the containers of the document may be referenced
+	// by a snapshot, `owned` then holds the containers copied since, by
+	// identity, keeping them alive, see addOwned
+	shared   bool
+	owned    map[uintptr]interface{}
+	ownedMax int
+	// watchers are notified of modifications
+	watchers []*watcher
+	// recording appends modifications to changelog
+	recording bool
+	changelog []Change
+	// undone holds the modifications reverted by Undo
+	undone []Change
+	// foldKeys makes lookups match keys case insensitively
+	foldKeys bool
+	// origins holds where each value of documents built by Load was set
+	origins map[string]Provenance
+	layers  []Source
+	// sortKeys makes every encoding of the document sort keys
+	sortKeys bool
+	// strict makes modifications check the values they store can be encoded
+	strict bool
+	// defaults holds the values of missing paths returned by Get
+	defaults map[string]interface{}
+	// copyOnGet makes Map and Array return copies of containers
+	copyOnGet bool
+	// pooling is set on documents returned by Acquire, `pooled` then holds
+	// the maps taken from the pool by the document, by identity, keeping them
+	// alive, see Reset
+	pooling bool
+	pooled  map[uintptr]interface{}
+	// tracer receives lookup misses, type mismatches and modifications
+	tracer Tracer
+	// quota bounds the size of the document
+	quota *quota
+	// err holds the last error recorded by accessors, see Err
+	err atomic.Value
+}
+
+// document returns the state of the document `j` belongs to,
+// `j` becomes the root of a new one if it had none
+func (j *JSON) document() *document {
+	if j.doc == nil {
+		j.doc = &document{root: j}
+	}
+	return j.doc
+}
+
+// errNilUnmarshal is returned by UnmarshalJSON on a nil *JSON
+var errNilUnmarshal = errors.New("simplejson: UnmarshalJSON on nil pointer")
+
+// orMissing returns `j`, or a detached missing value if `j` is nil,
+// so methods called on a nil *JSON behave as on a missing value
+func (j *JSON) orMissing() *JSON {
+	if j == nil {
+		return &JSON{}
+	}
+	return j
+}
+
+// wrap returns a view of `data` sharing the document state of `j`
+func (j *JSON) wrap(data interface{}) *JSON {
+	if j == nil {
+		return &JSON{data: data}
+	}
+	return &JSON{data: data, doc: j.doc}
+}
+
+// child returns a view of `data`, found at `key` within `j`
+func (j *JSON) child(data interface{}, key interface{}) *JSON {
+	c := &JSON{data: data, doc: j.doc}
+	if j.doc != nil {
+		c.parent, c.key = j, key
+	}
+	return c
+}
+
+// replace sets the value of `j`, updating its parent container
+// when `j` is a view located within its document
+func (j *JSON) replace(data interface{}) error {
+	if j == nil {
+		return nil
+	}
+	old := j.data
+	j.setData(data)
+	if err := j.notify(ChangeReplace, nil, old, data); err != nil {
+		// the modification was reverted through the parent container
+		if parent := j.parentData(); parent != nil {
+			j.data, _ = lookup(parent, []interface{}{j.key})
+		}
+		return err
+	}
+	return nil
+}
+
+// setData is like replace, without notifying the modification
+func (j *JSON) setData(data interface{}) {
+	j.beforeWrite()
+	parent := j.parentData()
+	j.data = data
+	switch c := parent.(type) {
+	case map[string]interface{}:
+		c[j.key.(string)] = data
+	case []interface{}:
+		c[j.key.(int)] = data
+	}
+}
+
+// parentData returns the container holding `j`, if it is known
+func (j *JSON) parentData() interface{} {
+	if j.parent == nil && (j.doc == nil || j == j.doc.root || !j.doc.locate(j)) {
+		return nil
+	}
+	if _, ok := lookup(j.parent.data, []interface{}{j.key}); !ok {
+		return nil
+	}
+	return j.parent.data
+}
+
+// beforeWrite must be called by every method modifying the document in place
+func (j *JSON) beforeWrite() {
+	j.checkWritable()
+	j.own()
 }
 
 // NewJson returns a pointer to a new `JSON` object
 // after unmarshaling `body` bytes
 func NewJSON(body []byte) (*JSON, error) {
+	return NewJSONWith(body)
+}
+
+// NewJSONWith is like NewJSON, decoding with `opts`
+func NewJSONWith(body []byte, opts ...DecodeOption) (*JSON, error) {
+	m := startDecode(len(body))
+	j, err := newJSON(body, opts)
+	m.done(j, err)
+	return j, err
+}
+
+// NewFromReader returns a *JSON by decoding from an io.Reader
+func NewFromReader(r io.Reader) (*JSON, error) {
+	return NewFromReaderWith(r)
+}
+
+func newJSON(body []byte, opts []DecodeOption) (*JSON, error) {
+	c := newDecodeConfig(opts)
+	if c.maxBytes > 0 && int64(len(body)) > c.maxBytes {
+		return nil, ErrTooLarge
+	}
+	if err := c.check(body); err != nil {
+		return nil, err
+	}
 	j := new(JSON)
+	j.document()
 	err := j.UnmarshalJSON(body)
+	if err == nil && c.progress != nil {
+		c.progress(int64(len(body)))
+	}
+	if err == nil {
+		err = c.apply(j)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -27,13 +206,18 @@ func NewJSON(body []byte) (*JSON, error) {
 
 // New returns a pointer to a new, empty `JSON` object
 func New() *JSON {
-	return &JSON{
+	j := &JSON{
 		data: make(map[string]interface{}),
 	}
+	j.document()
+	return j
 }
 
 // Interface returns the underlying data
 func (j *JSON) Interface() interface{} {
+	if j == nil {
+		return nil
+	}
 	return j.data
 }
 
@@ -44,36 +228,76 @@ func (j *JSON) Encode() ([]byte, error) {
 
 // EncodePretty returns its marshaled data as `[]byte` with indentation
 func (j *JSON) EncodePretty() ([]byte, error) {
-	return json.MarshalIndent(&j.data, "", "  ")
+	return j.EncodePrettyIndent("", "  ")
+}
+
+// EncodePrettyIndent is like EncodePretty, each line starting with `prefix`
+// followed by one copy of `indent` per nesting level
+func (j *JSON) EncodePrettyIndent(prefix, indent string) ([]byte, error) {
+	return j.EncodeWith(Indent(prefix, indent))
 }
 
 // Implements the json.Marshaler interface.
 func (j *JSON) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&j.data)
+	return j.EncodeWith()
 }
 
 // Set modifies `JSON` map by `key` and `value`
 // Useful for changing single key/value in a `JSON` object easily.
 func (j *JSON) Set(key string, val interface{}) {
+	j.set(key, val)
+}
+
+// set is like Set, returning the error of modifications exceeding the quota
+func (j *JSON) set(key string, val interface{}) error {
+	j.beforeWrite()
 	m, ok := j.CheckMap()
 	if !ok {
-		return
+		j.traceMismatch("object")
+		return nil
 	}
+	val = j.storable(val, key)
+	old, existed := m[key]
 	m[key] = val
+	return j.notify(setOp(existed), []interface{}{key}, old, val)
 }
 
 // SetPath modifies `JSON`, recursively checking/creating map keys for the supplied path,
 // and then finally writing in the value
 func (j *JSON) SetPath(branch []string, val interface{}) {
+	j = j.orMissing()
+	j.beforeWrite()
+	val = j.storable(val, stringsBranch(branch)...)
 	if len(branch) == 0 {
+		old := j.data
 		j.data = val
+		if j.doc == nil || j == j.doc.root {
+			j.notify(ChangeReplace, nil, old, val)
+		}
 		return
 	}
+	if j.doc.observed() {
+		// report the change at the topmost key created or replaced
+		path := stringsBranch(branch)
+		depth := len(path)
+		for i := range path[:len(path)-1] {
+			v, ok := lookup(j.data, path[:i+1])
+			if _, isMap := v.(map[string]interface{}); !ok || !isMap {
+				depth = i + 1
+				break
+			}
+		}
+		old, existed := lookup(j.data, path[:depth])
+		defer func() {
+			val, _ := lookup(j.data, path[:depth])
+			j.notify(setOp(existed), path[:depth], old, val)
+		}()
+	}
 
 	// in order to insert our branch, we need map[string]interface{}
 	if _, ok := (j.data).(map[string]interface{}); !ok {
 		// have to replace with something suitable
-		j.data = make(map[string]interface{})
+		j.data = j.acquireMap()
 	}
 	curr := j.data.(map[string]interface{})
 
@@ -81,7 +305,7 @@ func (j *JSON) SetPath(branch []string, val interface{}) {
 		b := branch[i]
 		// key exists?
 		if _, ok := curr[b]; !ok {
-			n := make(map[string]interface{})
+			n := j.acquireMap()
 			curr[b] = n
 			curr = n
 			continue
@@ -90,8 +314,10 @@ func (j *JSON) SetPath(branch []string, val interface{}) {
 		// make sure the value is the right sort of thing
 		if _, ok := curr[b].(map[string]interface{}); !ok {
 			// have to replace with something suitable
-			n := make(map[string]interface{})
+			n := j.acquireMap()
 			curr[b] = n
+		} else {
+			curr[b] = j.writable(curr[b])
 		}
 
 		curr = curr[b].(map[string]interface{})
@@ -103,34 +329,36 @@ func (j *JSON) SetPath(branch []string, val interface{}) {
 
 // Del modifies `JSON` map by deleting `key` if it is present.
 func (j *JSON) Del(key string) {
+	j.beforeWrite()
 	m, ok := j.CheckMap()
 	if !ok {
+		j.traceMismatch("object")
 		return
 	}
+	old, existed := m[key]
 	delete(m, key)
+	if existed {
+		j.notify(ChangeRemove, []interface{}{key}, old, nil)
+	}
 }
 
-// getKey returns a pointer to a new `JSON` object
-// for `key` in its `map` representation
+// getKey returns the value for `key` in the `map` representation of `data`
 // and a bool identifying success or failure
-func (j *JSON) getKey(key string) (*JSON, bool) {
-	m, ok := j.CheckMap()
-	if ok {
-		if val, ok := m[key]; ok {
-			return &JSON{val}, true
-		}
+func getKey(data interface{}, key string) (interface{}, bool) {
+	if m, ok := data.(map[string]interface{}); ok {
+		val, ok := m[key]
+		return val, ok
 	}
 	return nil, false
 }
 
-// getIndex returns a pointer to a new `JSON` object
-// for `index` in its `array` representation
-// and a bool identifying success or failure
-func (j *JSON) getIndex(index int) (*JSON, bool) {
-	a, ok := j.CheckArray()
-	if ok {
-		if len(a) > index {
-			return &JSON{a[index]}, true
+// getIndex returns the value for `index` in the `array` representation of `data`
+// and a bool identifying success or failure,
+// negative indexes count back from the end of the array
+func getIndex(data interface{}, index int) (interface{}, bool) {
+	if a, ok := data.([]interface{}); ok {
+		if normalizeIndex(&index, len(a)) {
+			return a[index], true
 		}
 	}
 	return nil, false
@@ -140,6 +368,9 @@ func (j *JSON) getIndex(index int) (*JSON, bool) {
 func (j *JSON) Keys() []string {
 	m, ok := j.CheckMap()
 	if ok {
+		if j.doc != nil && j.doc.sortKeys {
+			return sortedKeys(m)
+		}
 		keys := []string{}
 		for key := range m {
 			keys = append(keys, key)
@@ -168,23 +399,84 @@ func (j *JSON) Get(branch ...interface{}) *JSON {
 	if ok {
 		return jin
 	}
-	return &JSON{nil}
+	if def, ok := j.defaultAt(branch); ok {
+		return j.wrap(def)
+	}
+	return j.wrap(nil)
 }
 
 // CheckGet is like Get, except it also returns a bool
 // indicating whenever the branch was found or not
 // the JSON pointer mai be nil
 //
+// the branch is walked over the raw data, only the final value is wrapped
+// in a new JSON remembering the branch, so lookups cost two allocations
+// regardless of depth
+//
 //   newJs, ok := js.Get("top_level", "entries", 3, "dict")
 func (j *JSON) CheckGet(branch ...interface{}) (*JSON, bool) {
+	if j == nil {
+		return nil, false
+	}
+	if len(branch) == 0 {
+		return j, true
+	}
+	if j.doc != nil && (j.doc.track || j.doc.foldKeys) {
+		jin, ok := j.walk(branch)
+		if !ok {
+			j.traceMiss(branch)
+		}
+		return jin, ok
+	}
+	data, ok := lookup(j.data, branch)
+	if !ok {
+		return nil, false
+	}
+	jin := j.wrap(data)
+	if j.doc != nil {
+		jin.base, jin.rel = j, append([]interface{}(nil), branch...)
+	}
+	return jin, true
+}
+
+// walk is like CheckGet, but creates a view for every step of the branch,
+// so the resulting view can be located within the document
+func (j *JSON) walk(branch []interface{}) (*JSON, bool) {
+	return j.walkWith(branch, j.doc != nil && j.doc.foldKeys)
+}
+
+// walkWith is like walk, matching keys case insensitively if `fold` is set
+func (j *JSON) walkWith(branch []interface{}, fold bool) (*JSON, bool) {
 	jin := j
+	for _, p := range branch {
+		data, ok := lookup(jin.data, []interface{}{p})
+		switch k := p.(type) {
+		case string:
+			if !ok && fold {
+				p, data, ok = getKeyFold(jin.data, k)
+			}
+		case int:
+			if a, isArray := jin.data.([]interface{}); isArray && k < 0 {
+				p = k + len(a)
+			}
+		}
+		if !ok {
+			return nil, false
+		}
+		jin = &JSON{data: data, doc: j.doc, parent: jin, key: p}
+	}
+	return jin, true
+}
+
+// lookup walks `branch` over `data` and returns the value found at its end
+func lookup(data interface{}, branch []interface{}) (interface{}, bool) {
 	var ok bool
 	for _, p := range branch {
-		switch p.(type) {
+		switch p := p.(type) {
 		case string:
-			jin, ok = jin.getKey(p.(string))
+			data, ok = getKey(data, p)
 		case int:
-			jin, ok = jin.getIndex(p.(int))
+			data, ok = getIndex(data, p)
 		default:
 			ok = false
 		}
@@ -192,7 +484,7 @@ func (j *JSON) CheckGet(branch ...interface{}) (*JSON, bool) {
 			return nil, false
 		}
 	}
-	return jin, true
+	return data, true
 }
 
 // CheckJSONMap returns a copy of a JSON map, but with values as Jsons
@@ -203,7 +495,7 @@ func (j *JSON) CheckJSONMap() (map[string]*JSON, bool) {
 	}
 	jm := make(map[string]*JSON)
 	for key, val := range m {
-		jm[key] = &JSON{val}
+		jm[key] = j.child(val, key)
 	}
 	return jm, true
 }
@@ -216,13 +508,16 @@ func (j *JSON) CheckJSONArray() ([]*JSON, bool) {
 	}
 	ja := make([]*JSON, len(a))
 	for key, val := range a {
-		ja[key] = &JSON{val}
+		ja[key] = j.child(val, key)
 	}
 	return ja, true
 }
 
 // CheckMap type asserts to `map`
 func (j *JSON) CheckMap() (map[string]interface{}, bool) {
+	if j == nil {
+		return nil, false
+	}
 	if m, ok := (j.data).(map[string]interface{}); ok {
 		return m, true
 	}
@@ -231,6 +526,9 @@ func (j *JSON) CheckMap() (map[string]interface{}, bool) {
 
 // CheckArray type asserts to an `array`
 func (j *JSON) CheckArray() ([]interface{}, bool) {
+	if j == nil {
+		return nil, false
+	}
 	if a, ok := (j.data).([]interface{}); ok {
 		return a, true
 	}
@@ -239,6 +537,9 @@ func (j *JSON) CheckArray() ([]interface{}, bool) {
 
 // CheckBool type asserts to `bool`
 func (j *JSON) CheckBool() (bool, bool) {
+	if j == nil {
+		return false, false
+	}
 	if s, ok := (j.data).(bool); ok {
 		return s, true
 	}
@@ -247,6 +548,9 @@ func (j *JSON) CheckBool() (bool, bool) {
 
 // CheckString type asserts to `string`
 func (j *JSON) CheckString() (string, bool) {
+	if j == nil {
+		return "", false
+	}
 	if s, ok := (j.data).(string); ok {
 		return s, true
 	}
@@ -262,7 +566,8 @@ func (j *JSON) JSONArray(args ...[]*JSON) []*JSON {
 	case 1:
 		def = args[0]
 	default:
-		log.Panicf("JSONArray() received too many arguments %d", len(args))
+		j.extraArgs("JSONArray", len(args))
+		def = args[0]
 	}
 
 	a, ok := j.CheckJSONArray()
@@ -282,7 +587,8 @@ func (j *JSON) JSONMap(args ...map[string]*JSON) map[string]*JSON {
 	case 1:
 		def = args[0]
 	default:
-		log.Panicf("JSONMap() received too many arguments %d", len(args))
+		j.extraArgs("JSONMap", len(args))
+		def = args[0]
 	}
 
 	a, ok := j.CheckJSONMap()
@@ -307,14 +613,16 @@ func (j *JSON) Array(args ...[]interface{}) []interface{} {
 	case 1:
 		def = args[0]
 	default:
-		log.Panicf("Array() received too many arguments %d", len(args))
+		j.extraArgs("Array", len(args))
+		def = args[0]
 	}
 
 	a, ok := j.CheckArray()
 	if ok {
-		return a
+		return j.copied(a).([]interface{})
 	}
 
+	j.traceMismatch("array")
 	return def
 }
 
@@ -332,14 +640,16 @@ func (j *JSON) Map(args ...map[string]interface{}) map[string]interface{} {
 	case 1:
 		def = args[0]
 	default:
-		log.Panicf("Map() received too many arguments %d", len(args))
+		j.extraArgs("Map", len(args))
+		def = args[0]
 	}
 
 	a, ok := j.CheckMap()
 	if ok {
-		return a
+		return j.copied(a).(map[string]interface{})
 	}
 
+	j.traceMismatch("object")
 	return def
 }
 
@@ -355,7 +665,8 @@ func (j *JSON) String(args ...string) string {
 	case 1:
 		def = args[0]
 	default:
-		log.Panicf("String() received too many arguments %d", len(args))
+		j.extraArgs("String", len(args))
+		def = args[0]
 	}
 
 	s, ok := j.CheckString()
@@ -363,6 +674,7 @@ func (j *JSON) String(args ...string) string {
 		return s
 	}
 
+	j.traceMismatch("string")
 	return def
 }
 
@@ -378,7 +690,8 @@ func (j *JSON) Int(args ...int) int {
 	case 1:
 		def = args[0]
 	default:
-		log.Panicf("Int() received too many arguments %d", len(args))
+		j.extraArgs("Int", len(args))
+		def = args[0]
 	}
 
 	i, ok := j.CheckInt()
@@ -386,6 +699,7 @@ func (j *JSON) Int(args ...int) int {
 		return i
 	}
 
+	j.traceMismatch("number")
 	return def
 }
 
@@ -401,7 +715,8 @@ func (j *JSON) Float64(args ...float64) float64 {
 	case 1:
 		def = args[0]
 	default:
-		log.Panicf("Float64() received too many arguments %d", len(args))
+		j.extraArgs("Float64", len(args))
+		def = args[0]
 	}
 
 	f, ok := j.CheckFloat64()
@@ -409,6 +724,7 @@ func (j *JSON) Float64(args ...float64) float64 {
 		return f
 	}
 
+	j.traceMismatch("number")
 	return def
 }
 
@@ -424,7 +740,8 @@ func (j *JSON) Bool(args ...bool) bool {
 	case 1:
 		def = args[0]
 	default:
-		log.Panicf("Bool() received too many arguments %d", len(args))
+		j.extraArgs("Bool", len(args))
+		def = args[0]
 	}
 
 	b, ok := j.CheckBool()
@@ -432,6 +749,7 @@ func (j *JSON) Bool(args ...bool) bool {
 		return b
 	}
 
+	j.traceMismatch("bool")
 	return def
 }
 
@@ -447,7 +765,8 @@ func (j *JSON) Int64(args ...int64) int64 {
 	case 1:
 		def = args[0]
 	default:
-		log.Panicf("Int64() received too many arguments %d", len(args))
+		j.extraArgs("Int64", len(args))
+		def = args[0]
 	}
 
 	i, ok := j.CheckInt64()
@@ -455,6 +774,7 @@ func (j *JSON) Int64(args ...int64) int64 {
 		return i
 	}
 
+	j.traceMismatch("number")
 	return def
 }
 
@@ -470,7 +790,8 @@ func (j *JSON) Uint64(args ...uint64) uint64 {
 	case 1:
 		def = args[0]
 	default:
-		log.Panicf("Uint64() received too many arguments %d", len(args))
+		j.extraArgs("Uint64", len(args))
+		def = args[0]
 	}
 
 	i, ok := j.CheckUint64()
@@ -478,5 +799,22 @@ func (j *JSON) Uint64(args ...uint64) uint64 {
 		return i
 	}
 
+	j.traceMismatch("number")
 	return def
 }
+
+// Or returns `j` unless its value is missing or null, in which case it returns
+// `def` wrapped in a `JSON`, keeping chained lookups alive:
+//
+//	js.Get("a").Or(map[string]interface{}{"b": 1}).Get("b").Int()
+//
+// `def` may itself be a *JSON. The default is not added to the document.
+func (j *JSON) Or(def interface{}) *JSON {
+	if j.Interface() != nil {
+		return j
+	}
+	if d, ok := def.(*JSON); ok {
+		return d
+	}
+	return j.wrap(def)
+}
@@ -3,26 +3,42 @@
 package simplejson
 
 import (
-	"encoding/json"
 	"io"
 	"reflect"
 )
 
-// NewFromReader returns a *JSON by decoding from an io.Reader
-func NewFromReader(r io.Reader) (*JSON, error) {
+// NewFromReaderWith is like NewFromReader, decoding with `opts`
+func NewFromReaderWith(r io.Reader, opts ...DecodeOption) (*JSON, error) {
+	m := startDecode(0)
+	c := newDecodeConfig(opts)
 	j := new(JSON)
-	dec := json.NewDecoder(r)
-	err := dec.Decode(&j.data)
+	j.document()
+	dec := codec.NewDecoder(c.input(m.reader(r)))
+	err := c.decode(dec, &j.data)
+	if err == nil {
+		err = c.apply(j)
+	}
+	m.done(j, err)
 	return j, err
 }
 
 // Implements the json.Unmarshaler interface.
 func (j *JSON) UnmarshalJSON(p []byte) error {
-	return json.Unmarshal(p, &j.data)
+	if j == nil {
+		return errNilUnmarshal
+	}
+	if j.IsFrozen() {
+		return ErrFrozen
+	}
+	j.document()
+	return codec.Unmarshal(p, &j.data)
 }
 
 // CheckFloat64 coerces into a float64
 func (j *JSON) CheckFloat64() (float64, bool) {
+	if j == nil {
+		return 0, false
+	}
 	switch j.data.(type) {
 	case float32, float64:
 		return reflect.ValueOf(j.data).Float(), true
@@ -36,6 +52,9 @@ func (j *JSON) CheckFloat64() (float64, bool) {
 
 // CheckInt coerces into an int
 func (j *JSON) CheckInt() (int, bool) {
+	if j == nil {
+		return 0, false
+	}
 	switch j.data.(type) {
 	case float32, float64:
 		return int(reflect.ValueOf(j.data).Float()), true
@@ -49,6 +68,9 @@ func (j *JSON) CheckInt() (int, bool) {
 
 // CheckInt64 coerces into an int64
 func (j *JSON) CheckInt64() (int64, bool) {
+	if j == nil {
+		return 0, false
+	}
 	switch j.data.(type) {
 	case float32, float64:
 		return int64(reflect.ValueOf(j.data).Float()), true
@@ -62,6 +84,9 @@ func (j *JSON) CheckInt64() (int64, bool) {
 
 // CheckUint64 coerces into an uint64
 func (j *JSON) CheckUint64() (uint64, bool) {
+	if j == nil {
+		return 0, false
+	}
 	switch j.data.(type) {
 	case float32, float64:
 		return uint64(reflect.ValueOf(j.data).Float()), true
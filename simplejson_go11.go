@@ -11,23 +11,50 @@ import (
 )
 
 // Implements the json.Unmarshaler interface.
+// The tree is built by the scanner package, unless a codec other than
+// StdCodec is configured with SetCodec.
 func (j *JSON) UnmarshalJSON(p []byte) error {
-	dec := json.NewDecoder(bytes.NewBuffer(p))
+	if j == nil {
+		return errNilUnmarshal
+	}
+	if j.IsFrozen() {
+		return ErrFrozen
+	}
+	j.document()
+	if _, std := codec.(StdCodec); std {
+		data, err := buildTree(p)
+		if err != nil {
+			return err
+		}
+		j.data = data
+		return nil
+	}
+	dec := codec.NewDecoder(bytes.NewBuffer(p))
 	dec.UseNumber()
 	return dec.Decode(&j.data)
 }
 
-// NewFromReader returns a *JSON by decoding from an io.Reader
-func NewFromReader(r io.Reader) (*JSON, error) {
+// NewFromReaderWith is like NewFromReader, decoding with `opts`
+func NewFromReaderWith(r io.Reader, opts ...DecodeOption) (*JSON, error) {
+	m := startDecode(0)
+	c := newDecodeConfig(opts)
 	j := new(JSON)
-	dec := json.NewDecoder(r)
+	j.document()
+	dec := codec.NewDecoder(c.input(m.reader(r)))
 	dec.UseNumber()
-	err := dec.Decode(&j.data)
+	err := c.decode(dec, &j.data)
+	if err == nil {
+		err = c.apply(j)
+	}
+	m.done(j, err)
 	return j, err
 }
 
 // CheckFloat64 coerces into a float64
 func (j *JSON) CheckFloat64() (float64, bool) {
+	if j == nil {
+		return 0, false
+	}
 	switch j.data.(type) {
 	case json.Number:
 		nr, err := j.data.(json.Number).Float64()
@@ -44,6 +71,9 @@ func (j *JSON) CheckFloat64() (float64, bool) {
 
 // CheckInt coerces into an int
 func (j *JSON) CheckInt() (int, bool) {
+	if j == nil {
+		return 0, false
+	}
 	switch j.data.(type) {
 	case json.Number:
 		nr, err := j.data.(json.Number).Int64()
@@ -60,6 +90,9 @@ func (j *JSON) CheckInt() (int, bool) {
 
 // CheckInt64 coerces into an int64
 func (j *JSON) CheckInt64() (int64, bool) {
+	if j == nil {
+		return 0, false
+	}
 	switch j.data.(type) {
 	case json.Number:
 		nr, err := j.data.(json.Number).Int64()
@@ -76,6 +109,9 @@ func (j *JSON) CheckInt64() (int64, bool) {
 
 // CheckUint64 coerces into an uint64
 func (j *JSON) CheckUint64() (uint64, bool) {
+	if j == nil {
+		return 0, false
+	}
 	switch j.data.(type) {
 	case json.Number:
 		nr, err := strconv.ParseUint(j.data.(json.Number).String(), 10, 64)
@@ -255,3 +255,43 @@ func TestPathWillOverwriteExisting(t *testing.T) {
 	assert.Equal(t, true, ok)
 	assert.Equal(t, "bar", s)
 }
+
+func TestGetAllocations(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"b":[{"c":{"d":"deep"}}]}}`))
+	assert.Equal(t, nil, err)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		js.Get("a", "b", 0, "c", "d").String()
+	})
+	assert.Equal(t, 2.0, allocs)
+
+	_, ok := js.CheckGet("a", "b", -2)
+	assert.Equal(t, false, ok)
+}
+
+func TestOr(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"b":1},"n":null}`))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, 1, js.Get("a").Or(map[string]interface{}{"b": 2}).Get("b").Int())
+	assert.Equal(t, 2, js.Get("x").Or(map[string]interface{}{"b": 2}).Get("b").Int())
+	assert.Equal(t, "d", js.Get("n").Or("d").String())
+
+	def := New()
+	def.Set("c", true)
+	assert.Equal(t, true, js.Get("x", "y").Or(def).Get("c").Bool())
+	_, ok := js.CheckGet("x")
+	assert.Equal(t, false, ok)
+}
+
+func TestEncodePrettyIndent(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":[1,{"b":2}]}`))
+	assert.Equal(t, nil, err)
+
+	b, err := js.EncodePrettyIndent("", "\t")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "{\n\t\"a\": [\n\t\t1,\n\t\t{\n\t\t\t\"b\": 2\n\t\t}\n\t]\n}", string(b))
+
+	b, _ = js.Get("a", 1).EncodePrettyIndent("// ", "    ")
+	assert.Equal(t, "{\n//     \"b\": 2\n// }", string(b))
+}
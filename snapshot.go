@@ -0,0 +1,209 @@
+package simplejson
+
+import "reflect"
+
+// Snapshot returns a copy of the document that shares its whole structure
+// with the original. Both stay independent: writes to either of them, through
+// Set, SetPath, Del and the other mutators, first copy the containers on the
+// path from the root to the modified value, leaving the other untouched.
+//
+// Snapshots are cheap to take, the cost of copying is paid incrementally by
+// writes. Views obtained from either document before a write to one of their
+// ancestors keep observing the data as it was, and should be obtained again.
+// Containers returned by Map and Array are shared and must not be modified
+// directly. Snapshotting a frozen document returns a mutable copy.
+func (j *JSON) Snapshot() *JSON {
+	if j == nil {
+		return j.Clone()
+	}
+	// frozen containers are never modified in place, leaving the state of
+	// the document untouched keeps it safe for concurrent readers
+	if !j.IsFrozen() {
+		j.document().share()
+	}
+
+	s := &JSON{data: j.data}
+	s.document().share()
+	return s
+}
+
+// share marks every container of the document as possibly referenced elsewhere
+func (d *document) share() {
+	d.track = true
+	d.shared = true
+	d.owned = make(map[uintptr]interface{})
+	d.ownedMax = minOwned
+}
+
+// minOwned is the number of owned containers above which the document
+// first drops the ones no longer part of it
+const minOwned = 64
+
+// writable returns `data` if it can be modified in place by `j`,
+// or a shallow copy of it owned by its document otherwise
+func (j *JSON) writable(data interface{}) interface{} {
+	if j.doc == nil || !j.doc.shared {
+		return data
+	}
+	return j.doc.writable(data)
+}
+
+func (d *document) writable(data interface{}) interface{} {
+	id := identity(data)
+	if _, owned := d.owned[id]; id == 0 || owned {
+		return data
+	}
+	switch v := data.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[key] = val
+		}
+		data = m
+	case []interface{}:
+		data = append([]interface{}(nil), v...)
+	}
+	d.addOwned(data)
+	return data
+}
+
+// addOwned records `data` as owned by the document. Containers are kept
+// alive by the set, so their address can't be reused by another container
+// while recorded. The set is compacted to the containers still reachable
+// from the root each time it doubles, which bounds it by the size of the
+// document
+func (d *document) addOwned(data interface{}) {
+	if len(d.owned) >= d.ownedMax {
+		owned := make(map[uintptr]interface{})
+		d.reachable(d.root.data, owned)
+		d.owned = owned
+		d.ownedMax = 2 * len(owned)
+		if d.ownedMax < minOwned {
+			d.ownedMax = minOwned
+		}
+	}
+	d.owned[identity(data)] = data
+}
+
+// reachable adds the owned containers reachable from `data` to `owned`,
+// the ancestors of an owned container being owned as well
+func (d *document) reachable(data interface{}, owned map[uintptr]interface{}) {
+	id := identity(data)
+	if _, ok := d.owned[id]; id == 0 || !ok {
+		return
+	}
+	owned[id] = data
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for _, val := range v {
+			d.reachable(val, owned)
+		}
+	case []interface{}:
+		for _, val := range v {
+			d.reachable(val, owned)
+		}
+	}
+}
+
+// own makes every container from the root of the document down to `j`
+// owned by the document, copying the shared ones, so `j` can be modified
+// in place without affecting snapshots
+func (j *JSON) own() {
+	if j == nil {
+		return
+	}
+	d := j.doc
+	if d == nil || !d.shared {
+		return
+	}
+	if j.parent == nil && j != d.root && !d.locate(j) {
+		// not part of the document anymore, detach it
+		j.data = d.writable(j.data)
+		return
+	}
+	if j.parent == nil {
+		j.data = d.writable(j.data)
+		return
+	}
+
+	j.parent.own()
+	data, ok := lookup(j.parent.data, []interface{}{j.key})
+	if !ok {
+		j.data = d.writable(j.data)
+		return
+	}
+	j.data = d.writable(data)
+	if c, ok := j.parent.data.(map[string]interface{}); ok {
+		c[j.key.(string)] = j.data
+	} else {
+		j.parent.data.([]interface{})[j.key.(int)] = j.data
+	}
+}
+
+// locate finds the container of a view obtained while the document did not
+// track views, and sets its parent and key. Views obtained by a lookup are
+// found again by walking their branch, others by searching the document
+func (d *document) locate(j *JSON) bool {
+	if j.base != nil {
+		// values without identity can't be told apart,
+		// the one found at the branch is taken as the view's
+		c, ok := j.base.walkWith(j.rel, false)
+		if ok && identity(c.data) == identity(j.data) {
+			j.parent, j.key = c.parent, c.key
+			j.base, j.rel = nil, nil
+			return true
+		}
+	}
+	id := identity(j.data)
+	if id == 0 {
+		return false
+	}
+	path, ok := find(d.root.data, id, nil)
+	if !ok || len(path) == 0 {
+		return false
+	}
+	parent := d.root
+	for _, key := range path[:len(path)-1] {
+		data, _ := lookup(parent.data, []interface{}{key})
+		parent = parent.child(data, key)
+	}
+	j.parent, j.key = parent, path[len(path)-1]
+	return true
+}
+
+// find returns the branch leading from `data` to the container identified by `id`
+func find(data interface{}, id uintptr, branch []interface{}) ([]interface{}, bool) {
+	if identity(data) == id {
+		return branch, true
+	}
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			if path, ok := find(val, id, append(branch, key)); ok {
+				return path, true
+			}
+		}
+	case []interface{}:
+		for i, val := range v {
+			if path, ok := find(val, id, append(branch, i)); ok {
+				return path, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// identity returns the address of a map or array, 0 for other values
+// and arrays without storage, which can't be modified in place
+func identity(data interface{}) uintptr {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		return reflect.ValueOf(v).Pointer()
+	case []interface{}:
+		if cap(v) == 0 {
+			return 0
+		}
+		return reflect.ValueOf(v).Pointer()
+	}
+	return 0
+}
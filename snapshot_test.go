@@ -0,0 +1,111 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"b":{"c":1},"d":[{"e":1}]},"f":{"g":1}}`))
+	assert.Equal(t, nil, err)
+	early := js.Get("a", "b")
+
+	snap := js.Snapshot()
+	assert.Equal(t, js.Interface(), snap.Interface())
+
+	// views obtained before the snapshot are located within the document
+	early.Set("x", true)
+	assert.Equal(t, true, js.Get("a", "b", "x").Bool())
+	_, ok := snap.Get("a", "b").CheckGet("x")
+	assert.Equal(t, false, ok)
+
+	js.Get("a", "b").Set("c", 2)
+	assert.Equal(t, 2, js.Get("a", "b", "c").Int())
+	assert.Equal(t, 1, snap.Get("a", "b", "c").Int())
+
+	// untouched branches are still shared
+	assert.Equal(t, identity(js.Get("f").Interface()), identity(snap.Get("f").Interface()))
+
+	snap.Get("a", "d", 0).Set("e", 3)
+	assert.Equal(t, 1, js.Get("a", "d", 0, "e").Int())
+	assert.Equal(t, 3, snap.Get("a", "d", 0, "e").Int())
+
+	snap.SetPath([]string{"f", "g"}, 5)
+	assert.Equal(t, 1, js.Get("f", "g").Int())
+	assert.Equal(t, 5, snap.Get("f", "g").Int())
+
+	snap.Del("a")
+	assert.Equal(t, 2, js.Get("a", "b", "c").Int())
+}
+
+func TestSnapshotFrozen(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"b":1}}`))
+	assert.Equal(t, nil, err)
+	js.Freeze()
+
+	snap := js.Snapshot()
+	snap.Get("a").Set("b", 2)
+	assert.Equal(t, 1, js.Get("a", "b").Int())
+	assert.Equal(t, 2, snap.Get("a", "b").Int())
+}
+
+func TestSnapshotAppend(t *testing.T) {
+	js := New()
+	js.Set("a", make([]interface{}, 0, 8))
+	js.Get("a").Append(1, 2, 3, 4, 5)
+
+	snap := js.Snapshot()
+	other := js.Snapshot()
+	snap.Get("a").Append("x")
+	other.Get("a").Append("y")
+	js.Get("a").Append("z")
+	assert.Equal(t, `{"a":[1,2,3,4,5,"x"]}`, string(mustEncode(t, snap)))
+	assert.Equal(t, `{"a":[1,2,3,4,5,"y"]}`, string(mustEncode(t, other)))
+	assert.Equal(t, `{"a":[1,2,3,4,5,"z"]}`, string(mustEncode(t, js)))
+
+	tx := js.Begin()
+	tx.Get("a").Append("t")
+	assert.Equal(t, `{"a":[1,2,3,4,5,"z"]}`, string(mustEncode(t, js)))
+	assert.Equal(t, nil, tx.Commit())
+	assert.Equal(t, `{"a":[1,2,3,4,5,"z","t"]}`, string(mustEncode(t, js)))
+
+	// empty decoded arrays have no storage of their own
+	js = mustJSON(`{"a":[]}`)
+	snap = js.Snapshot()
+	js.Get("a").Append("x")
+	snap.Get("a").Append("y")
+	assert.Equal(t, `{"a":["x"]}`, string(mustEncode(t, js)))
+	assert.Equal(t, `{"a":["y"]}`, string(mustEncode(t, snap)))
+}
+
+func TestSnapshotOwnedBounded(t *testing.T) {
+	js := New()
+	snap := js.Snapshot()
+	for i := 0; i < 1000; i++ {
+		js.Set("k", map[string]interface{}{"x": 0})
+		js.Get("k").Set("x", i)
+		if i == 500 {
+			snap = js.Snapshot()
+		}
+	}
+	assert.Equal(t, 999, js.Get("k", "x").Int())
+	assert.Equal(t, 500, snap.Get("k", "x").Int())
+	// containers replaced by later writes are forgotten
+	assert.T(t, len(js.doc.owned) <= 2*minOwned)
+	for id, data := range js.doc.owned {
+		assert.Equal(t, id, identity(data))
+	}
+}
+
+func TestSnapshotLookupViews(t *testing.T) {
+	js := mustJSON(`{"a":{"list":[],"n":1}}`)
+	list := js.Get("a", "list")
+
+	// views obtained before the snapshot are found again by their branch
+	snap := js.Snapshot()
+	list.Append(1)
+	assert.Equal(t, `{"a":{"list":[1],"n":1}}`, string(mustEncode(t, js)))
+	assert.Equal(t, `{"a":{"list":[],"n":1}}`, string(mustEncode(t, snap)))
+	assert.Equal(t, []interface{}{"a", "list"}, list.Path())
+}
@@ -0,0 +1,78 @@
+package simplejson
+
+import (
+	"github.com/brunotm/go-simplejson/scanner"
+)
+
+// ValueType identifies the type of a JSON value
+type ValueType int
+
+// JSON value types
+const (
+	NullValue ValueType = iota
+	ObjectValue
+	ArrayValue
+	StringValue
+	NumberValue
+	BoolValue
+)
+
+func (t ValueType) String() string {
+	switch t {
+	case NullValue:
+		return "null"
+	case ObjectValue:
+		return "object"
+	case ArrayValue:
+		return "array"
+	case StringValue:
+		return "string"
+	case NumberValue:
+		return "number"
+	case BoolValue:
+		return "bool"
+	}
+	return "unknown"
+}
+
+// Sniff reports the type of the top-level value in `data` without decoding it.
+// Objects and arrays are recognized by their opening token only and are not
+// validated, scalars are checked to be a single valid value.
+func Sniff(data []byte) (ValueType, error) {
+	s := scanner.New(data)
+	b, err := s.Peek()
+	if err != nil {
+		return NullValue, err
+	}
+	switch b {
+	case '{':
+		return ObjectValue, nil
+	case '[':
+		return ArrayValue, nil
+	}
+
+	var h sniffHandler
+	if err := scanner.Scan(data, &h); err != nil {
+		return NullValue, err
+	}
+	switch h.kind {
+	case scanner.String:
+		return StringValue, nil
+	case scanner.Number:
+		return NumberValue, nil
+	case scanner.Bool:
+		return BoolValue, nil
+	}
+	return NullValue, nil
+}
+
+// sniffHandler records the kind of a scanned scalar
+type sniffHandler struct {
+	scanner.NopHandler
+	kind scanner.Kind
+}
+
+func (h *sniffHandler) OnValue(kind scanner.Kind, raw []byte) error {
+	h.kind = kind
+	return nil
+}
@@ -0,0 +1,30 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestSniff(t *testing.T) {
+	cases := map[string]ValueType{
+		` {"a": [1, 2`: ObjectValue,
+		"\n[{}]":       ArrayValue,
+		`"str"`:        StringValue,
+		`-1.5e3`:       NumberValue,
+		` true `:       BoolValue,
+		`false`:        BoolValue,
+		`null`:         NullValue,
+	}
+	for in, want := range cases {
+		got, err := Sniff([]byte(in))
+		assert.Equal(t, nil, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	assert.Equal(t, "array", ArrayValue.String())
+
+	for _, in := range []string{``, `  `, `nul`, `1 2`, `"open`, `}`, `01x`} {
+		_, err := Sniff([]byte(in))
+		assert.NotEqual(t, nil, err, in)
+	}
+}
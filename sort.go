@@ -0,0 +1,155 @@
+package simplejson
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortKeys makes every encoding of the document, and of the views obtained
+// from it, list keys sorted at every nesting level, as the SortedKeys option
+// does, and Keys return them sorted. Objects are maps which hold no order,
+// so the document itself is left unchanged.
+func (j *JSON) SortKeys() {
+	if j == nil {
+		return
+	}
+	j.document().sortKeys = true
+}
+
+// SortedKeys returns the top level keys of the `JSON` object in sorted order,
+// or nil if it is not an object
+func (j *JSON) SortedKeys() []string {
+	m, ok := j.CheckMap()
+	if !ok {
+		return nil
+	}
+	return sortedKeys(m)
+}
+
+// ForEachSorted calls `fn` with each key and value of the `JSON` object in
+// sorted key order, until `fn` returns false
+func (j *JSON) ForEachSorted(fn func(key string, val *JSON) bool) {
+	m, ok := j.CheckMap()
+	if !ok {
+		return
+	}
+	for _, key := range sortedKeys(m) {
+		if !fn(key, j.child(m[key], key)) {
+			return
+		}
+	}
+}
+
+// SortOrder is the direction a SortKey sorts in
+type SortOrder int
+
+// Sort directions
+const (
+	Asc SortOrder = iota
+	Desc
+)
+
+// SortKey is a field SortArrayByKeys sorts by, a dotted path within the
+// elements, such as "address.country", or "" for the elements themselves
+type SortKey struct {
+	Path  string
+	Order SortOrder
+}
+
+// SortArrayByKeys sorts the `JSON` array in place by the first of `keys`,
+// then by the next ones among elements with equal values, keeping the order
+// of elements equal by every key:
+//
+//	js.SortArrayByKeys([]SortKey{{"country", Asc}, {"age", Desc}})
+//
+// Numbers compare by value, strings lexically and false before true. Values
+// of different types sort booleans first, then numbers, strings, arrays and
+// objects, while missing and null values always sort last.
+func (j *JSON) SortArrayByKeys(keys []SortKey) {
+	j.permute(func(a []interface{}) {
+		vals := make([][]interface{}, len(a))
+		for i, elem := range a {
+			vals[i] = make([]interface{}, len(keys))
+			for k, key := range keys {
+				vals[i][k], _ = lookup(elem, dottedBranch(elem, key.Path))
+			}
+		}
+		idx := make([]int, len(a))
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.SliceStable(idx, func(x, y int) bool {
+			for k, key := range keys {
+				vx, vy := vals[idx[x]][k], vals[idx[y]][k]
+				if vx == nil || vy == nil {
+					if (vx == nil) != (vy == nil) {
+						return vy == nil
+					}
+					continue
+				}
+				c := compareValues(vx, vy)
+				if key.Order == Desc {
+					c = -c
+				}
+				if c != 0 {
+					return c < 0
+				}
+			}
+			return false
+		})
+		sorted := make([]interface{}, len(a))
+		for i, k := range idx {
+			sorted[i] = a[k]
+		}
+		copy(a, sorted)
+	})
+}
+
+// compareValues orders two values that are not null, see SortArrayByKeys
+func compareValues(x, y interface{}) int {
+	rx, ry := typeRank(x), typeRank(y)
+	if rx != ry {
+		if rx < ry {
+			return -1
+		}
+		return 1
+	}
+	switch vx := x.(type) {
+	case bool:
+		if vx == y.(bool) {
+			return 0
+		}
+		if !vx {
+			return -1
+		}
+		return 1
+	case string:
+		return strings.Compare(vx, y.(string))
+	}
+	if nx, ok := numberRat(x); ok {
+		if ny, ok := numberRat(y); ok {
+			return nx.Cmp(ny)
+		}
+	}
+	kx, _ := canonicalKey(x)
+	ky, _ := canonicalKey(y)
+	return strings.Compare(kx, ky)
+}
+
+// typeRank orders values of different types
+func typeRank(val interface{}) int {
+	switch val.(type) {
+	case bool:
+		return 0
+	case string:
+		return 2
+	case []interface{}:
+		return 3
+	case map[string]interface{}:
+		return 4
+	}
+	if isNumber(val) {
+		return 1
+	}
+	return 5
+}
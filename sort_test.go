@@ -0,0 +1,84 @@
+package simplejson
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestSortKeys(t *testing.T) {
+	SetCodec(unsortedCodec{})
+	defer SetCodec(nil)
+
+	js, err := NewJSON([]byte(`{"b":1,"a":{"y":2,"x":3}}`))
+	assert.Equal(t, nil, err)
+	b, _ := js.Encode()
+	assert.Equal(t, `"unsorted"`, string(b))
+
+	js.SortKeys()
+	b, _ = js.Encode()
+	assert.Equal(t, `{"a":{"x":3,"y":2},"b":1}`, string(b))
+	b, _ = js.Get("a").Encode()
+	assert.Equal(t, `{"x":3,"y":2}`, string(b))
+	b, _ = js.MarshalJSON()
+	assert.Equal(t, `{"a":{"x":3,"y":2},"b":1}`, string(b))
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, NewEncoder(&buf).Encode(js))
+	assert.Equal(t, "{\"a\":{\"x\":3,\"y\":2},\"b\":1}\n", buf.String())
+}
+
+func TestSortedIteration(t *testing.T) {
+	js := mustJSON(`{"c":1,"a":2,"b":{"z":0,"y":0},"d":3}`)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, js.SortedKeys())
+	assert.Equal(t, []string{"y", "z"}, js.Get("b").SortedKeys())
+	assert.Equal(t, []string(nil), js.Get("a").SortedKeys())
+
+	var keys []string
+	sum := 0
+	js.ForEachSorted(func(key string, val *JSON) bool {
+		keys = append(keys, key)
+		sum += val.Int()
+		return key != "c"
+	})
+	assert.Equal(t, []string{"a", "b", "c"}, keys)
+	assert.Equal(t, 3, sum)
+
+	js.ForEachSorted(func(key string, val *JSON) bool {
+		val.Set("x", key)
+		return true
+	})
+	assert.Equal(t, "b", js.Get("b", "x").String())
+
+	js.SortKeys()
+	assert.Equal(t, []string{"a", "b", "c", "d"}, js.Keys())
+	assert.Equal(t, []string{"x", "y", "z"}, js.Get("b").Keys())
+}
+
+func TestSortArrayByKeys(t *testing.T) {
+	js := mustJSON(`[
+		{"name":"a","country":"fr","age":30},
+		{"name":"b","country":"de","age":25},
+		{"name":"c","country":"fr","age":41},
+		{"name":"d","age":50},
+		{"name":"e","country":"de","age":25.5},
+		{"name":"f","country":"de","age":25}
+	]`)
+	names := func() []string {
+		var out []string
+		for _, elem := range js.Array() {
+			out = append(out, elem.(map[string]interface{})["name"].(string))
+		}
+		return out
+	}
+
+	js.SortArrayByKeys([]SortKey{{"country", Asc}, {"age", Desc}})
+	assert.Equal(t, []string{"e", "b", "f", "c", "a", "d"}, names())
+	js.SortArrayByKeys([]SortKey{{"country", Desc}, {"name", Asc}})
+	assert.Equal(t, []string{"a", "c", "b", "e", "f", "d"}, names())
+
+	mixed := mustJSON(`[ "b", null, 2, true, "a", [1], 1.5, {"a":1}, false ]`)
+	mixed.SortArrayByKeys([]SortKey{{"", Asc}})
+	assert.Equal(t, `[false,true,1.5,2,"a","b",[1],{"a":1},null]`, string(mustEncode(t, mixed)))
+}
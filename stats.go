@@ -0,0 +1,204 @@
+package simplejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"unicode/utf8"
+)
+
+// DocumentStats describes the shape and size of a document
+type DocumentStats struct {
+	Objects int
+	Arrays  int
+	Strings int
+	Numbers int
+	Bools   int
+	Nulls   int
+	// Keys is the total number of object members
+	Keys int
+	// MaxDepth is the deepest container nesting, 0 for a scalar document
+	MaxDepth int
+	// StringBytes is the total length of keys and string values
+	StringBytes int
+	// EncodedSize is the estimated length of the compact encoding
+	EncodedSize int
+}
+
+// Nodes returns the total number of values in the document
+func (s DocumentStats) Nodes() int {
+	return s.Objects + s.Arrays + s.Strings + s.Numbers + s.Bools + s.Nulls
+}
+
+// Stats walks the document and reports its statistics without encoding it
+func (j *JSON) Stats() DocumentStats {
+	var s DocumentStats
+	s.walk(j.Interface(), 0)
+	return s
+}
+
+func (s *DocumentStats) walk(data interface{}, depth int) {
+	if depth > s.MaxDepth {
+		s.MaxDepth = depth
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		s.Objects++
+		s.Keys += len(v)
+		s.EncodedSize += 2 + separators(len(v))
+		for key, val := range v {
+			s.StringBytes += len(key)
+			s.EncodedSize += quotedLen(key) + 1
+			s.walk(val, depth+1)
+		}
+		if depth+1 > s.MaxDepth {
+			s.MaxDepth = depth + 1
+		}
+	case []interface{}:
+		s.Arrays++
+		s.EncodedSize += 2 + separators(len(v))
+		for _, val := range v {
+			s.walk(val, depth+1)
+		}
+		if depth+1 > s.MaxDepth {
+			s.MaxDepth = depth + 1
+		}
+	case string:
+		s.Strings++
+		s.StringBytes += len(v)
+		s.EncodedSize += quotedLen(v)
+	case bool:
+		s.Bools++
+		s.EncodedSize += len(strconv.FormatBool(v))
+	case nil:
+		s.Nulls++
+		s.EncodedSize += 4
+	default:
+		if isNumber(v) {
+			s.Numbers++
+		}
+		s.EncodedSize += scalarLen(v)
+	}
+}
+
+// EncodedLen returns the length of the output of Encode without encoding
+// the whole document, to size buffers or enforce payload limits beforehand.
+// Scalars are measured by encoding them as Encode does, one at a time.
+// It fails where Encode would, such as on NaN floats.
+func (j *JSON) EncodedLen() (int, error) {
+	data := j.Interface()
+	c := newEncodeConfig(nil).forDocument(j)
+	if _, std := codec.(StdCodec); c.native() && !std {
+		// other codecs may encode scalars differently within documents
+		b, err := codec.Marshal(data)
+		return len(b), err
+	}
+	var scratch bytes.Buffer
+	return c.encodedLen(&scratch, data)
+}
+
+// encodedLen returns the length of the encoding of `data`,
+// scalars being written to `scratch`
+func (c *encodeConfig) encodedLen(scratch *bytes.Buffer, data interface{}) (int, error) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		n := 2 + separators(len(v))
+		for key, val := range v {
+			scratch.Reset()
+			if err := c.writeString(scratch, key); err != nil {
+				return 0, err
+			}
+			n += scratch.Len() + 1
+			m, err := c.encodedLen(scratch, val)
+			if err != nil {
+				return 0, err
+			}
+			n += m
+		}
+		return n, nil
+	case []interface{}:
+		n := 2 + separators(len(v))
+		for _, val := range v {
+			m, err := c.encodedLen(scratch, val)
+			if err != nil {
+				return 0, err
+			}
+			n += m
+		}
+		return n, nil
+	}
+	scratch.Reset()
+	if err := c.write(scratch, data); err != nil {
+		return 0, err
+	}
+	return scratch.Len(), nil
+}
+
+func separators(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return n - 1
+}
+
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case json.Number, float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return true
+	}
+	return false
+}
+
+// scalarLen returns the encoded length of a non container value
+func scalarLen(v interface{}) int {
+	switch n := v.(type) {
+	case json.Number:
+		return len(n)
+	case string:
+		return quotedLen(n)
+	case int:
+		return len(strconv.FormatInt(int64(n), 10))
+	case int64:
+		return len(strconv.FormatInt(n, 10))
+	case uint64:
+		return len(strconv.FormatUint(n, 10))
+	}
+	b, err := codec.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
+
+// quotedLen returns the length of `s` encoded as a JSON string
+// with the escaping rules of encoding/json
+func quotedLen(s string) int {
+	n := 2
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\' || c == '\n' || c == '\r' || c == '\t':
+				n += 2
+			case c < 0x20 || c == '<' || c == '>' || c == '&':
+				n += 6
+			default:
+				n++
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			n += 3 // replaced by U+FFFD
+		case r == '\u2028' || r == '\u2029':
+			n += 6
+		default:
+			n += size
+		}
+		i += size
+	}
+	return n
+}
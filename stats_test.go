@@ -0,0 +1,60 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestStats(t *testing.T) {
+	raw := `{"a":[1,2.5,"x<y"],"b":{"c":null,"d":true,"e":"café\n"},"f":[]}`
+	js, err := NewJSON([]byte(raw))
+	assert.Equal(t, nil, err)
+	js.Set("g", int64(-42))
+
+	s := js.Stats()
+	assert.Equal(t, 2, s.Objects)
+	assert.Equal(t, 2, s.Arrays)
+	assert.Equal(t, 2, s.Strings)
+	assert.Equal(t, 3, s.Numbers)
+	assert.Equal(t, 1, s.Bools)
+	assert.Equal(t, 1, s.Nulls)
+	assert.Equal(t, 7, s.Keys)
+	assert.Equal(t, 2, s.MaxDepth)
+	assert.Equal(t, 11, s.Nodes())
+	assert.Equal(t, 7+3+6, s.StringBytes)
+
+	b, err := js.Encode()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, len(b), s.EncodedSize)
+
+	assert.Equal(t, 0, New().Get("missing").Stats().MaxDepth)
+}
+
+func TestEncodedLen(t *testing.T) {
+	js := mustJSON(`{"a":[1,2.50,"x<y "],"b":{"c":null,"d":true,"e":"café\n\u0001"},"f":[],"g":{}}`)
+	js.Set("h", []interface{}{int8(-3), uint(7), float32(0.1), 1e21, map[string]int{"z": 1}})
+	js.Set("i", "\xff")
+	js.Set("j", "\b\f\u2028<>&")
+	js.Set("k\b", []interface{}{1.5, 1e-7, -0.0, float32(3.4e38), uint64(1 << 63)})
+	b := mustEncode(t, js)
+	n, err := js.EncodedLen()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, len(b), n)
+
+	n, err = js.Get("b").EncodedLen()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, len(mustEncode(t, js.Get("b"))), n)
+	n, err = js.Get("missing").EncodedLen()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 4, n)
+
+	js.Set("nan", math.NaN())
+	_, err = js.EncodedLen()
+	assert.NotEqual(t, nil, err)
+	js.Set("nan", json.Number("1."))
+	_, err = js.EncodedLen()
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,172 @@
+package simplejson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+// EncodePrettyTo writes the document to `w` as EncodePretty encodes it,
+// walking the document and writing values as they are encoded, so the
+// output of large documents is never held in memory. Options apply as for
+// EncodeWith, except LineWidth which is ignored.
+func (j *JSON) EncodePrettyTo(w io.Writer, opts ...EncodeOption) error {
+	c := newEncodeConfig(opts).forDocument(j)
+	bw := bufio.NewWriter(c.output(w))
+	p := &prettyWriter{w: bw, c: c, prefix: c.prefix, indent: c.indent}
+	if p.prefix == "" && p.indent == "" {
+		p.indent = "  "
+	}
+	if err := p.write(j.Interface(), 0); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// prettyWriter writes indented JSON to a stream
+type prettyWriter struct {
+	w      *bufio.Writer
+	c      *encodeConfig
+	prefix string
+	indent string
+	// buf holds the encoding of the scalar being written
+	buf bytes.Buffer
+}
+
+// write writes `data`, found at nesting level `depth`
+func (p *prettyWriter) write(data interface{}, depth int) error {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			_, err := p.w.WriteString("{}")
+			return err
+		}
+		p.w.WriteByte('{')
+		for i, key := range sortedKeys(v) {
+			if i > 0 {
+				p.w.WriteByte(',')
+			}
+			p.newline(depth + 1)
+			if err := p.scalar(key, depth+1); err != nil {
+				return err
+			}
+			p.w.WriteString(": ")
+			if err := p.write(v[key], depth+1); err != nil {
+				return err
+			}
+		}
+		p.newline(depth)
+		return p.w.WriteByte('}')
+	case []interface{}:
+		if len(v) == 0 {
+			_, err := p.w.WriteString("[]")
+			return err
+		}
+		p.w.WriteByte('[')
+		for i, val := range v {
+			if i > 0 {
+				p.w.WriteByte(',')
+			}
+			p.newline(depth + 1)
+			if err := p.write(val, depth+1); err != nil {
+				return err
+			}
+		}
+		p.newline(depth)
+		return p.w.WriteByte(']')
+	}
+	return p.scalar(data, depth)
+}
+
+// scalar writes `data` as a single value, indenting it if it is
+// a Go value encoded by the codec as an object or array
+func (p *prettyWriter) scalar(data interface{}, depth int) error {
+	p.buf.Reset()
+	if err := p.c.write(&p.buf, data); err != nil {
+		return err
+	}
+	b := p.buf.Bytes()
+	if len(b) > 0 && (b[0] == '{' || b[0] == '[') {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, b, p.prefix+strings.Repeat(p.indent, depth), p.indent); err != nil {
+			return err
+		}
+		b = indented.Bytes()
+	}
+	_, err := p.w.Write(b)
+	return err
+}
+
+func (p *prettyWriter) newline(depth int) {
+	p.w.WriteByte('\n')
+	p.w.WriteString(p.prefix)
+	for i := 0; i < depth; i++ {
+		p.w.WriteString(p.indent)
+	}
+}
+
+// StreamArrayTo writes the array found at `path` to `w`, `chunk` elements
+// at a time, encoding them as they are written rather than the whole array
+// first. Writers with a Flush method, such as http.ResponseWriter, are
+// flushed after a write once `flushEvery` has elapsed since the previous
+// flush, so clients of chunked responses receive elements as they come:
+//
+//	err := js.StreamArrayTo(w, []interface{}{"items"}, 100, time.Second)
+//
+// A zero `flushEvery` flushes after every chunk. The array is written
+// compactly, a *TypeError is returned if `path` is not an array.
+func (j *JSON) StreamArrayTo(w io.Writer, path []interface{}, chunk int, flushEvery time.Duration) error {
+	j = j.orMissing()
+	data, _ := lookup(j.data, path)
+	a, ok := data.([]interface{})
+	if !ok {
+		base, _ := j.location()
+		return &TypeError{Path: append(base, path...), Want: "array", Got: jsonType(data)}
+	}
+	if chunk < 1 {
+		chunk = 1
+	}
+
+	c := newEncodeConfig(nil).forDocument(j)
+	flush := func() error {
+		switch f := w.(type) {
+		case interface{ Flush() }:
+			f.Flush()
+		case interface{ Flush() error }:
+			return f.Flush()
+		}
+		return nil
+	}
+	last := time.Now()
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := 0; i == 0 || i < len(a); i += chunk {
+		for k := i; k < i+chunk && k < len(a); k++ {
+			if k > 0 {
+				buf.WriteByte(',')
+			}
+			b, err := c.encode(a[k])
+			if err != nil {
+				return err
+			}
+			buf.Write(b)
+		}
+		if i+chunk >= len(a) {
+			buf.WriteByte(']')
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+		buf.Reset()
+		if time.Since(last) >= flushEvery && i+chunk < len(a) {
+			if err := flush(); err != nil {
+				return err
+			}
+			last = time.Now()
+		}
+	}
+	return flush()
+}
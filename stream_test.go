@@ -0,0 +1,74 @@
+package simplejson
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestEncodePrettyTo(t *testing.T) {
+	js := mustJSON(`{"b":"<a&b>","a":[1,2.5,{"d":null,"c":true}],"e":{},"f":[],"g":[[]]}`)
+	js.Set("h", map[string][]int{"x": {1, 2}})
+
+	want, err := js.EncodePretty()
+	assert.Equal(t, nil, err)
+	var buf bytes.Buffer
+	assert.Equal(t, nil, js.EncodePrettyTo(&buf))
+	assert.Equal(t, string(want), buf.String())
+
+	want, err = js.EncodeWith(Indent("> ", "\t"), EscapeHTML(false))
+	assert.Equal(t, nil, err)
+	buf.Reset()
+	assert.Equal(t, nil, js.EncodePrettyTo(&buf, Indent("> ", "\t"), EscapeHTML(false)))
+	assert.Equal(t, string(want), buf.String())
+
+	buf.Reset()
+	assert.Equal(t, nil, js.Get("a", 1).EncodePrettyTo(&buf))
+	assert.Equal(t, "2.5", buf.String())
+
+	js.Set("nan", func() {})
+	assert.NotEqual(t, nil, js.EncodePrettyTo(&buf))
+}
+
+type flushRecorder struct {
+	bytes.Buffer
+	writes  []string
+	flushes int
+}
+
+func (r *flushRecorder) Write(p []byte) (int, error) {
+	r.writes = append(r.writes, string(p))
+	return r.Buffer.Write(p)
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes++
+}
+
+func TestStreamArrayTo(t *testing.T) {
+	js := mustJSON(`{"items":[1,{"a":"b"},[2],"c","d"],"empty":[]}`)
+
+	var r flushRecorder
+	assert.Equal(t, nil, js.StreamArrayTo(&r, []interface{}{"items"}, 2, 0))
+	assert.Equal(t, `[1,{"a":"b"},[2],"c","d"]`, r.String())
+	assert.Equal(t, []string{`[1,{"a":"b"}`, `,[2],"c"`, `,"d"]`}, r.writes)
+	assert.Equal(t, 3, r.flushes)
+
+	r = flushRecorder{}
+	assert.Equal(t, nil, js.StreamArrayTo(&r, []interface{}{"items"}, 0, time.Hour))
+	assert.Equal(t, 5, len(r.writes))
+	assert.Equal(t, 1, r.flushes)
+
+	r = flushRecorder{}
+	assert.Equal(t, nil, js.StreamArrayTo(&r, []interface{}{"empty"}, 10, 0))
+	assert.Equal(t, "[]", r.String())
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, js.Get("items").StreamArrayTo(&buf, nil, 10, 0))
+	assert.Equal(t, `[1,{"a":"b"},[2],"c","d"]`, buf.String())
+
+	err := js.StreamArrayTo(&buf, []interface{}{"items", 1}, 10, 0)
+	assert.Equal(t, "simplejson: expected array at items[1], got object", err.Error())
+}
@@ -0,0 +1,103 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrUnsupportedValue is wrapped by the panic value of modifications storing
+// a value that cannot be encoded as JSON into a strict document, see StrictSet
+var ErrUnsupportedValue = errors.New("unsupported value")
+
+// StrictSet makes Set, SetPath, SetIndex and Append check the values they
+// store in the document `j` belongs to, panicking with an error wrapping
+// ErrUnsupportedValue when one cannot be encoded, such as a channel, a
+// function or a NaN float, instead of letting Encode fail far from the bug:
+//
+//	simplejson: unsupported value at items[3].done: chan bool
+//
+// Values within objects and arrays are checked too.
+func (j *JSON) StrictSet() {
+	if j == nil {
+		return
+	}
+	j.document().strict = true
+}
+
+// checkValue panics if the document is strict and `val`, to be stored
+// at `branch` within `j`, cannot be encoded
+func (j *JSON) checkValue(val interface{}, branch ...interface{}) {
+	if err := j.strictError(val, branch...); err != nil {
+		panic(err)
+	}
+}
+
+// strictError is like checkValue, returning the error instead of panicking
+func (j *JSON) strictError(val interface{}, branch ...interface{}) error {
+	if j == nil || j.doc == nil || !j.doc.strict {
+		return nil
+	}
+	desc, path, ok := unsupported(val, nil)
+	if !ok {
+		return nil
+	}
+	base, _ := j.location()
+	path = append(append(base, branch...), path...)
+	return fmt.Errorf("simplejson: %w at %s: %s", ErrUnsupportedValue, formatPath(path), desc)
+}
+
+// unsupported describes the first value of `val` that cannot be encoded
+// and returns its location within `val`, appended to `path`
+func unsupported(val interface{}, path []interface{}) (string, []interface{}, bool) {
+	switch v := val.(type) {
+	case nil, bool, string, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return "", nil, false
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return fmt.Sprintf("float64 %v", v), path, true
+		}
+		return "", nil, false
+	case float32:
+		if math.IsInf(float64(v), 0) || math.IsNaN(float64(v)) {
+			return fmt.Sprintf("float32 %v", v), path, true
+		}
+		return "", nil, false
+	case json.Number:
+		if !isValidNumber(string(v)) {
+			return fmt.Sprintf("invalid number literal %q", string(v)), path, true
+		}
+		return "", nil, false
+	case map[string]interface{}:
+		for key, elem := range v {
+			if desc, at, ok := unsupported(elem, append(path, key)); ok {
+				return desc, at, true
+			}
+		}
+		return "", nil, false
+	case []interface{}:
+		for i, elem := range v {
+			if desc, at, ok := unsupported(elem, append(path, i)); ok {
+				return desc, at, true
+			}
+		}
+		return "", nil, false
+	}
+
+	// other Go values are encoded by the codec
+	_, err := codec.Marshal(val)
+	if err == nil {
+		return "", nil, false
+	}
+	var typeErr *json.UnsupportedTypeError
+	var valueErr *json.UnsupportedValueError
+	switch {
+	case errors.As(err, &typeErr):
+		return typeErr.Type.String(), path, true
+	case errors.As(err, &valueErr):
+		return fmt.Sprintf("%T %s", val, valueErr.Str), path, true
+	}
+	return fmt.Sprintf("%T: %v", val, err), path, true
+}
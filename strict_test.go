@@ -0,0 +1,55 @@
+package simplejson
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+// setPanic returns the error `fn` panics with, nil if it does not
+func setPanic(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = r.(error)
+		}
+	}()
+	fn()
+	return nil
+}
+
+func TestStrictSet(t *testing.T) {
+	js := mustJSON(`{"items":[{"a":1}]}`)
+
+	// values are not checked by default
+	assert.Equal(t, nil, setPanic(func() { js.Set("f", func() {}) }))
+	js.Del("f")
+
+	js.StrictSet()
+	item := js.Get("items", 0)
+	err := setPanic(func() { item.Set("done", make(chan bool)) })
+	assert.T(t, errors.Is(err, ErrUnsupportedValue))
+	assert.Equal(t, "simplejson: unsupported value at items[0].done: chan bool", err.Error())
+	_, ok := item.CheckGet("done")
+	assert.Equal(t, false, ok)
+
+	err = setPanic(func() { js.SetPath([]string{"a", "b"}, []interface{}{1, math.NaN()}) })
+	assert.Equal(t, "simplejson: unsupported value at a.b[1]: float64 NaN", err.Error())
+	err = setPanic(func() { js.Get("items").Append(2, map[string]interface{}{"c": complex(1, 2)}) })
+	assert.Equal(t, "simplejson: unsupported value at items[2].c: complex128", err.Error())
+	assert.Equal(t, 1, len(js.Get("items").Array()))
+	err = setPanic(func() { js.Get("items").SetIndex(0, struct{ F func() }{}) })
+	assert.Equal(t, "simplejson: unsupported value at items[0]: func()", err.Error())
+
+	// values the codec encodes are accepted
+	assert.Equal(t, nil, setPanic(func() {
+		js.Set("s", struct{ A []int }{[]int{1}})
+		js.Set("n", 1.5)
+		js.Get("items").Append(nil, "x", map[string]int{"a": 1})
+	}))
+
+	tx := js.Begin()
+	err = setPanic(func() { tx.Set("f", func() {}) })
+	assert.T(t, errors.Is(err, ErrUnsupportedValue))
+}
@@ -0,0 +1,122 @@
+package simplejson
+
+import "sync"
+
+// SyncJSON is a `JSON` document safe for concurrent use by multiple goroutines.
+//
+// Reads return detached copies, so values obtained from it can be freely used
+// while other goroutines modify the document. Mutations are atomic.
+type SyncJSON struct {
+	sync.RWMutex
+	j *JSON
+}
+
+// NewSync returns a SyncJSON guarding `j`, which must not be used directly afterwards.
+// A nil `j` starts from an empty object.
+func NewSync(j *JSON) *SyncJSON {
+	if j == nil {
+		j = New()
+	}
+	return &SyncJSON{j: j}
+}
+
+// Get is like JSON.Get, returning a deep copy of the value found
+func (s *SyncJSON) Get(branch ...interface{}) *JSON {
+	s.RLock()
+	defer s.RUnlock()
+	return s.j.Get(branch...).Clone()
+}
+
+// CheckGet is like JSON.CheckGet, returning a deep copy of the value found
+func (s *SyncJSON) CheckGet(branch ...interface{}) (*JSON, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	jin, ok := s.j.CheckGet(branch...)
+	if !ok {
+		return nil, false
+	}
+	return jin.Clone(), true
+}
+
+// Interface returns a deep copy of the underlying data
+func (s *SyncJSON) Interface() interface{} {
+	s.RLock()
+	defer s.RUnlock()
+	return deepCopy(s.j.data)
+}
+
+// Keys returns the top level keys of the document
+func (s *SyncJSON) Keys() []string {
+	s.RLock()
+	defer s.RUnlock()
+	return s.j.Keys()
+}
+
+// Set atomically modifies the document by `key` and `value`
+func (s *SyncJSON) Set(key string, val interface{}) {
+	s.Lock()
+	defer s.Unlock()
+	s.j.Set(key, val)
+}
+
+// SetPath atomically modifies the document creating map keys for the supplied path
+func (s *SyncJSON) SetPath(branch []string, val interface{}) {
+	s.Lock()
+	defer s.Unlock()
+	s.j.SetPath(branch, val)
+}
+
+// Del atomically deletes `key` from the document
+func (s *SyncJSON) Del(key string) {
+	s.Lock()
+	defer s.Unlock()
+	s.j.Del(key)
+}
+
+// View calls `fn` with the document under a read lock.
+// `fn` must not modify the document nor retain it after returning.
+func (s *SyncJSON) View(fn func(j *JSON)) {
+	s.RLock()
+	defer s.RUnlock()
+	fn(s.j)
+}
+
+// Update calls `fn` with the document under a write lock,
+// allowing several modifications to be applied atomically.
+// `fn` must not retain the document after returning.
+func (s *SyncJSON) Update(fn func(j *JSON)) {
+	s.Lock()
+	defer s.Unlock()
+	fn(s.j)
+}
+
+// Encode returns the marshaled document as `[]byte`
+func (s *SyncJSON) Encode() ([]byte, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.j.Encode()
+}
+
+// EncodePretty returns the marshaled document as `[]byte` with indentation
+func (s *SyncJSON) EncodePretty() ([]byte, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.j.EncodePretty()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *SyncJSON) MarshalJSON() ([]byte, error) {
+	return s.Encode()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, replacing the document.
+func (s *SyncJSON) UnmarshalJSON(p []byte) error {
+	j := new(JSON)
+	if err := j.UnmarshalJSON(p); err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.j = j
+	return nil
+}
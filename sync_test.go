@@ -0,0 +1,43 @@
+package simplejson
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestSyncJSON(t *testing.T) {
+	js, err := NewJSON([]byte(`{"config":{"n":0}}`))
+	assert.Equal(t, nil, err)
+	s := NewSync(js)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for n := 0; n < 50; n++ {
+				s.SetPath([]string{"config", "w" + strconv.Itoa(i)}, n)
+				c := s.Get("config")
+				c.Set("local", true)
+				s.Update(func(j *JSON) {
+					j.Get("config").Set("n", j.Get("config", "n").Int()+1)
+				})
+				_, _ = s.Encode()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 400, s.Get("config", "n").Int())
+	assert.Equal(t, 49, s.Get("config", "w3").Int())
+	_, ok := s.CheckGet("config", "local")
+	assert.Equal(t, false, ok)
+
+	assert.Equal(t, nil, s.UnmarshalJSON([]byte(`{"a":1}`)))
+	assert.Equal(t, []string{"a"}, s.Keys())
+	s.Del("a")
+	assert.Equal(t, map[string]interface{}{}, s.Interface())
+}
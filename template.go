@@ -0,0 +1,198 @@
+package simplejson
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// TemplateFuncs returns functions querying `j` from Go templates, which take
+// dotted paths relative to `j`, the empty path being `j` itself:
+//
+//	get PATH             the *JSON at PATH, to call its methods
+//	getString PATH [DEF] the string at PATH, or DEF
+//	getInt PATH [DEF]    the int at PATH, or DEF
+//	getFloat PATH [DEF]  the float64 at PATH, or DEF
+//	getBool PATH [DEF]   the bool at PATH, or DEF
+//	exists PATH          whether a value is found at PATH
+//	keys PATH            the sorted keys of the object at PATH
+//	items PATH           the elements of the array at PATH as *JSON
+//	toJSON PATH          the encoding of the value at PATH
+//
+// The functions can be used with html/template as well:
+//
+//	t := template.New("config").Funcs(simplejson.TemplateFuncs(js))
+//	{{ range items "servers" }}{{ (.Get "host").String }}:{{ (.Get "port").Int 80 }}{{ end }}
+func TemplateFuncs(j *JSON) template.FuncMap {
+	at := func(path string) *JSON {
+		return j.Get(dottedBranch(j.data, path)...)
+	}
+	return template.FuncMap{
+		"get": at,
+		"getString": func(path string, def ...string) string {
+			return at(path).String(def...)
+		},
+		"getInt": func(path string, def ...int) int {
+			return at(path).Int(def...)
+		},
+		"getFloat": func(path string, def ...float64) float64 {
+			return at(path).Float64(def...)
+		},
+		"getBool": func(path string, def ...bool) bool {
+			return at(path).Bool(def...)
+		},
+		"exists": func(path string) bool {
+			_, ok := j.CheckGet(dottedBranch(j.data, path)...)
+			return ok
+		},
+		"keys": func(path string) []string {
+			keys := at(path).Keys()
+			sort.Strings(keys)
+			return keys
+		},
+		"items": func(path string) []*JSON {
+			return at(path).JSONArray()
+		},
+		"toJSON": func(path string) (string, error) {
+			b, err := at(path).Encode()
+			return string(b), err
+		},
+	}
+}
+
+// Template is a document whose string values hold `{{path}}` placeholders,
+// filled by Render with the values found at their dotted paths
+type Template struct {
+	data         interface{}
+	placeholders []string
+}
+
+// NewTemplate parses `body` as a template. A string made of a single
+// placeholder takes the value filling it, keeping its type, placeholders
+// within longer strings are replaced by the text of scalar values:
+//
+//	{"to": "{{user.email}}", "count": "{{count}}", "subject": "Hello {{user.name}}"}
+func NewTemplate(body []byte) (*Template, error) {
+	j, err := NewJSON(body)
+	if err != nil {
+		return nil, err
+	}
+	t := &Template{data: j.data}
+	seen := make(map[string]bool)
+	_, err = renderTemplate(t.data, nil, func(name string) (interface{}, error) {
+		if !seen[name] {
+			seen[name] = true
+			t.placeholders = append(t.placeholders, name)
+		}
+		return "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(t.placeholders)
+	return t, nil
+}
+
+// Placeholders returns the sorted paths of the placeholders of the template
+func (t *Template) Placeholders() []string {
+	return append([]string(nil), t.placeholders...)
+}
+
+// Render returns a new document from the template with its placeholders
+// filled from `values`, failing with the list of the placeholders not found
+// in `values` if any. Null values fill placeholders too.
+func (t *Template) Render(values *JSON) (*JSON, error) {
+	values = values.orMissing()
+	var missing []string
+	for _, name := range t.placeholders {
+		if _, _, ok := lookupDotted(values.data, name); !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("simplejson: unresolved template placeholders: %s", strings.Join(missing, ", "))
+	}
+
+	data, err := renderTemplate(t.data, nil, func(name string) (interface{}, error) {
+		val, _, _ := lookupDotted(values.data, name)
+		return deepCopy(val), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	j := &JSON{data: data}
+	j.document()
+	return j, nil
+}
+
+// renderTemplate returns a copy of `data`, found at `path`, with the
+// placeholders of its strings replaced by the values returned by `fill`
+func renderTemplate(data interface{}, path []interface{}, fill func(name string) (interface{}, error)) (interface{}, error) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			res, err := renderTemplate(val, append(path, key), fill)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = res
+		}
+		return m, nil
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, val := range v {
+			res, err := renderTemplate(val, append(path, i), fill)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = res
+		}
+		return a, nil
+	case string:
+		return renderString(v, path, fill)
+	}
+	return data, nil
+}
+
+func renderString(s string, path []interface{}, fill func(name string) (interface{}, error)) (interface{}, error) {
+	start := strings.Index(s, "{{")
+	if start < 0 {
+		return s, nil
+	}
+	if start == 0 && strings.Index(s, "}}") == len(s)-2 {
+		name := strings.TrimSpace(s[2 : len(s)-2])
+		if name == "" {
+			return nil, fmt.Errorf("simplejson: empty placeholder in %q at %s", s, formatPath(path))
+		}
+		return fill(name)
+	}
+
+	var b strings.Builder
+	for start >= 0 {
+		b.WriteString(s[:start])
+		end := strings.Index(s[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("simplejson: unterminated placeholder in %q at %s", s, formatPath(path))
+		}
+		placeholder := s[start : start+end+2]
+		name := strings.TrimSpace(placeholder[2 : len(placeholder)-2])
+		if name == "" {
+			return nil, fmt.Errorf("simplejson: empty placeholder in %q at %s", s, formatPath(path))
+		}
+		val, err := fill(name)
+		if err != nil {
+			return nil, err
+		}
+		str, err := placeholderString(val)
+		if err != nil {
+			return nil, fmt.Errorf("simplejson: placeholder %s at %s: %w", placeholder, formatPath(path), err)
+		}
+		b.WriteString(str)
+		s = s[start+end+2:]
+		start = strings.Index(s, "{{")
+	}
+	b.WriteString(s)
+	return b.String(), nil
+}
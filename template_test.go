@@ -0,0 +1,52 @@
+package simplejson
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"text/template"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	js, err := NewJSON([]byte(`{"name":"svc","debug":true,"servers":[{"host":"a","port":8080},{"host":"b"}],"env":{"B":"2","A":"1"}}`))
+	assert.Equal(t, nil, err)
+
+	tmpl, err := template.New("t").Funcs(TemplateFuncs(js)).Parse(
+		`{{ getString "name" }} {{ getString "missing" "def" }} {{ getBool "debug" }} {{ getInt "servers.0.port" }} {{ exists "servers.1.port" }}
+{{ range items "servers" }}{{ (.Get "host").String }}:{{ (.Get "port").Int 80 }} {{ end }}
+{{ range keys "env" }}{{ . }}={{ getString (printf "env.%s" .) }} {{ end }}
+{{ toJSON "env" }} {{ len (get "servers").Array }}`)
+	assert.Equal(t, nil, err)
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, tmpl.Execute(&buf, nil))
+	assert.Equal(t, "svc def true 8080 false\na:8080 b:80 \nA=1 B=2 \n{\"A\":\"1\",\"B\":\"2\"} 2", buf.String())
+}
+
+func TestNewTemplate(t *testing.T) {
+	tmpl, err := NewTemplate([]byte(`{"to":"{{user.email}}","count":"{{ count }}","subject":"Hi {{user.name}}, {{count}} new","tags":["{{tags.0}}","x"],"plain":1}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"count", "tags.0", "user.email", "user.name"}, tmpl.Placeholders())
+
+	js, err := tmpl.Render(mustJSON(`{"user":{"email":"a@b.c","name":"Ann"},"count":3,"tags":[{"k":1}]}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"count":3,"plain":1,"subject":"Hi Ann, 3 new","tags":[{"k":1},"x"],"to":"a@b.c"}`, string(mustEncode(t, js)))
+
+	// the template is reusable
+	js, err = tmpl.Render(mustJSON(`{"user":{"email":null,"name":"Bob"},"count":0,"tags":["t"]}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"count":0,"plain":1,"subject":"Hi Bob, 0 new","tags":["t","x"],"to":null}`, string(mustEncode(t, js)))
+
+	_, err = tmpl.Render(mustJSON(`{"user":{"name":"Ann"}}`))
+	assert.Equal(t, "simplejson: unresolved template placeholders: count, tags.0, user.email", err.Error())
+	_, err = tmpl.Render(mustJSON(`{"user":{"email":"a","name":{}},"count":1,"tags":[1]}`))
+	assert.Equal(t, "simplejson: placeholder {{user.name}} at subject: cannot substitute an object or array within a string", err.Error())
+	assert.Equal(t, "cannot substitute an object or array within a string", errors.Unwrap(err).Error())
+
+	for _, body := range []string{`{"a":"x {{b"}`, `{"a":"{{}}"}`, `{"a":`} {
+		_, err := NewTemplate([]byte(body))
+		assert.NotEqual(t, nil, err, body)
+	}
+}
@@ -0,0 +1,43 @@
+package simplejson
+
+import "time"
+
+// NormalizeTimes rewrites the strings of the document parsed by one of
+// `layouts`, tried in order, as times formatted with the `target` layout in
+// UTC, so timestamps gathered from several sources compare and sort alike:
+//
+//	js.NormalizeTimes([]string{time.RFC1123Z, "2006-01-02 15:04:05"}, time.RFC3339)
+//
+// Times parsed without a zone are taken as UTC. Other strings are untouched.
+func (j *JSON) NormalizeTimes(layouts []string, target string) {
+	if j == nil {
+		return
+	}
+	j.checkWritable()
+	j.replace(normalizeTimes(j.data, layouts, target))
+}
+
+// normalizeTimes returns a copy of `data` with times rewritten
+func normalizeTimes(data interface{}, layouts []string, target string) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[key] = normalizeTimes(val, layouts, target)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, val := range v {
+			a[i] = normalizeTimes(val, layouts, target)
+		}
+		return a
+	case string:
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t.UTC().Format(target)
+			}
+		}
+	}
+	return data
+}
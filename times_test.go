@@ -0,0 +1,24 @@
+package simplejson
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestNormalizeTimes(t *testing.T) {
+	js := mustJSON(`{
+		"a": "Mon, 02 Jan 2006 15:04:05 -0700",
+		"b": ["2006-01-02 22:04:05", "2006-01-02T23:04:05+01:00"],
+		"c": "not a time",
+		"d": 1136239445
+	}`)
+	js.NormalizeTimes([]string{time.RFC1123Z, "2006-01-02 15:04:05", time.RFC3339}, time.RFC3339)
+
+	want := "2006-01-02T22:04:05Z"
+	assert.Equal(t, want, js.Get("a").String())
+	assert.Equal(t, []interface{}{want, want}, js.Get("b").Array())
+	assert.Equal(t, "not a time", js.Get("c").String())
+	assert.Equal(t, int64(1136239445), js.Get("d").Int64())
+}
@@ -0,0 +1,92 @@
+package simplejson
+
+import (
+	"fmt"
+	"log"
+)
+
+// Tracer receives debugging events from a document, to find out why
+// a lookup came back empty or a default value was used
+type Tracer interface {
+	// Miss is called when nothing is found at `path`
+	Miss(path []interface{})
+	// Mismatch is called when the value at `path` is not a `want`,
+	// and an accessor falls back to its default
+	Mismatch(path []interface{}, want string, got interface{})
+	// Set is called after every modification of the document
+	Set(c Change)
+}
+
+// SetTracer makes the document of `j` report events to `t`, nil disables tracing.
+//
+// Like OnChange, tracing makes the document track the location of the views
+// obtained from it, so Get chains allocate a view per step.
+func (j *JSON) SetTracer(t Tracer) {
+	if j == nil {
+		return
+	}
+	d := j.document()
+	d.tracer = t
+	if t != nil {
+		d.track = true
+	}
+}
+
+// LogTracer returns a Tracer printing events to `l`, or to the standard logger if nil
+func LogTracer(l *log.Logger) Tracer {
+	return logTracer{l}
+}
+
+type logTracer struct {
+	l *log.Logger
+}
+
+func (t logTracer) printf(format string, args ...interface{}) {
+	if t.l == nil {
+		log.Printf(format, args...)
+		return
+	}
+	t.l.Printf(format, args...)
+}
+
+func (t logTracer) Miss(path []interface{}) {
+	t.printf("simplejson: %s requested but absent", formatPath(path))
+}
+
+func (t logTracer) Mismatch(path []interface{}, want string, got interface{}) {
+	t.printf("simplejson: %s is %s, not %s", formatPath(path), jsonType(got), want)
+}
+
+func (t logTracer) Set(c Change) {
+	t.printf("simplejson: %s %s = %s", c.Op, formatPath(c.Path), formatValue(c.New))
+}
+
+// formatValue formats `v` for logs
+func formatValue(v interface{}) string {
+	b, err := codec.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// traceMiss reports that `branch` was not found below `j`
+func (j *JSON) traceMiss(branch []interface{}) {
+	if j == nil || j.doc == nil || j.doc.tracer == nil {
+		return
+	}
+	if base, ok := j.location(); ok {
+		j.doc.tracer.Miss(append(base, branch...))
+	}
+}
+
+// traceMismatch reports that the value of `j` is not a `want`,
+// missing values were reported by traceMiss already
+func (j *JSON) traceMismatch(want string) {
+	if j == nil || j.doc == nil || j.doc.tracer == nil || j.data == nil {
+		return
+	}
+	if path, ok := j.location(); ok {
+		j.doc.tracer.Mismatch(path, want, j.data)
+	}
+}
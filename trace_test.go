@@ -0,0 +1,63 @@
+package simplejson
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+type recordingTracer struct {
+	events []string
+}
+
+func (t *recordingTracer) Miss(path []interface{}) {
+	t.events = append(t.events, "miss "+formatPath(path))
+}
+
+func (t *recordingTracer) Mismatch(path []interface{}, want string, got interface{}) {
+	t.events = append(t.events, "mismatch "+formatPath(path)+" "+want)
+}
+
+func (t *recordingTracer) Set(c Change) {
+	t.events = append(t.events, c.Op.String()+" "+formatPath(c.Path))
+}
+
+func TestTracer(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"b":"x","n":1},"list":[1]}`))
+	assert.Equal(t, nil, err)
+	tr := &recordingTracer{}
+	js.SetTracer(tr)
+
+	assert.Equal(t, "def", js.Get("a").Get("c").String("def"))
+	assert.Equal(t, 0, js.Get("a", "b").Int())
+	assert.Equal(t, "x", js.Get("a", "b").String())
+	_, ok := js.CheckGet("list", 3)
+	assert.Equal(t, false, ok)
+	js.Get("a").Set("n", 2)
+	js.Get("list").Set("k", 1)
+
+	assert.Equal(t, []string{
+		"miss a.c",
+		"mismatch a.b number",
+		"miss list[3]",
+		"replace a.n",
+		"mismatch list object",
+	}, tr.events)
+
+	js.SetTracer(nil)
+	js.Get("missing")
+	assert.Equal(t, 5, len(tr.events))
+}
+
+func TestLogTracer(t *testing.T) {
+	var buf bytes.Buffer
+	js, _ := NewJSON([]byte(`{"a":1}`))
+	js.SetTracer(LogTracer(log.New(&buf, "", 0)))
+
+	js.Get("b").String()
+	js.Get("a").Bool()
+	js.Set("a", "v")
+	assert.Equal(t, "simplejson: b requested but absent\nsimplejson: a is number, not bool\nsimplejson: replace a = \"v\"\n", buf.String())
+}
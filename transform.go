@@ -0,0 +1,948 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Transform runs the jq program `program` over the document and returns its result.
+//
+// A practical subset of jq is supported:
+//
+//	paths         . .a .a.b ."some key" .[0] .[-1] .[] .a[]? .[1:3]
+//	pipes         f | g, f, g
+//	construction  [f] {a: f, "b": g, c, (f): g}
+//	literals      numbers, "strings", true, false, null
+//	operators     + - * / % == != < <= > >= and or //
+//	functions     select(f) map(f) keys length not has(k) empty add type sort
+//	              sort_by(f) first last to_entries from_entries tostring tonumber
+//
+// Objects are iterated in sorted key order. jq programs produce streams of values,
+// the result is null if none is produced and an error if several are, wrap the
+// program in [ ] to collect them into an array.
+func (j *JSON) Transform(program string) (*JSON, error) {
+	j = j.orMissing()
+	p := &jqParser{exprParser{name: "transform", expr: program, ops: jqOperators}}
+	p.next()
+	f, err := p.pipe()
+	if err == nil && p.tok.kind != tokEOF {
+		err = p.errorf("unexpected %s", p.tok)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out, err := f(j.data)
+	if err != nil {
+		return nil, fmt.Errorf("simplejson: transform %q: %w", program, err)
+	}
+	switch len(out) {
+	case 0:
+		return j.wrap(nil), nil
+	case 1:
+		return j.wrap(out[0]), nil
+	}
+	return nil, fmt.Errorf("simplejson: transform %q: produced %d values, collect them with [ ]", program, len(out))
+}
+
+var jqOperators = []string{"|", "//", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "(", ")", "[", "]", "{", "}", ",", ".", ":", ";", "?"}
+
+// jqFilter produces the outputs of a jq filter for an input
+type jqFilter func(in interface{}) ([]interface{}, error)
+
+// jqParser compiles jq programs into filters
+type jqParser struct {
+	exprParser
+}
+
+func (p *jqParser) isOp(op string) bool {
+	return p.err == nil && p.tok.kind == tokOp && p.tok.text == op
+}
+
+// pipe parses `f | g`, the lowest precedence construct
+func (p *jqParser) pipe() (jqFilter, error) {
+	left, err := p.comma()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("|") {
+		p.next()
+		right, err := p.comma()
+		if err != nil {
+			return nil, err
+		}
+		left = jqPipe(left, right)
+	}
+	return left, p.err
+}
+
+func jqPipe(left, right jqFilter) jqFilter {
+	return func(in interface{}) ([]interface{}, error) {
+		vals, err := left(in)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, v := range vals {
+			res, err := right(v)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, res...)
+		}
+		return out, nil
+	}
+}
+
+// comma parses `f, g`
+func (p *jqParser) comma() (jqFilter, error) {
+	left, err := p.binary(0)
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp(",") {
+		p.next()
+		right, err := p.binary(0)
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(in interface{}) ([]interface{}, error) {
+			a, err := l(in)
+			if err != nil {
+				return nil, err
+			}
+			b, err := right(in)
+			return append(a, b...), err
+		}
+	}
+	return left, p.err
+}
+
+// jq binary operators by precedence, from lowest to highest
+var jqPrecedence = map[string]int{
+	"//":  1,
+	"or":  2,
+	"and": 3,
+	"==":  4, "!=": 4, "<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+// binary parses operators of at least `min` precedence
+func (p *jqParser) binary(min int) (jqFilter, error) {
+	left, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+	for p.err == nil && (p.tok.kind == tokOp || p.tok.kind == tokIdent) {
+		prec, ok := jqPrecedence[p.tok.text]
+		if !ok || prec <= min {
+			break
+		}
+		op := p.tok.text
+		p.next()
+		right, err := p.binary(prec)
+		if err != nil {
+			return nil, err
+		}
+		left = jqBinary(op, left, right)
+	}
+	return left, p.err
+}
+
+func jqBinary(op string, left, right jqFilter) jqFilter {
+	return func(in interface{}) ([]interface{}, error) {
+		lvals, err := left(in)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, l := range lvals {
+			switch {
+			case op == "and" && !truthy(l), op == "or" && truthy(l):
+				out = append(out, op == "or")
+				continue
+			case op == "//" && truthy(l):
+				out = append(out, l)
+				continue
+			}
+			rvals, err := right(in)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range rvals {
+				var v interface{}
+				switch op {
+				case "and", "or":
+					v = truthy(r)
+				case "//":
+					v = r
+				case "==":
+					v = deepEqual(l, r)
+				case "!=":
+					v = !deepEqual(l, r)
+				case "<", "<=", ">", ">=":
+					v, err = compare(op, l, r)
+				default:
+					v, err = jqArithmetic(op, l, r)
+				}
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, v)
+			}
+		}
+		return out, nil
+	}
+}
+
+// jqArithmetic extends arithmetic with the jq rules for null,
+// arrays and objects
+func jqArithmetic(op string, l, r interface{}) (interface{}, error) {
+	if op == "+" {
+		switch {
+		case l == nil:
+			return r, nil
+		case r == nil:
+			return l, nil
+		}
+		switch a := l.(type) {
+		case []interface{}:
+			if b, ok := r.([]interface{}); ok {
+				return append(append([]interface{}{}, a...), b...), nil
+			}
+		case map[string]interface{}:
+			if b, ok := r.(map[string]interface{}); ok {
+				m := make(map[string]interface{}, len(a)+len(b))
+				for k, v := range a {
+					m[k] = v
+				}
+				for k, v := range b {
+					m[k] = v
+				}
+				return m, nil
+			}
+		}
+	}
+	return arithmetic(op, l, r)
+}
+
+// postfix parses a term followed by path suffixes
+func (p *jqParser) postfix() (jqFilter, error) {
+	f, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.err == nil {
+		switch {
+		case p.isOp("."):
+			p.next()
+			key, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			f = jqPipe(f, jqIndex(key))
+		case p.isOp("["):
+			suffix, err := p.brackets()
+			if err != nil {
+				return nil, err
+			}
+			f = jqPipe(f, suffix)
+		case p.isOp("?"):
+			p.next()
+			f = jqTry(f)
+		default:
+			return f, nil
+		}
+	}
+	return f, p.err
+}
+
+// key parses the key following a "."
+func (p *jqParser) key() (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+	if p.tok.kind != tokIdent && p.tok.kind != tokString {
+		return "", p.errorf("expected a key after \".\", found %s", p.tok)
+	}
+	key := p.tok.text
+	p.next()
+	return key, p.err
+}
+
+// brackets parses [], [f] and [f:g] suffixes
+func (p *jqParser) brackets() (jqFilter, error) {
+	p.next()
+	if p.isOp("]") {
+		p.next()
+		return jqIterate, p.err
+	}
+	var from, to jqFilter
+	var err error
+	if !p.isOp(":") {
+		if from, err = p.pipe(); err != nil {
+			return nil, err
+		}
+	}
+	slice := p.isOp(":")
+	if slice {
+		p.next()
+		if !p.isOp("]") {
+			if to, err = p.pipe(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	if slice {
+		return jqSlice(from, to), nil
+	}
+	return func(in interface{}) ([]interface{}, error) {
+		keys, err := from(in)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, k := range keys {
+			res, err := jqIndexValue(in, k)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, res)
+		}
+		return out, nil
+	}, nil
+}
+
+func jqIndex(key string) jqFilter {
+	return func(in interface{}) ([]interface{}, error) {
+		v, err := jqIndexValue(in, key)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{v}, nil
+	}
+}
+
+// jqIndexValue returns the value at `key` of `in`, null if there is none
+func jqIndexValue(in, key interface{}) (interface{}, error) {
+	if in == nil {
+		return nil, nil
+	}
+	switch k := key.(type) {
+	case string:
+		if m, ok := in.(map[string]interface{}); ok {
+			return m[k], nil
+		}
+	case json.Number:
+		if a, ok := in.([]interface{}); ok {
+			i, err := strconv.Atoi(k.String())
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %s", k)
+			}
+			v, _ := getIndex(a, i)
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot index %s with %s", jsonType(in), jsonType(key))
+}
+
+func jqIterate(in interface{}) ([]interface{}, error) {
+	switch v := in.(type) {
+	case []interface{}:
+		return append([]interface{}{}, v...), nil
+	case map[string]interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, k := range sortedKeys(v) {
+			out = append(out, v[k])
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("cannot iterate over %s", jsonType(in))
+}
+
+func jqSlice(from, to jqFilter) jqFilter {
+	return func(in interface{}) ([]interface{}, error) {
+		var n int
+		switch v := in.(type) {
+		case nil:
+			return []interface{}{nil}, nil
+		case []interface{}:
+			n = len(v)
+		case string:
+			n = utf8.RuneCountInString(v)
+		default:
+			return nil, fmt.Errorf("cannot slice %s", jsonType(in))
+		}
+		i, err := jqSliceBound(from, in, n, 0)
+		if err != nil {
+			return nil, err
+		}
+		k, err := jqSliceBound(to, in, n, n)
+		if err != nil {
+			return nil, err
+		}
+		if k < i {
+			k = i
+		}
+		if s, ok := in.(string); ok {
+			return []interface{}{string([]rune(s)[i:k])}, nil
+		}
+		return []interface{}{append([]interface{}{}, in.([]interface{})[i:k]...)}, nil
+	}
+}
+
+// jqSliceBound returns a bound of a slice of `in` of length `n`,
+// `def` if `f` is missing
+func jqSliceBound(f jqFilter, in interface{}, n, def int) (int, error) {
+	if f == nil {
+		return def, nil
+	}
+	vals, err := f(in)
+	if err != nil {
+		return 0, err
+	}
+	if len(vals) != 1 {
+		return 0, fmt.Errorf("slice bounds must be single values")
+	}
+	if vals[0] == nil {
+		return def, nil
+	}
+	r, ok := numberRat(vals[0])
+	if !ok || !r.IsInt() {
+		return 0, fmt.Errorf("slice bounds must be integers, got %s", jsonType(vals[0]))
+	}
+	i := int(r.Num().Int64())
+	if i < 0 {
+		i += n
+	}
+	if i < 0 {
+		return 0, nil
+	}
+	if i > n {
+		return n, nil
+	}
+	return i, nil
+}
+
+// jqTry suppresses the errors of `f`
+func jqTry(f jqFilter) jqFilter {
+	return func(in interface{}) ([]interface{}, error) {
+		out, err := f(in)
+		if err != nil {
+			return nil, nil
+		}
+		return out, nil
+	}
+}
+
+// jqCollect gathers the outputs of `f` into an array
+func jqCollect(f jqFilter) jqFilter {
+	return func(in interface{}) ([]interface{}, error) {
+		out, err := f(in)
+		if err != nil {
+			return nil, err
+		}
+		if out == nil {
+			out = []interface{}{}
+		}
+		return []interface{}{out}, nil
+	}
+}
+
+func jqIdentity(in interface{}) ([]interface{}, error) {
+	return []interface{}{in}, nil
+}
+
+func jqConst(v interface{}) jqFilter {
+	return func(interface{}) ([]interface{}, error) {
+		return []interface{}{v}, nil
+	}
+}
+
+// term parses the operands of jq expressions
+func (p *jqParser) term() (jqFilter, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	tok := p.tok
+	switch tok.kind {
+	case tokNumber:
+		if !isValidNumber(tok.text) {
+			return nil, p.errorf("invalid number %s", tok)
+		}
+		p.next()
+		return jqConst(json.Number(tok.text)), p.err
+	case tokString:
+		p.next()
+		return jqConst(tok.text), p.err
+	case tokIdent:
+		return p.function()
+	}
+
+	switch tok.text {
+	case ".":
+		p.next()
+		switch {
+		case p.err == nil && p.tok.pos == tok.pos+1 && (p.tok.kind == tokIdent || p.tok.kind == tokString):
+			key, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			return jqIndex(key), nil
+		case p.isOp("["):
+			return p.brackets()
+		}
+		return jqIdentity, p.err
+	case "-":
+		p.next()
+		f, err := p.postfix()
+		if err != nil {
+			return nil, err
+		}
+		return jqBinary("-", jqConst(json.Number("0")), f), nil
+	case "(":
+		p.next()
+		f, err := p.pipe()
+		if err != nil {
+			return nil, err
+		}
+		return f, p.expect(")")
+	case "[":
+		p.next()
+		if p.isOp("]") {
+			p.next()
+			return func(interface{}) ([]interface{}, error) {
+				return []interface{}{[]interface{}{}}, nil
+			}, p.err
+		}
+		f, err := p.pipe()
+		if err != nil {
+			return nil, err
+		}
+		return jqCollect(f), p.expect("]")
+	case "{":
+		return p.object()
+	}
+	return nil, p.errorf("unexpected %s", tok)
+}
+
+type jqEntry struct {
+	key, val jqFilter
+}
+
+// object parses object construction
+func (p *jqParser) object() (jqFilter, error) {
+	p.next()
+	var entries []jqEntry
+	for !p.isOp("}") {
+		if p.err != nil {
+			return nil, p.err
+		}
+		if len(entries) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		var e jqEntry
+		var name string
+		switch {
+		case p.tok.kind == tokIdent || p.tok.kind == tokString:
+			name = p.tok.text
+			e.key = jqConst(name)
+			p.next()
+		case p.isOp("("):
+			p.next()
+			key, err := p.pipe()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			e.key = key
+		default:
+			return nil, p.errorf("expected an object key, found %s", p.tok)
+		}
+		if p.isOp(":") {
+			p.next()
+			val, err := p.binary(0)
+			if err != nil {
+				return nil, err
+			}
+			e.val = val
+		} else if name != "" {
+			e.val = jqIndex(name)
+		} else {
+			return nil, p.errorf("expected \":\", found %s", p.tok)
+		}
+		entries = append(entries, e)
+	}
+	p.next()
+
+	return func(in interface{}) ([]interface{}, error) {
+		objs := []map[string]interface{}{{}}
+		for _, e := range entries {
+			keys, err := e.key(in)
+			if err != nil {
+				return nil, err
+			}
+			vals, err := e.val(in)
+			if err != nil {
+				return nil, err
+			}
+			var next []map[string]interface{}
+			for _, obj := range objs {
+				for _, k := range keys {
+					s, ok := k.(string)
+					if !ok {
+						return nil, fmt.Errorf("object keys must be strings, got %s", jsonType(k))
+					}
+					for _, v := range vals {
+						m := make(map[string]interface{}, len(obj)+1)
+						for key, val := range obj {
+							m[key] = val
+						}
+						m[s] = v
+						next = append(next, m)
+					}
+				}
+			}
+			objs = next
+		}
+		out := make([]interface{}, len(objs))
+		for i, obj := range objs {
+			out[i] = obj
+		}
+		return out, nil
+	}, p.err
+}
+
+// function parses literals and calls of builtin functions
+func (p *jqParser) function() (jqFilter, error) {
+	name := p.tok
+	p.next()
+	switch name.text {
+	case "true":
+		return jqConst(true), p.err
+	case "false":
+		return jqConst(false), p.err
+	case "null":
+		return jqConst(nil), p.err
+	}
+
+	var args []jqFilter
+	if p.isOp("(") {
+		p.next()
+		for {
+			arg, err := p.pipe()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if !p.isOp(";") {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	fn, ok := jqFuncs[name.text]
+	if !ok {
+		return nil, p.errorAt(name.pos, "unknown function %s/%d", name.text, len(args))
+	}
+	if len(args) != fn.arity {
+		return nil, p.errorAt(name.pos, "%s takes %d arguments, got %d", name.text, fn.arity, len(args))
+	}
+	f := fn.fn(args)
+	return func(in interface{}) ([]interface{}, error) {
+		out, err := f(in)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name.text, err)
+		}
+		return out, nil
+	}, p.err
+}
+
+type jqFunc struct {
+	arity int
+	fn    func(args []jqFilter) jqFilter
+}
+
+// jqValue adapts a function of the input returning a single value
+func jqValue(fn func(in interface{}) (interface{}, error)) func([]jqFilter) jqFilter {
+	return func([]jqFilter) jqFilter {
+		return func(in interface{}) ([]interface{}, error) {
+			v, err := fn(in)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{v}, nil
+		}
+	}
+}
+
+var jqFuncs map[string]jqFunc
+
+func init() {
+	jqFuncs = map[string]jqFunc{
+		"select": {1, func(args []jqFilter) jqFilter {
+			return func(in interface{}) ([]interface{}, error) {
+				conds, err := args[0](in)
+				if err != nil {
+					return nil, err
+				}
+				var out []interface{}
+				for _, c := range conds {
+					if truthy(c) {
+						out = append(out, in)
+					}
+				}
+				return out, nil
+			}
+		}},
+		"map": {1, func(args []jqFilter) jqFilter {
+			return jqCollect(jqPipe(jqIterate, args[0]))
+		}},
+		"empty": {0, func([]jqFilter) jqFilter {
+			return func(interface{}) ([]interface{}, error) {
+				return nil, nil
+			}
+		}},
+		"not": {0, jqValue(func(in interface{}) (interface{}, error) {
+			return !truthy(in), nil
+		})},
+		"keys": {0, jqValue(func(in interface{}) (interface{}, error) {
+			switch v := in.(type) {
+			case map[string]interface{}:
+				return stringsToValues(sortedKeys(v)), nil
+			case []interface{}:
+				out := make([]interface{}, len(v))
+				for i := range v {
+					out[i] = json.Number(strconv.Itoa(i))
+				}
+				return out, nil
+			}
+			return nil, fmt.Errorf("%s has no keys", jsonType(in))
+		})},
+		"length": {0, jqValue(func(in interface{}) (interface{}, error) {
+			if n, ok := numberRat(in); ok {
+				return ratNumber(n.Abs(n)), nil
+			}
+			return exprFuncs["len"].fn([]interface{}{in})
+		})},
+		"has": {1, func(args []jqFilter) jqFilter {
+			return func(in interface{}) ([]interface{}, error) {
+				keys, err := args[0](in)
+				if err != nil {
+					return nil, err
+				}
+				out := make([]interface{}, len(keys))
+				for i, k := range keys {
+					switch v := in.(type) {
+					case map[string]interface{}:
+						s, ok := k.(string)
+						if !ok {
+							return nil, fmt.Errorf("cannot check whether object has a key of type %s", jsonType(k))
+						}
+						_, out[i] = v[s]
+					case []interface{}:
+						n, ok := numberRat(k)
+						if !ok {
+							return nil, fmt.Errorf("cannot check whether array has a key of type %s", jsonType(k))
+						}
+						out[i] = n.Sign() >= 0 && n.Cmp(new(big.Rat).SetInt64(int64(len(v)))) < 0
+					default:
+						return nil, fmt.Errorf("cannot check whether %s has a key", jsonType(in))
+					}
+				}
+				return out, nil
+			}
+		}},
+		"add": {0, jqValue(func(in interface{}) (interface{}, error) {
+			vals, err := jqIterate(in)
+			if err != nil {
+				return nil, err
+			}
+			var sum interface{}
+			for _, v := range vals {
+				if sum, err = jqArithmetic("+", sum, v); err != nil {
+					return nil, err
+				}
+			}
+			return sum, nil
+		})},
+		"type": {0, jqValue(func(in interface{}) (interface{}, error) {
+			if t := jsonType(in); t != "bool" {
+				return t, nil
+			}
+			return "boolean", nil
+		})},
+		"sort": {0, jqValue(func(in interface{}) (interface{}, error) {
+			return jqSort(in, nil)
+		})},
+		"sort_by": {1, func(args []jqFilter) jqFilter {
+			return jqValue(func(in interface{}) (interface{}, error) {
+				return jqSort(in, args[0])
+			})(nil)
+		}},
+		"first": {0, jqValue(func(in interface{}) (interface{}, error) {
+			return jqIndexValue(in, json.Number("0"))
+		})},
+		"last": {0, jqValue(func(in interface{}) (interface{}, error) {
+			return jqIndexValue(in, json.Number("-1"))
+		})},
+		"to_entries": {0, jqValue(func(in interface{}) (interface{}, error) {
+			m, ok := in.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s has no entries", jsonType(in))
+			}
+			out := make([]interface{}, 0, len(m))
+			for _, k := range sortedKeys(m) {
+				out = append(out, map[string]interface{}{"key": k, "value": m[k]})
+			}
+			return out, nil
+		})},
+		"from_entries": {0, jqValue(func(in interface{}) (interface{}, error) {
+			a, ok := in.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot build an object from %s", jsonType(in))
+			}
+			out := make(map[string]interface{}, len(a))
+			for _, e := range a {
+				m, _ := e.(map[string]interface{})
+				k, ok := m["key"].(string)
+				if !ok {
+					return nil, fmt.Errorf("entries must have a string key")
+				}
+				out[k] = m["value"]
+			}
+			return out, nil
+		})},
+		"tostring": {0, jqValue(func(in interface{}) (interface{}, error) {
+			if s, ok := in.(string); ok {
+				return s, nil
+			}
+			b, err := newEncodeConfig(nil).encode(in)
+			return string(b), err
+		})},
+		"tonumber": {0, jqValue(func(in interface{}) (interface{}, error) {
+			if _, ok := numberRat(in); ok {
+				return in, nil
+			}
+			if s, ok := in.(string); ok && isValidNumber(s) {
+				return json.Number(s), nil
+			}
+			return nil, fmt.Errorf("cannot parse %s as a number", jsonType(in))
+		})},
+	}
+}
+
+// jqSort sorts the array `in` by the values of `by`, or the elements themselves
+func jqSort(in interface{}, by jqFilter) (interface{}, error) {
+	a, ok := in.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot sort %s", jsonType(in))
+	}
+	keys := make([]interface{}, len(a))
+	for i, v := range a {
+		keys[i] = v
+		if by != nil {
+			k, err := jqCollect(by)(v)
+			if err != nil {
+				return nil, err
+			}
+			keys[i] = k[0]
+		}
+	}
+	idx := make([]int, len(a))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(x, y int) bool {
+		return jqCompare(keys[idx[x]], keys[idx[y]]) < 0
+	})
+	out := make([]interface{}, len(a))
+	for i, k := range idx {
+		out[i] = a[k]
+	}
+	return out, nil
+}
+
+// jqOrder ranks the types of values in the order of jq
+func jqOrder(v interface{}) int {
+	switch v {
+	case nil:
+		return 0
+	case false:
+		return 1
+	case true:
+		return 2
+	}
+	switch v.(type) {
+	case string:
+		return 4
+	case []interface{}:
+		return 5
+	case map[string]interface{}:
+		return 6
+	}
+	return 3
+}
+
+// jqCompare orders values as jq does, null < false < true < numbers
+// < strings < arrays < objects
+func jqCompare(a, b interface{}) int {
+	if x, y := jqOrder(a), jqOrder(b); x != y {
+		return x - y
+	}
+	switch x := a.(type) {
+	case string:
+		return strings.Compare(x, b.(string))
+	case []interface{}:
+		y := b.([]interface{})
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := jqCompare(x[i], y[i]); c != 0 {
+				return c
+			}
+		}
+		return len(x) - len(y)
+	case map[string]interface{}:
+		y := b.(map[string]interface{})
+		xk, yk := sortedKeys(x), sortedKeys(y)
+		if c := jqCompare(stringsToValues(xk), stringsToValues(yk)); c != 0 {
+			return c
+		}
+		for _, k := range xk {
+			if c := jqCompare(x[k], y[k]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+	x, xok := numberRat(a)
+	y, yok := numberRat(b)
+	if xok && yok {
+		return x.Cmp(y)
+	}
+	return 0
+}
+
+func stringsToValues(s []string) []interface{} {
+	out := make([]interface{}, len(s))
+	for i, v := range s {
+		out[i] = v
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+package simplejson
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestTransform(t *testing.T) {
+	js, err := NewJSON([]byte(`{
+		"users": [
+			{"name":"ann","age":31,"tags":["admin"],"team":{"id":1}},
+			{"name":"bob","age":25,"tags":[]},
+			{"name":"cy","age":40,"tags":["ops","admin"],"team":{"id":2}}
+		],
+		"meta": {"b":2,"a":1}
+	}`))
+	assert.Equal(t, nil, err)
+
+	cases := []struct {
+		program string
+		want    string
+	}{
+		{`.`, `{"meta":{"a":1,"b":2},"users":[{"age":31,"name":"ann","tags":["admin"],"team":{"id":1}},{"age":25,"name":"bob","tags":[]},{"age":40,"name":"cy","tags":["ops","admin"],"team":{"id":2}}]}`},
+		{`.users[0].name`, `"ann"`},
+		{`.users[-1].team.id`, `2`},
+		{`."meta".a`, `1`},
+		{`[.users[] | select(.age > 30) | .name]`, `["ann","cy"]`},
+		{`.users | map({name, admin: (.tags | has(0)), team: .team.id})`, `[{"admin":true,"name":"ann","team":1},{"admin":false,"name":"bob","team":null},{"admin":true,"name":"cy","team":2}]`},
+		{`[.users[] | .team.id // 0]`, `[1,0,2]`},
+		{`.users | map(.age) | add / length`, `32`},
+		{`[.meta[]]`, `[1,2]`},
+		{`.meta | keys`, `["a","b"]`},
+		{`.meta | to_entries | map({(.key): (.value * 10)}) | add`, `{"a":10,"b":20}`},
+		{`(.meta | to_entries | from_entries) == .meta`, `true`},
+		{`[.users[] | .name, .age] | .[1:3]`, `[31,"bob"]`},
+		{`.users | sort_by(.age) | map(.name) | first`, `"bob"`},
+		{`[.users[].tags[]] | sort`, `["admin","admin","ops"]`},
+		{`.users[1].tags | length == 0 and (. | type) == "array"`, `true`},
+		{`[.users[] | select((.tags | length) > 1 or .name == "bob") | .name]`, `["bob","cy"]`},
+		{`[.meta.a, "x", null, true] | map(type)`, `["number","string","null","boolean"]`},
+		{`.users[0].name + "-" + (.users[0].age | tostring)`, `"ann-31"`},
+		{`.missing.deep`, `null`},
+		{`[.users[0].name[]?]`, `[]`},
+		{`.users | map(select(.name == "none")) | first`, `null`},
+		{`[empty]`, `[]`},
+		{`{a: (1, 2)} | .a`, ``},
+		{`.users[0] | not`, `false`},
+		{`"42" | tonumber + 1`, `43`},
+		{`[1,[2]] + [3]`, `[1,[2],3]`},
+		{`-(.meta.b) * 2`, `-4`},
+	}
+	for _, c := range cases {
+		res, err := js.Transform(c.program)
+		if c.want == "" {
+			assert.NotEqual(t, nil, err, c.program)
+			continue
+		}
+		assert.Equal(t, nil, err, c.program)
+		b, _ := res.Encode()
+		assert.Equal(t, c.want, string(b), c.program)
+	}
+
+	for program, msg := range map[string]string{
+		`.users[]`:       `simplejson: transform ".users[]": produced 3 values, collect them with [ ]`,
+		`.users.name`:    `simplejson: transform ".users.name": cannot index array with string`,
+		`.users | foo`:   `simplejson: transform ".users | foo": offset 9: unknown function foo/0`,
+		`select(1; 2)`:   `simplejson: transform "select(1; 2)": offset 0: select takes 1 arguments, got 2`,
+		`{a: 1`:          `simplejson: transform "{a: 1": offset 5: expected ",", found end of expression`,
+		`.meta | keys[`:  `simplejson: transform ".meta | keys[": offset 13: unexpected end of expression`,
+		`.meta.a | keys`: `simplejson: transform ".meta.a | keys": keys: number has no keys`,
+	} {
+		_, err := js.Transform(program)
+		assert.Equal(t, msg, err.Error(), program)
+	}
+
+	_, err = js.Transform(".meta.a | keys")
+	assert.Equal(t, "keys: number has no keys", errors.Unwrap(err).Error())
+	assert.Equal(t, "number has no keys", errors.Unwrap(errors.Unwrap(err)).Error())
+}
@@ -0,0 +1,95 @@
+package simplejson
+
+import (
+	"encoding/json"
+
+	"github.com/brunotm/go-simplejson/scanner"
+)
+
+// treeBuilder is a scanner.Handler building the generic tree of a document,
+// with numbers decoded as json.Number
+type treeBuilder struct {
+	stack []frame
+	root  interface{}
+}
+
+// frame is a container under construction
+type frame struct {
+	object map[string]interface{}
+	array  []interface{}
+	key    string
+}
+
+// buildTree decodes the single value held in `raw`
+func buildTree(raw []byte) (interface{}, error) {
+	b := &treeBuilder{}
+	if err := scanner.Scan(raw, b); err != nil {
+		return nil, err
+	}
+	return b.root, nil
+}
+
+func (b *treeBuilder) add(val interface{}) {
+	if len(b.stack) == 0 {
+		b.root = val
+		return
+	}
+	top := &b.stack[len(b.stack)-1]
+	if top.object != nil {
+		top.object[top.key] = val
+		return
+	}
+	top.array = append(top.array, val)
+}
+
+func (b *treeBuilder) pop() interface{} {
+	top := b.stack[len(b.stack)-1]
+	b.stack = b.stack[:len(b.stack)-1]
+	if top.object != nil {
+		return top.object
+	}
+	return top.array
+}
+
+func (b *treeBuilder) OnObjectStart() error {
+	b.stack = append(b.stack, frame{object: make(map[string]interface{})})
+	return nil
+}
+
+func (b *treeBuilder) OnObjectEnd() error {
+	b.add(b.pop())
+	return nil
+}
+
+func (b *treeBuilder) OnArrayStart() error {
+	b.stack = append(b.stack, frame{array: []interface{}{}})
+	return nil
+}
+
+func (b *treeBuilder) OnArrayEnd() error {
+	b.add(b.pop())
+	return nil
+}
+
+func (b *treeBuilder) OnKey(key string) error {
+	b.stack[len(b.stack)-1].key = key
+	return nil
+}
+
+func (b *treeBuilder) OnValue(kind scanner.Kind, raw []byte) error {
+	switch kind {
+	case scanner.String:
+		s, err := scanner.Unquote(raw)
+		if err != nil {
+			return err
+		}
+		b.add(s)
+	case scanner.Number:
+		b.add(json.Number(raw))
+	case scanner.Bool:
+		b.add(raw[0] == 't')
+	case scanner.Null:
+		b.add(nil)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package simplejson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestBuildTree(t *testing.T) {
+	raw := []byte(`{"a": [1, "two", true, null, {"b": []}], "c": {"d\n": 1.5e3}}`)
+
+	tree, err := buildTree(raw)
+	assert.Equal(t, nil, err)
+
+	js, err := NewJSON(raw)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, js.Interface(), tree)
+	assert.Equal(t, json.Number("1.5e3"), tree.(map[string]interface{})["c"].(map[string]interface{})["d\n"])
+
+	_, err = buildTree([]byte(`{"a": [1, }`))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestNewJSONScanner(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":[1,"xé",true,null,{}],"n":12345678901234567890}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, json.Number("12345678901234567890"), js.Get("n").Interface())
+	assert.Equal(t, "xé", js.Get("a", 1).String())
+
+	// inputs rejected by encoding/json are rejected by the scanner
+	for _, raw := range []string{"{\"a\":\"\x01\"}", `{"a":"\x"}`, `{"a":1} x`, `[1,]`} {
+		_, err := NewJSON([]byte(raw))
+		assert.NotEqual(t, nil, err, raw)
+	}
+}
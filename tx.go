@@ -0,0 +1,75 @@
+package simplejson
+
+import "errors"
+
+// ErrTxDone is returned when committing or rolling back a finished transaction
+var ErrTxDone = errors.New("simplejson: transaction has already been committed or rolled back")
+
+// Tx is a set of modifications to a document applied all at once by Commit,
+// or discarded by Rollback. The document is left untouched until Commit.
+type Tx struct {
+	target *JSON
+	work   *JSON
+	done   bool
+}
+
+// Begin starts a transaction over the document.
+// Modifications made to the document itself while the transaction is open
+// are overwritten by Commit.
+func (j *JSON) Begin() *Tx {
+	work := j.Snapshot()
+	if j != nil && j.doc != nil && j.doc.strict {
+		work.StrictSet()
+	}
+	return &Tx{target: j, work: work}
+}
+
+// JSON returns the working copy of the document, holding the modifications
+// made so far, any mutator called on it is part of the transaction
+func (tx *Tx) JSON() *JSON {
+	return tx.work
+}
+
+// Get is like JSON.Get over the working copy of the document
+func (tx *Tx) Get(branch ...interface{}) *JSON {
+	return tx.work.Get(branch...)
+}
+
+// Set is like JSON.Set within the transaction
+func (tx *Tx) Set(key string, val interface{}) {
+	tx.work.Set(key, val)
+}
+
+// SetPath is like JSON.SetPath within the transaction
+func (tx *Tx) SetPath(branch []string, val interface{}) {
+	tx.work.SetPath(branch, val)
+}
+
+// Del is like JSON.Del within the transaction
+func (tx *Tx) Del(key string) {
+	tx.work.Del(key)
+}
+
+// Commit applies every modification of the transaction to the document
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	if tx.target.IsFrozen() {
+		return ErrFrozen
+	}
+	if err := tx.target.replace(tx.work.data); err != nil {
+		return err
+	}
+	tx.done = true
+	return nil
+}
+
+// Rollback discards every modification of the transaction
+func (tx *Tx) Rollback() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	return nil
+}
@@ -0,0 +1,56 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestTxCommit(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"b":1},"c":[1,2]}`))
+	assert.Equal(t, nil, err)
+
+	tx := js.Begin()
+	tx.Set("x", "y")
+	tx.SetPath([]string{"a", "b"}, 2)
+	tx.Del("c")
+	assert.Equal(t, 2, tx.Get("a", "b").Int())
+	assert.Equal(t, 1, js.Get("a", "b").Int())
+	assert.Equal(t, 2, len(js.Get("c").Array()))
+
+	assert.Equal(t, nil, tx.Commit())
+	assert.Equal(t, 2, js.Get("a", "b").Int())
+	assert.Equal(t, "y", js.Get("x").String())
+	_, ok := js.CheckGet("c")
+	assert.Equal(t, false, ok)
+	assert.Equal(t, ErrTxDone, tx.Commit())
+	assert.Equal(t, ErrTxDone, tx.Rollback())
+
+	// a transaction over a view commits into its parent
+	tx = js.Get("a").Begin()
+	tx.Set("b", 3)
+	assert.Equal(t, nil, tx.Commit())
+	assert.Equal(t, 3, js.Get("a", "b").Int())
+}
+
+func TestTxRollback(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"b":1}}`))
+	assert.Equal(t, nil, err)
+
+	tx := js.Begin()
+	tx.JSON().Get("a").Set("b", 2)
+	tx.Set("c", true)
+	assert.Equal(t, nil, tx.Rollback())
+	assert.Equal(t, `{"a":{"b":1}}`, string(mustEncode(t, js)))
+
+	js.Freeze()
+	tx = js.Begin()
+	tx.Set("c", true)
+	assert.Equal(t, ErrFrozen, tx.Commit())
+}
+
+func mustEncode(t *testing.T, js *JSON) []byte {
+	b, err := js.Encode()
+	assert.Equal(t, nil, err)
+	return b
+}
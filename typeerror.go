@@ -0,0 +1,38 @@
+package simplejson
+
+import "fmt"
+
+// TypeError reports a value that is not of the expected type
+type TypeError struct {
+	// Path locates the value within its document, nil when unknown
+	Path []interface{}
+	Want string
+	Got  string
+}
+
+func (e *TypeError) Error() string {
+	if e.Path == nil {
+		return fmt.Sprintf("simplejson: expected %s, got %s", e.Want, e.Got)
+	}
+	return fmt.Sprintf("simplejson: expected %s at %s, got %s", e.Want, formatPath(e.Path), e.Got)
+}
+
+// Expect returns nil if the value is of type `want`, or a *TypeError
+// locating it within its document otherwise, to report why a Check*
+// method failed:
+//
+//	name, ok := item.CheckString()
+//	if !ok {
+//		return item.Expect(StringValue)
+//		// simplejson: expected string at items[3].name, got number
+//	}
+//
+// Values are located as by Path. Missing values are null.
+func (j *JSON) Expect(want ValueType) error {
+	j = j.orMissing()
+	got := jsonType(j.data)
+	if got == want.String() {
+		return nil
+	}
+	return &TypeError{Path: j.Path(), Want: want.String(), Got: got}
+}
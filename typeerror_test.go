@@ -0,0 +1,35 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestExpect(t *testing.T) {
+	js := mustJSON(`{"items":[{"name":"a"},{"name":1},[true]]}`)
+
+	assert.Equal(t, nil, js.Expect(ObjectValue))
+	assert.Equal(t, nil, js.Get("items", 0, "name").Expect(StringValue))
+
+	err := js.Get("items", 0).Expect(ArrayValue)
+	assert.Equal(t, "simplejson: expected array at items[0], got object", err.Error())
+	te := err.(*TypeError)
+	assert.Equal(t, []interface{}{"items", 0}, te.Path)
+	assert.Equal(t, "object", te.Got)
+
+	// scalars are located from the lookup which returned them
+	err = js.Get("items", 1, "name").Expect(StringValue)
+	assert.Equal(t, "simplejson: expected string at items[1].name, got number", err.Error())
+	err = js.wrap(1).Expect(StringValue)
+	assert.Equal(t, "simplejson: expected string, got number", err.Error())
+	js.TrackParents()
+	err = js.Get("items", 1, "name").Expect(StringValue)
+	assert.Equal(t, "simplejson: expected string at items[1].name, got number", err.Error())
+
+	err = js.Expect(ArrayValue)
+	assert.Equal(t, "simplejson: expected array at (root), got object", err.Error())
+	err = js.Get("missing").Expect(NumberValue)
+	assert.Equal(t, "simplejson: expected number, got null", err.Error())
+	assert.Equal(t, nil, js.Get("missing").Expect(NullValue))
+}
@@ -0,0 +1,79 @@
+package simplejson
+
+// Undo reverts the last `n` recorded modifications of the document,
+// see StartRecording, and returns how many were reverted.
+// Reverted modifications can be replayed by Redo until a new one is recorded.
+func (j *JSON) Undo(n int) int {
+	if j == nil {
+		return 0
+	}
+	d := j.doc
+	if d == nil {
+		return 0
+	}
+	i := 0
+	for ; i < n && len(d.changelog) > 0; i++ {
+		c := d.changelog[len(d.changelog)-1]
+		d.changelog = d.changelog[:len(d.changelog)-1]
+		d.apply(Change{Op: inverse(c.Op), Path: c.Path, Old: c.New, New: c.Old})
+		d.undone = append(d.undone, c)
+	}
+	return i
+}
+
+// Redo replays the last `n` modifications reverted by Undo
+// and returns how many were replayed
+func (j *JSON) Redo(n int) int {
+	if j == nil {
+		return 0
+	}
+	d := j.doc
+	if d == nil {
+		return 0
+	}
+	i := 0
+	for ; i < n && len(d.undone) > 0; i++ {
+		c := d.undone[len(d.undone)-1]
+		d.undone = d.undone[:len(d.undone)-1]
+		d.apply(c)
+		d.changelog = append(d.changelog, c)
+	}
+	return i
+}
+
+func inverse(op ChangeOp) ChangeOp {
+	switch op {
+	case ChangeAdd:
+		return ChangeRemove
+	case ChangeRemove:
+		return ChangeAdd
+	}
+	return op
+}
+
+// apply performs a modification on the document without recording it
+func (d *document) apply(c Change) {
+	recording, undone := d.recording, d.undone
+	d.recording = false
+	defer func() {
+		d.recording, d.undone = recording, undone
+	}()
+
+	if len(c.Path) == 0 {
+		d.root.SetPath(nil, deepCopy(c.New))
+		return
+	}
+	parent, ok := d.root.walk(c.Path[:len(c.Path)-1])
+	if !ok {
+		return
+	}
+	key := c.Path[len(c.Path)-1]
+	switch c.Op {
+	case ChangeRemove:
+		parent.delChild(key)
+	case ChangeAdd:
+		parent.insertChild(key, deepCopy(c.New))
+	default:
+		parent.setChild(key, deepCopy(c.New))
+	}
+}
@@ -0,0 +1,47 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestUndoRedo(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"b":1},"c":"d"}`))
+	assert.Equal(t, nil, err)
+	js.StartRecording()
+
+	js.Get("a").Set("b", 2)
+	js.SetPath([]string{"x", "y"}, []interface{}{1})
+	js.Del("c")
+	assert.Equal(t, `{"a":{"b":2},"x":{"y":[1]}}`, string(mustEncode(t, js)))
+
+	assert.Equal(t, 2, js.Undo(2))
+	assert.Equal(t, `{"a":{"b":2},"c":"d"}`, string(mustEncode(t, js)))
+	assert.Equal(t, 1, len(js.Changes()))
+
+	assert.Equal(t, 5, js.Undo(5)+js.Redo(1)+3)
+	assert.Equal(t, `{"a":{"b":2},"c":"d"}`, string(mustEncode(t, js)))
+
+	assert.Equal(t, 2, js.Redo(5))
+	assert.Equal(t, `{"a":{"b":2},"x":{"y":[1]}}`, string(mustEncode(t, js)))
+
+	// a new modification discards the redo history
+	js.Undo(1)
+	js.Set("z", true)
+	assert.Equal(t, 0, js.Redo(1))
+	assert.Equal(t, 0, New().Undo(1))
+}
+
+func TestUndoArray(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":[1,2,3]}`))
+	assert.Equal(t, nil, err)
+	js.StartRecording()
+
+	js.Get("a").setChild(1, "two")
+	js.Get("a").delChild(0)
+	assert.Equal(t, `{"a":["two",3]}`, string(mustEncode(t, js)))
+
+	js.Undo(2)
+	assert.Equal(t, `{"a":[1,2,3]}`, string(mustEncode(t, js)))
+}
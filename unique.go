@@ -0,0 +1,40 @@
+package simplejson
+
+// UniqueArray modifies the `JSON` array by removing the elements deeply equal to
+// a previous one, keeping the first seen order. Numbers are equal by value.
+func (j *JSON) UniqueArray() {
+	j.uniqueBy(func(elem interface{}) (interface{}, bool) {
+		return elem, true
+	})
+}
+
+// UniqueArrayByKey is like UniqueArray, comparing the values at the dotted path
+// `key` of the elements. Elements missing the key are kept.
+func (j *JSON) UniqueArrayByKey(key string) {
+	j.uniqueBy(func(elem interface{}) (interface{}, bool) {
+		return lookup(elem, dottedBranch(elem, key))
+	})
+}
+
+func (j *JSON) uniqueBy(keyOf func(elem interface{}) (interface{}, bool)) {
+	j.checkWritable()
+	a, ok := j.CheckArray()
+	if !ok {
+		return
+	}
+	seen := make(map[string]bool, len(a))
+	out := make([]interface{}, 0, len(a))
+	for _, elem := range a {
+		if val, ok := keyOf(elem); ok {
+			key, err := canonicalKey(val)
+			if err == nil && seen[key] {
+				continue
+			}
+			seen[key] = err == nil
+		}
+		out = append(out, elem)
+	}
+	if len(out) < len(a) {
+		j.replace(out)
+	}
+}
@@ -0,0 +1,28 @@
+package simplejson
+
+import (
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestUniqueArray(t *testing.T) {
+	js, _ := NewJSON([]byte(`{"a":[1,{"x":[1,2],"y":null},"1",1.0,{"y":null,"x":[1,2]},[1],[1],1e0,null,null]}`))
+	var changes []Change
+	js.OnChange(nil, func(c Change) { changes = append(changes, c) })
+
+	js.Get("a").UniqueArray()
+	b, _ := js.Encode()
+	assert.Equal(t, `{"a":[1,{"x":[1,2],"y":null},"1",[1],null]}`, string(b))
+	assert.Equal(t, 1, len(changes))
+
+	js.Get("a").UniqueArray()
+	assert.Equal(t, 1, len(changes))
+}
+
+func TestUniqueArrayByKey(t *testing.T) {
+	js, _ := NewJSON([]byte(`[{"id":{"n":1},"v":"a"},{"v":"b"},{"id":{"n":2}},{"id":{"n":1.0},"v":"c"},{"v":"d"}]`))
+	js.UniqueArrayByKey("id.n")
+	b, _ := js.Encode()
+	assert.Equal(t, `[{"id":{"n":1},"v":"a"},{"v":"b"},{"id":{"n":2}},{"v":"d"}]`, string(b))
+}
@@ -0,0 +1,112 @@
+package simplejson
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// UTF8Mode sets how invalid UTF-8 in strings is handled
+type UTF8Mode int
+
+const (
+	// UTF8Replace replaces invalid sequences with U+FFFD, as encoding/json does
+	UTF8Replace UTF8Mode = iota
+	// UTF8Reject fails decoding or encoding
+	UTF8Reject
+)
+
+// DecodeUTF8 sets how invalid UTF-8 in the input is handled,
+// it is replaced by default
+func DecodeUTF8(mode UTF8Mode) DecodeOption {
+	return func(c *decodeConfig) {
+		c.utf8 = mode
+		c.sanitizeUTF8 = true
+	}
+}
+
+// EncodeUTF8 sets how invalid UTF-8 in strings of the document is handled,
+// it is replaced by default
+func EncodeUTF8(mode UTF8Mode) EncodeOption {
+	return func(c *encodeConfig) {
+		c.utf8 = mode
+	}
+}
+
+// ASCII escapes every non ASCII character of strings with \u sequences,
+// so the output is pure ASCII
+func ASCII() EncodeOption {
+	return func(c *encodeConfig) {
+		c.ascii = true
+	}
+}
+
+// EncodeASCII is like Encode, with the ASCII option
+func (j *JSON) EncodeASCII() ([]byte, error) {
+	return j.EncodeWith(ASCII())
+}
+
+// checkUTF8 returns an error locating the first invalid UTF-8 sequence of `b`
+func checkUTF8(b []byte) error {
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		if r == utf8.RuneError && size == 1 {
+			return fmt.Errorf("simplejson: invalid UTF-8 at offset %d", i)
+		}
+		i += size
+	}
+	return nil
+}
+
+// sanitizeUTF8 replaces invalid UTF-8 in keys and strings of `data`
+func sanitizeUTF8(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			if !utf8.ValidString(key) {
+				delete(v, key)
+				key = strings.ToValidUTF8(key, "�")
+			}
+			v[key] = sanitizeUTF8(val)
+		}
+	case []interface{}:
+		for i, val := range v {
+			v[i] = sanitizeUTF8(val)
+		}
+	case string:
+		if !utf8.ValidString(v) {
+			return strings.ToValidUTF8(v, "�")
+		}
+	}
+	return data
+}
+
+// escapeASCII rewrites the non ASCII characters of an encoded string
+func escapeASCII(buf *bytes.Buffer, start int) {
+	s := buf.Bytes()[start:]
+	ascii := true
+	for _, b := range s {
+		if b >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return
+	}
+	escaped := make([]byte, 0, len(s)+16)
+	for _, r := range string(s) {
+		switch {
+		case r < utf8.RuneSelf:
+			escaped = append(escaped, byte(r))
+		case r > 0xFFFF:
+			r -= 0x10000
+			escaped = append(escaped, fmt.Sprintf(`\u%04x\u%04x`, 0xD800+(r>>10), 0xDC00+(r&0x3FF))...)
+		default:
+			escaped = append(escaped, fmt.Sprintf(`\u%04x`, r)...)
+		}
+	}
+	buf.Truncate(start)
+	buf.Write(escaped)
+}
@@ -0,0 +1,51 @@
+package simplejson
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestDecodeUTF8(t *testing.T) {
+	raw := "{\"a\xff\":\"b\xc3\x28c\",\"ok\":\"é\"}"
+
+	js, err := NewJSONWith([]byte(raw), DecodeUTF8(UTF8Replace))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "b�(c", js.Get("a�").String())
+
+	_, err = NewJSONWith([]byte(raw), DecodeUTF8(UTF8Reject))
+	assert.Equal(t, "simplejson: invalid UTF-8 at offset 3", err.Error())
+
+	_, err = NewFromReaderWith(strings.NewReader(raw), DecodeUTF8(UTF8Reject))
+	assert.NotEqual(t, nil, err)
+
+	js, err = NewJSONWith([]byte(`{"ok":"é"}`), DecodeUTF8(UTF8Reject))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "é", js.Get("ok").String())
+}
+
+func TestEncodeUTF8(t *testing.T) {
+	js := New()
+	js.Set("s", "a\xffb")
+
+	b, err := js.Encode()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"s":"a�b"}`, string(b))
+
+	_, err = js.EncodeWith(EncodeUTF8(UTF8Reject))
+	assert.Equal(t, `simplejson: invalid UTF-8 in string "a\xffb"`, err.Error())
+}
+
+func TestEncodeASCII(t *testing.T) {
+	js := New()
+	js.Set("café", "naïve 😀 <b>")
+
+	b, err := js.EncodeASCII()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"caf\u00e9":"na\u00efve \ud83d\ude00 \u003cb\u003e"}`, string(b))
+
+	back, err := NewJSON(b)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "naïve 😀 <b>", back.Get("café").String())
+}
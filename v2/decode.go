@@ -0,0 +1,223 @@
+package simplejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// document holds the options and key order shared by a document and its views
+type document struct {
+	opts options
+	// root is the value the document was created with, views being obtained
+	// from it
+	root *JSON
+	// order holds the keys of objects in document order, by object identity,
+	// see setOrder
+	order    map[uintptr]objectOrder
+	orderMax int
+}
+
+// objectOrder holds the keys of an object in order, along with the object
+// itself, which keeps it alive so its address can't be reused by another one
+type objectOrder struct {
+	m    map[string]interface{}
+	keys []string
+}
+
+// minOrder is the number of ordered objects above which the document
+// first forgets the ones no longer part of it
+const minOrder = 64
+
+func newDocument(opts []Option) *document {
+	return &document{opts: newOptions(opts)}
+}
+
+// wrap returns the root of the document, holding `data`
+func (d *document) wrap(data interface{}) *JSON {
+	d.root = &JSON{data: data, doc: d}
+	return d.root
+}
+
+// decode reads a single value from `dec`
+func (d *document) decode(dec *json.Decoder) (interface{}, error) {
+	if d.opts.useNumber {
+		dec.UseNumber()
+	}
+	if !d.opts.orderedKeys && d.opts.maxDepth == 0 {
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return nil, &Error{Op: "decode", Err: err}
+		}
+		return v, nil
+	}
+	return d.parse(dec, nil)
+}
+
+// parse reads a value token by token, recording key order and checking depth
+func (d *document) parse(dec *json.Decoder, path []interface{}) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, &Error{Op: "decode", Path: path, Err: err}
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+	if d.opts.maxDepth > 0 && len(path) >= d.opts.maxDepth {
+		return nil, &Error{Op: "decode", Path: path, Err: ErrMaxDepth}
+	}
+
+	switch delim {
+	case '{':
+		m := map[string]interface{}{}
+		var keys []string
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, &Error{Op: "decode", Path: path, Err: err}
+			}
+			key := tok.(string)
+			val, err := d.parse(dec, append(path[:len(path):len(path)], key))
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := m[key]; !dup {
+				keys = append(keys, key)
+			}
+			m[key] = val
+		}
+		d.setOrder(m, keys)
+		_, err = dec.Token()
+		return m, err
+	default:
+		a := []interface{}{}
+		for dec.More() {
+			val, err := d.parse(dec, append(path[:len(path):len(path)], len(a)))
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, val)
+		}
+		_, err = dec.Token()
+		return a, err
+	}
+}
+
+// decodeBytes decodes `body`, which must hold a single value
+func (d *document) decodeBytes(body []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	v, err := d.decode(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, &Error{Op: "decode", Err: errTrailingData}
+	}
+	return v, nil
+}
+
+var errTrailingData = errors.New("invalid data after top-level value")
+
+// setOrder records the keys of `m` in order, when keys are ordered
+func (d *document) setOrder(m map[string]interface{}, keys []string) {
+	if !d.opts.orderedKeys {
+		return
+	}
+	if d.order == nil {
+		d.order = map[uintptr]objectOrder{}
+	}
+	d.order[identity(m)] = objectOrder{m: m, keys: keys}
+}
+
+// compact forgets the objects no longer reachable from the root each time
+// their number doubles, which bounds them by the size of the document. It is
+// only called by modifications, decoded objects being recorded before they
+// are part of the document
+func (d *document) compact() {
+	if len(d.order) < d.orderMax || d.root == nil {
+		return
+	}
+	order := make(map[uintptr]objectOrder)
+	d.reachable(d.root.data, order)
+	d.order = order
+	d.orderMax = 2 * len(order)
+	if d.orderMax < minOrder {
+		d.orderMax = minOrder
+	}
+}
+
+// reachable adds the recorded orders of the objects within `data` to `order`
+func (d *document) reachable(data interface{}, order map[uintptr]objectOrder) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if o, ok := d.order[identity(v)]; ok {
+			order[identity(v)] = o
+		}
+		for _, val := range v {
+			d.reachable(val, order)
+		}
+	case []interface{}:
+		for _, val := range v {
+			d.reachable(val, order)
+		}
+	}
+}
+
+// orderOf returns the recorded keys of `m`
+func (d *document) orderOf(m map[string]interface{}) []string {
+	return d.order[identity(m)].keys
+}
+
+// addKey records `key` as the last key of `m`
+func (d *document) addKey(m map[string]interface{}, key string) {
+	if d.opts.orderedKeys {
+		keys := append(d.orderOf(m), key)
+		d.compact()
+		d.setOrder(m, keys)
+	}
+}
+
+// removeKey forgets `key` from the keys of `m`
+func (d *document) removeKey(m map[string]interface{}, key string) {
+	if !d.opts.orderedKeys {
+		return
+	}
+	keys := d.orderOf(m)
+	for i, k := range keys {
+		if k == key {
+			keys = append(keys[:i:i], keys[i+1:]...)
+			d.compact()
+			d.setOrder(m, keys)
+			return
+		}
+	}
+}
+
+// keys returns the keys of `m` in document order when keys are ordered,
+// keys of unknown order are sorted and listed last
+func (d *document) keys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	seen := make(map[string]bool, len(m))
+	for _, k := range d.orderOf(m) {
+		if _, ok := m[k]; ok && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	n := len(keys)
+	for k := range m {
+		if !seen[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys[n:])
+	return keys
+}
+
+func identity(m map[string]interface{}) uintptr {
+	return reflect.ValueOf(m).Pointer()
+}
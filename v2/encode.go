@@ -0,0 +1,115 @@
+package simplejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// Encode returns the document encoded as JSON,
+// objects list their keys in document order when keys are ordered
+func (j *JSON) Encode() ([]byte, error) {
+	if !j.doc.opts.orderedKeys {
+		return json.Marshal(j.data)
+	}
+	var buf bytes.Buffer
+	if err := j.doc.encode(&buf, j.data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodePretty is like Encode, with indentation
+func (j *JSON) EncodePretty() ([]byte, error) {
+	b, err := j.Encode()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, b, "", "  "); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (j *JSON) MarshalJSON() ([]byte, error) {
+	return j.Encode()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface,
+// options of the document are kept
+func (j *JSON) UnmarshalJSON(p []byte) error {
+	if j.doc == nil {
+		j.doc = newDocument(nil)
+		j.doc.root = j
+	}
+	v, err := j.doc.decodeBytes(p)
+	if err != nil {
+		return err
+	}
+	return j.replace(v)
+}
+
+func (d *document) encode(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		buf.WriteByte('{')
+		for i, k := range d.keys(v) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(k)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := d.encode(buf, v[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, elem := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := d.encode(buf, elem); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+	return nil
+}
+
+// SortKeys sorts the keys of every object of the document, so that Keys and
+// Encode list them sorted. It is only needed for documents with ordered
+// keys, others always list keys sorted.
+func (j *JSON) SortKeys() {
+	if j.doc.opts.orderedKeys {
+		j.doc.sortKeys(j.data)
+	}
+}
+
+func (d *document) sortKeys(data interface{}) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key, val := range v {
+			keys = append(keys, key)
+			d.sortKeys(val)
+		}
+		sort.Strings(keys)
+		d.setOrder(v, keys)
+	case []interface{}:
+		for _, val := range v {
+			d.sortKeys(val)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package simplejson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when a branch does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrType is returned when a value is not of the requested type
+	ErrType = errors.New("wrong type")
+	// ErrMaxDepth is returned when a document nests deeper than WithMaxDepth allows
+	ErrMaxDepth = errors.New("maximum depth exceeded")
+)
+
+// Error reports a failed operation on the value at Path,
+// it wraps one of the package errors or a decoding error
+type Error struct {
+	Op   string
+	Path []interface{}
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("simplejson: %s %s: %v", e.Op, formatPath(e.Path), e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// formatPath returns a human readable representation of a branch,
+// such as `items[3].name`
+func formatPath(branch []interface{}) string {
+	if len(branch) == 0 {
+		return "(root)"
+	}
+	var b strings.Builder
+	for _, p := range branch {
+		switch k := p.(type) {
+		case string:
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(k)
+		case int:
+			b.WriteString("[" + strconv.Itoa(k) + "]")
+		default:
+			fmt.Fprintf(&b, "[%v]", k)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package simplejson
+
+// Option configures how a document is decoded and encoded
+type Option func(*options)
+
+type options struct {
+	useNumber   bool
+	orderedKeys bool
+	maxDepth    int
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithUseNumber decodes numbers as json.Number instead of float64,
+// preserving integers that do not fit a float64 exactly
+func WithUseNumber() Option {
+	return func(o *options) {
+		o.useNumber = true
+	}
+}
+
+// WithOrderedKeys keeps the keys of objects in document order,
+// Keys and Encode then list keys in that order, new keys being last
+func WithOrderedKeys() Option {
+	return func(o *options) {
+		o.orderedKeys = true
+	}
+}
+
+// WithMaxDepth rejects documents nesting objects and arrays deeper than `n`,
+// a top level object or array having a depth of 1. Zero means no limit.
+func WithMaxDepth(n int) Option {
+	return func(o *options) {
+		o.maxDepth = n
+	}
+}
@@ -0,0 +1,407 @@
+// Package simplejson is the second major version of
+// github.com/brunotm/go-simplejson.
+//
+// Documents are configured once through functional options when they are
+// created, and every operation that can fail reports why with an error
+// instead of silently doing nothing or panicking:
+//
+//	js, err := simplejson.NewJSON(body, simplejson.WithUseNumber(), simplejson.WithOrderedKeys())
+//	if err != nil { ... }
+//	port, err := js.Get("server", "port").Int()
+//	err = js.SetPath([]interface{}{"server", "tls", "enabled"}, true)
+//
+// Errors are of type *Error and wrap ErrNotFound, ErrType or ErrMaxDepth,
+// which can be tested with errors.Is. The first version of the package is
+// unchanged, documents can be converted between both with FromV1 and V1.
+package simplejson
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"reflect"
+
+	v1 "github.com/brunotm/go-simplejson"
+)
+
+// JSON wraps an arbitrary decoded JSON value.
+//
+// A JSON, and every JSON obtained from it through Get, shares the same
+// underlying maps and arrays. It is not safe for concurrent use when any
+// goroutine modifies the document.
+type JSON struct {
+	data interface{}
+	doc  *document
+	// parent and key locate the value within its document
+	parent *JSON
+	key    interface{}
+	// absent is set on views of values missing from the document
+	absent bool
+}
+
+// New returns a new, empty object
+func New(opts ...Option) *JSON {
+	return newDocument(opts).wrap(map[string]interface{}{})
+}
+
+// NewJSON returns a document decoded from `body`,
+// which must hold a single JSON value
+func NewJSON(body []byte, opts ...Option) (*JSON, error) {
+	doc := newDocument(opts)
+	v, err := doc.decodeBytes(body)
+	if err != nil {
+		return nil, err
+	}
+	return doc.wrap(v), nil
+}
+
+// NewFromReader returns a document decoded from the next JSON value in `r`
+func NewFromReader(r io.Reader, opts ...Option) (*JSON, error) {
+	doc := newDocument(opts)
+	v, err := doc.decode(json.NewDecoder(r))
+	if err != nil {
+		return nil, err
+	}
+	return doc.wrap(v), nil
+}
+
+// FromV1 returns a document sharing the data of a version 1 document
+func FromV1(js *v1.JSON, opts ...Option) *JSON {
+	return newDocument(opts).wrap(js.Interface())
+}
+
+// V1 returns a version 1 document sharing the data of `j`
+func (j *JSON) V1() *v1.JSON {
+	js := v1.New()
+	js.SetPath(nil, j.data)
+	return js
+}
+
+// Interface returns the underlying data
+func (j *JSON) Interface() interface{} {
+	return j.data
+}
+
+// Exists reports whether the value was found in the document
+func (j *JSON) Exists() bool {
+	return !j.absent
+}
+
+// Path returns the branch locating the value within its document
+func (j *JSON) Path() []interface{} {
+	if j.parent == nil {
+		return []interface{}{}
+	}
+	return append(j.parent.Path(), j.key)
+}
+
+// Get returns the value at `branch`, made of object keys and array indexes,
+// negative indexes counting back from the end of arrays.
+//
+// Get always returns a valid JSON allowing for chained calls, when the value
+// does not exist the methods of the returned JSON report ErrNotFound.
+func (j *JSON) Get(branch ...interface{}) *JSON {
+	curr := j
+	for _, p := range branch {
+		if curr.absent {
+			curr = &JSON{doc: j.doc, parent: curr, key: p, absent: true}
+			continue
+		}
+		data, key, ok := child(curr.data, p)
+		curr = &JSON{data: data, doc: j.doc, parent: curr, key: key, absent: !ok}
+	}
+	return curr
+}
+
+// Lookup is like Get, returning an error if the value does not exist
+func (j *JSON) Lookup(branch ...interface{}) (*JSON, error) {
+	jin := j.Get(branch...)
+	if err := jin.check("get"); err != nil {
+		return nil, err
+	}
+	return jin, nil
+}
+
+// child returns the value at `key` of an object or index of an array
+func child(data interface{}, key interface{}) (interface{}, interface{}, bool) {
+	switch k := key.(type) {
+	case string:
+		if m, ok := data.(map[string]interface{}); ok {
+			v, ok := m[k]
+			return v, k, ok
+		}
+	case int:
+		if a, ok := data.([]interface{}); ok {
+			if k < 0 {
+				k += len(a)
+			}
+			if k >= 0 && k < len(a) {
+				return a[k], k, true
+			}
+		}
+	}
+	return nil, key, false
+}
+
+// check returns an error if the value does not exist
+func (j *JSON) check(op string) error {
+	if !j.absent {
+		return nil
+	}
+	// report the first missing element of the branch
+	missing := j
+	for missing.parent != nil && missing.parent.absent {
+		missing = missing.parent
+	}
+	return &Error{Op: op, Path: missing.Path(), Err: ErrNotFound}
+}
+
+func (j *JSON) typeError(op string) error {
+	if err := j.check(op); err != nil {
+		return err
+	}
+	return &Error{Op: op, Path: j.Path(), Err: ErrType}
+}
+
+// Keys returns the keys of an object, in document order when keys are
+// ordered and sorted otherwise
+func (j *JSON) Keys() ([]string, error) {
+	m, err := j.Map()
+	if err != nil {
+		return nil, err
+	}
+	return j.doc.keys(m), nil
+}
+
+// Len returns the number of elements of an array or keys of an object
+func (j *JSON) Len() (int, error) {
+	switch v := j.data.(type) {
+	case map[string]interface{}:
+		return len(v), nil
+	case []interface{}:
+		return len(v), nil
+	}
+	return 0, j.typeError("len")
+}
+
+// Map returns the value as an object
+func (j *JSON) Map() (map[string]interface{}, error) {
+	if m, ok := j.data.(map[string]interface{}); ok {
+		return m, nil
+	}
+	return nil, j.typeError("map")
+}
+
+// Array returns the value as an array
+func (j *JSON) Array() ([]interface{}, error) {
+	if a, ok := j.data.([]interface{}); ok {
+		return a, nil
+	}
+	return nil, j.typeError("array")
+}
+
+// String returns the value as a string
+func (j *JSON) String() (string, error) {
+	if s, ok := j.data.(string); ok {
+		return s, nil
+	}
+	return "", j.typeError("string")
+}
+
+// Bool returns the value as a bool
+func (j *JSON) Bool() (bool, error) {
+	if b, ok := j.data.(bool); ok {
+		return b, nil
+	}
+	return false, j.typeError("bool")
+}
+
+// Float64 returns the value as a float64
+func (j *JSON) Float64() (float64, error) {
+	switch v := j.data.(type) {
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f, nil
+		}
+	case float32, float64:
+		return reflect.ValueOf(v).Float(), nil
+	case int, int8, int16, int32, int64:
+		return float64(reflect.ValueOf(v).Int()), nil
+	case uint, uint8, uint16, uint32, uint64:
+		return float64(reflect.ValueOf(v).Uint()), nil
+	}
+	return 0, j.typeError("float64")
+}
+
+// Int64 returns the value as an int64, numbers with a fractional part
+// or out of range are rejected
+func (j *JSON) Int64() (int64, error) {
+	switch v := j.data.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+	case float32, float64:
+		f := reflect.ValueOf(v).Float()
+		if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return int64(f), nil
+		}
+	case int, int8, int16, int32, int64:
+		return reflect.ValueOf(v).Int(), nil
+	case uint, uint8, uint16, uint32, uint64:
+		if u := reflect.ValueOf(v).Uint(); u <= math.MaxInt64 {
+			return int64(u), nil
+		}
+	}
+	return 0, j.typeError("int64")
+}
+
+// Int is like Int64, for int
+func (j *JSON) Int() (int, error) {
+	i, err := j.Int64()
+	if err != nil || int64(int(i)) != i {
+		return 0, j.typeError("int")
+	}
+	return int(i), nil
+}
+
+// Uint64 is like Int64, for uint64
+func (j *JSON) Uint64() (uint64, error) {
+	switch v := j.data.(type) {
+	case json.Number:
+		var u uint64
+		if err := json.Unmarshal([]byte(v), &u); err == nil {
+			return u, nil
+		}
+	case uint, uint8, uint16, uint32, uint64:
+		return reflect.ValueOf(v).Uint(), nil
+	default:
+		if i, err := j.Int64(); err == nil && i >= 0 {
+			return uint64(i), nil
+		}
+	}
+	return 0, j.typeError("uint64")
+}
+
+// replace sets the value of `j`, updating its parent container
+func (j *JSON) replace(data interface{}) error {
+	if err := j.check("set"); err != nil {
+		return err
+	}
+	j.data = data
+	switch c := j.parent.dataOrNil().(type) {
+	case map[string]interface{}:
+		c[j.key.(string)] = data
+	case []interface{}:
+		c[j.key.(int)] = data
+	}
+	return nil
+}
+
+func (j *JSON) dataOrNil() interface{} {
+	if j == nil {
+		return nil
+	}
+	return j.data
+}
+
+// Set sets `key` of an object to `val`
+func (j *JSON) Set(key string, val interface{}) error {
+	m, err := j.Map()
+	if err != nil {
+		return err
+	}
+	if _, ok := m[key]; !ok {
+		j.doc.addKey(m, key)
+	}
+	m[key] = val
+	return nil
+}
+
+// Delete removes `key` from an object, it is an error if it does not exist
+func (j *JSON) Delete(key string) error {
+	m, err := j.Map()
+	if err != nil {
+		return err
+	}
+	if _, ok := m[key]; !ok {
+		return &Error{Op: "delete", Path: append(j.Path(), key), Err: ErrNotFound}
+	}
+	delete(m, key)
+	j.doc.removeKey(m, key)
+	return nil
+}
+
+// SetPath sets the value at `branch`, creating objects for missing keys.
+// Array indexes must address existing elements, and values along the
+// branch are never replaced by objects: both are reported as errors.
+// An empty branch replaces the value of `j`.
+func (j *JSON) SetPath(branch []interface{}, val interface{}) error {
+	if len(branch) == 0 {
+		return j.replace(val)
+	}
+	curr := j
+	for _, p := range branch[:len(branch)-1] {
+		next := curr.Get(p)
+		if next.absent {
+			key, ok := p.(string)
+			if !ok {
+				return next.check("set")
+			}
+			if err := curr.Set(key, map[string]interface{}{}); err != nil {
+				return err
+			}
+			next = curr.Get(key)
+		}
+		curr = next
+	}
+	switch k := branch[len(branch)-1].(type) {
+	case string:
+		return curr.Set(k, val)
+	case int:
+		return curr.SetIndex(k, val)
+	}
+	return &Error{Op: "set", Path: append(j.Path(), branch...), Err: ErrType}
+}
+
+// SetIndex sets the element at `index` of an array,
+// negative indexes counting back from the end of the array
+func (j *JSON) SetIndex(index int, val interface{}) error {
+	a, err := j.Array()
+	if err != nil {
+		return err
+	}
+	if index < 0 {
+		index += len(a)
+	}
+	if index < 0 || index >= len(a) {
+		return &Error{Op: "set", Path: append(j.Path(), index), Err: ErrNotFound}
+	}
+	a[index] = val
+	return nil
+}
+
+// RemoveIndex removes the element at `index` of an array,
+// negative indexes counting back from the end of the array
+func (j *JSON) RemoveIndex(index int) error {
+	a, err := j.Array()
+	if err != nil {
+		return err
+	}
+	if index < 0 {
+		index += len(a)
+	}
+	if index < 0 || index >= len(a) {
+		return &Error{Op: "remove", Path: append(j.Path(), index), Err: ErrNotFound}
+	}
+	return j.replace(append(a[:index:index], a[index+1:]...))
+}
+
+// Append appends `vals` to an array
+func (j *JSON) Append(vals ...interface{}) error {
+	a, err := j.Array()
+	if err != nil {
+		return err
+	}
+	return j.replace(append(a, vals...))
+}
@@ -0,0 +1,154 @@
+package simplejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	v1 "github.com/brunotm/go-simplejson"
+)
+
+func TestGet(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"b":[1,2.5,"x",true]},"big":9223372036854775807}`), WithUseNumber())
+	assert.Equal(t, nil, err)
+
+	i, err := js.Get("a", "b", 0).Int()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, i)
+
+	f, _ := js.Get("a", "b", 1).Float64()
+	assert.Equal(t, 2.5, f)
+	s, _ := js.Get("a", "b", -2).String()
+	assert.Equal(t, "x", s)
+	b, _ := js.Get("a", "b", 3).Bool()
+	assert.Equal(t, true, b)
+	big, _ := js.Get("big").Int64()
+	assert.Equal(t, int64(9223372036854775807), big)
+
+	_, err = js.Get("a", "b", 1).Int()
+	assert.Equal(t, "simplejson: int a.b[1]: wrong type", err.Error())
+	assert.Equal(t, true, errors.Is(err, ErrType))
+
+	_, err = js.Get("a", "c", "d").String()
+	assert.Equal(t, "simplejson: string a.c: not found", err.Error())
+	assert.Equal(t, true, errors.Is(err, ErrNotFound))
+	assert.Equal(t, false, js.Get("a", "c").Exists())
+
+	_, err = js.Lookup("a", "b", 9)
+	assert.Equal(t, "simplejson: get a.b[9]: not found", err.Error())
+	found, err := js.Lookup("a", "b")
+	assert.Equal(t, nil, err)
+	n, _ := found.Len()
+	assert.Equal(t, 4, n)
+	assert.Equal(t, []interface{}{"a", "b"}, found.Path())
+}
+
+func TestMutators(t *testing.T) {
+	js := New()
+	assert.Equal(t, nil, js.SetPath([]interface{}{"server", "hosts"}, []interface{}{"a"}))
+	assert.Equal(t, nil, js.Get("server", "hosts").Append("b", "c"))
+	assert.Equal(t, nil, js.Get("server", "hosts").SetIndex(-1, "z"))
+	assert.Equal(t, nil, js.Get("server", "hosts").RemoveIndex(0))
+	assert.Equal(t, nil, js.SetPath([]interface{}{"server", "hosts", 0}, "y"))
+	assert.Equal(t, nil, js.Get("server").Set("port", 80))
+
+	b, _ := js.Encode()
+	assert.Equal(t, `{"server":{"hosts":["y","z"],"port":80}}`, string(b))
+
+	err := js.SetPath([]interface{}{"server", "port", "x"}, 1)
+	assert.Equal(t, "simplejson: map server.port: wrong type", err.Error())
+	err = js.SetPath([]interface{}{"server", "hosts", 5, "x"}, 1)
+	assert.Equal(t, "simplejson: set server.hosts[5]: not found", err.Error())
+	err = js.Get("server", "hosts").SetIndex(2, 1)
+	assert.Equal(t, "simplejson: set server.hosts[2]: not found", err.Error())
+	err = js.Get("missing").Set("a", 1)
+	assert.Equal(t, "simplejson: map missing: not found", err.Error())
+	err = js.Get("server").Append(1)
+	assert.Equal(t, "simplejson: array server: wrong type", err.Error())
+	err = js.Delete("missing")
+	assert.Equal(t, "simplejson: delete missing: not found", err.Error())
+	assert.Equal(t, nil, js.Get("server").Delete("port"))
+
+	assert.Equal(t, nil, js.SetPath(nil, "replaced"))
+	s, _ := js.String()
+	assert.Equal(t, "replaced", s)
+}
+
+func TestOrderedKeys(t *testing.T) {
+	js, err := NewJSON([]byte(`{"z":1,"a":{"y":true,"b":false},"m":[{"q":1,"p":2}]}`), WithOrderedKeys())
+	assert.Equal(t, nil, err)
+
+	keys, _ := js.Keys()
+	assert.Equal(t, []string{"z", "a", "m"}, keys)
+
+	js.Set("c", 3)
+	js.Delete("z")
+	js.Set("z", 4)
+	b, _ := js.Encode()
+	assert.Equal(t, `{"a":{"y":true,"b":false},"m":[{"q":1,"p":2}],"c":3,"z":4}`, string(b))
+
+	unordered, _ := NewJSON([]byte(`{"z":1,"a":2}`))
+	keys, _ = unordered.Keys()
+	assert.Equal(t, []string{"a", "z"}, keys)
+
+	pretty, _ := js.Get("a").EncodePretty()
+	assert.Equal(t, "{\n  \"y\": true,\n  \"b\": false\n}", string(pretty))
+
+	js.SortKeys()
+	b, _ = js.Encode()
+	assert.Equal(t, `{"a":{"b":false,"y":true},"c":3,"m":[{"p":2,"q":1}],"z":4}`, string(b))
+}
+
+func TestOrderedKeysBounded(t *testing.T) {
+	js, err := NewJSON([]byte(`{"a":{"y":1,"b":2}}`), WithOrderedKeys())
+	assert.Equal(t, nil, err)
+	for i := 0; i < 1000; i++ {
+		js.Set("k", map[string]interface{}{})
+		js.Get("k").Set("z", i)
+		js.Get("k").Set("a", i)
+	}
+	// objects replaced by later writes are forgotten
+	assert.T(t, len(js.doc.order) <= 2*minOrder)
+	keys, _ := js.Get("k").Keys()
+	assert.Equal(t, []string{"z", "a"}, keys)
+	b, _ := js.Encode()
+	assert.Equal(t, `{"a":{"y":1,"b":2},"k":{"z":999,"a":999}}`, string(b))
+}
+
+func TestMaxDepth(t *testing.T) {
+	_, err := NewJSON([]byte(`{"a":[{"b":1}]}`), WithMaxDepth(3))
+	assert.Equal(t, nil, err)
+
+	_, err = NewJSON([]byte(`{"a":[{"b":[]}]}`), WithMaxDepth(3))
+	assert.Equal(t, "simplejson: decode a[0].b: maximum depth exceeded", err.Error())
+	assert.Equal(t, true, errors.Is(err, ErrMaxDepth))
+
+	_, err = NewJSON([]byte(`{} {}`))
+	assert.NotEqual(t, nil, err)
+	_, err = NewJSON([]byte(`{"a":`), WithMaxDepth(3))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestInterop(t *testing.T) {
+	js, err := NewFromReader(bytes.NewBufferString(`{"a":1}`), WithUseNumber())
+	assert.Equal(t, nil, err)
+
+	old := js.V1()
+	assert.Equal(t, json.Number("1"), old.Get("a").Interface())
+	old.Set("b", 2)
+	assert.Equal(t, true, js.Get("b").Exists())
+
+	back := FromV1(v1.New())
+	assert.Equal(t, nil, back.Set("c", 3))
+
+	val := new(struct {
+		Params *JSON `json:"params"`
+	})
+	assert.Equal(t, nil, json.Unmarshal([]byte(`{"params":{"x":"y"}}`), val))
+	s, _ := val.Params.Get("x").String()
+	assert.Equal(t, "y", s)
+	p, _ := json.Marshal(val)
+	assert.Equal(t, `{"params":{"x":"y"}}`, string(p))
+}
@@ -0,0 +1,66 @@
+package simplejson
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// WatchInterval is how often Watch checks the watched file for changes
+var WatchInterval = time.Second
+
+// Watch loads the JSON document at `path` and reloads it every time the file
+// changes, calling `onChange` with every new version, frozen so it can be
+// safely shared, or with the error that prevented loading it.
+// The file is polled every WatchInterval. Watch blocks until `ctx` is done
+// and returns its error.
+func Watch(ctx context.Context, path string, onChange func(*JSON, error)) error {
+	var (
+		last    os.FileInfo
+		lastErr error
+	)
+
+	check := func() {
+		info, err := os.Stat(path)
+		if err == nil && last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+			return
+		}
+
+		var js *JSON
+		if err == nil {
+			js, err = loadFile(path)
+		}
+		if err != nil {
+			last = nil
+			if lastErr == nil || err.Error() != lastErr.Error() {
+				onChange(nil, err)
+			}
+			lastErr = err
+			return
+		}
+		last, lastErr = info, nil
+		onChange(js.Freeze(), nil)
+	}
+
+	check()
+	ticker := time.NewTicker(WatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+// loadFile decodes the JSON document at `path`
+func loadFile(path string) (*JSON, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewFromReader(f)
+}
@@ -0,0 +1,60 @@
+package simplejson
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bmizerany/assert"
+)
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "simplejson")
+	assert.Equal(t, nil, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config.json")
+	assert.Equal(t, nil, ioutil.WriteFile(file, []byte(`{"v":1}`), 0600))
+
+	defer func(d time.Duration) { WatchInterval = d }(WatchInterval)
+	WatchInterval = 5 * time.Millisecond
+
+	type event struct {
+		js  *JSON
+		err error
+	}
+	events := make(chan event, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- Watch(ctx, file, func(js *JSON, err error) { events <- event{js, err} })
+	}()
+
+	next := func() event {
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for reload")
+		}
+		return event{}
+	}
+
+	e := next()
+	assert.Equal(t, nil, e.err)
+	assert.Equal(t, 1, e.js.Get("v").Int())
+	assert.Equal(t, true, e.js.IsFrozen())
+
+	assert.Equal(t, nil, ioutil.WriteFile(file, []byte(`{"v":22}`), 0600))
+	e = next()
+	assert.Equal(t, 22, e.js.Get("v").Int())
+
+	assert.Equal(t, nil, ioutil.WriteFile(file, []byte(`{"v":`), 0600))
+	e = next()
+	assert.NotEqual(t, nil, e.err)
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}